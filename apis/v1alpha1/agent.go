@@ -25,6 +25,13 @@ import (
 // Contains details about an agent.
 type AgentSpec struct {
 
+	// Action groups reconciled together with the agent. When set, action groups
+	// on the agent's DRAFT version that are not listed here are deleted; leave
+	// it unset to manage action groups separately.
+	ActionGroups []*InlineActionGroup `json:"actionGroups,omitempty"`
+	// The name of the AgentClass whose defaults apply to the agent. The default
+	// AgentClass applies when it is unset.
+	AgentClassName *string `json:"agentClassName,omitempty"`
 	// The agent's collaboration role.
 	AgentCollaboration *string `json:"agentCollaboration,omitempty"`
 	// A name for the agent that you create.
@@ -91,6 +98,14 @@ type AgentSpec struct {
 	// Instructions that tell the agent what it should do and how it should interact
 	// with users.
 	Instruction *string `json:"instruction,omitempty"`
+	// Knowledge bases associated with the agent. When set, knowledge bases
+	// associated with the agent's DRAFT version that are not listed here are
+	// disassociated; leave it unset to manage associations separately.
+	KnowledgeBases []*InlineKnowledgeBase `json:"knowledgeBases,omitempty"`
+	// The maintenance window in which the changes that prepare the agent again
+	// are applied. Tag changes and changes to an agent that isn't PREPARED are
+	// applied right away. Leave it unset to apply every change right away.
+	MaintenanceWindow *MaintenanceWindow `json:"maintenanceWindow,omitempty"`
 	// Contains the details of the memory configured for the agent.
 	MemoryConfiguration *MemoryConfiguration `json:"memoryConfiguration,omitempty"`
 	// Specifies the type of orchestration strategy for the agent. This is set to
@@ -117,6 +132,10 @@ type AgentStatus struct {
 	// resource
 	// +kubebuilder:validation:Optional
 	Conditions []*ackv1alpha1.Condition `json:"conditions"`
+	// The AgentClass whose defaults were applied to the agent, with the spec
+	// fields they set.
+	// +kubebuilder:validation:Optional
+	AgentClass *AppliedAgentClass `json:"agentClass,omitempty"`
 	// The unique identifier of the agent.
 	//
 	// Regex Pattern: `^[0-9a-zA-Z]{10}$`
@@ -159,6 +178,25 @@ type AgentStatus struct {
 	// Contains reasons that the agent-related API that you invoked failed.
 	// +kubebuilder:validation:Optional
 	FailureReasons []*string `json:"failureReasons,omitempty"`
+	// The keys of the tags the agent inherited from its namespace or from the
+	// cluster default tags, which aren't copied back to its spec.
+	// +kubebuilder:validation:Optional
+	InheritedTagKeys []*string `json:"inheritedTagKeys,omitempty"`
+	// The ID of the cluster owning the agent, from its
+	// bedrockagent.services.k8s.aws/owner-cluster tag. Controllers with another
+	// cluster ID leave the agent alone unless they take it over with the
+	// bedrockagent.services.k8s.aws/take-over annotation.
+	// +kubebuilder:validation:Optional
+	OwnerCluster *string `json:"ownerCluster,omitempty"`
+	// The time at which the reconciliation of the agent was paused with the
+	// bedrockagent.services.k8s.aws/paused annotation.
+	// +kubebuilder:validation:Optional
+	PausedAt *metav1.Time `json:"pausedAt,omitempty"`
+	// The changes the controller would make to the agent, written instead of
+	// applying them when the agent is in dry-run mode, or until they are
+	// approved when it is in approval mode.
+	// +kubebuilder:validation:Optional
+	Plan *string `json:"plan,omitempty"`
 	// The time at which the agent was last prepared.
 	// +kubebuilder:validation:Optional
 	PreparedAt *metav1.Time `json:"preparedAt,omitempty"`
@@ -166,6 +204,9 @@ type AgentStatus struct {
 	// to succeed.
 	// +kubebuilder:validation:Optional
 	RecommendedActions []*string `json:"recommendedActions,omitempty"`
+	// The specs the agent was last successfully prepared with, oldest first.
+	// +kubebuilder:validation:Optional
+	Revisions []*AgentRevision `json:"revisions,omitempty"`
 	// The time at which the agent was last updated.
 	// +kubebuilder:validation:Optional
 	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
@@ -174,6 +215,7 @@ type AgentStatus struct {
 // Agent is the Schema for the Agents API
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 type Agent struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,110 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// InlineActionGroup is an action group that is created, updated and deleted
+// together with the Agent that declares it. Action groups are matched by
+// actionGroupName.
+type InlineActionGroup struct {
+	// The Lambda function or custom control method that carries out the actions
+	// of the action group.
+	ActionGroupExecutor *InlineActionGroupExecutor `json:"actionGroupExecutor,omitempty"`
+	// The name of the action group.
+	//
+	// Regex Pattern: `^([0-9a-zA-Z][_-]?){1,100}$`
+	// +kubebuilder:validation:Required
+	ActionGroupName *string `json:"actionGroupName"`
+	// Whether the action group is available for the agent to invoke. Defaults
+	// to ENABLED.
+	ActionGroupState *string `json:"actionGroupState,omitempty"`
+	// The OpenAPI schema describing the API operations of the action group.
+	// Mutually exclusive with functionSchema.
+	APISchema *InlineAPISchema `json:"apiSchema,omitempty"`
+	// A description of the action group.
+	Description *string `json:"description,omitempty"`
+	// The functions the agent can elicit from the user. Mutually exclusive with
+	// apiSchema.
+	FunctionSchema *InlineFunctionSchema `json:"functionSchema,omitempty"`
+	// Set to a built-in signature such as AMAZON.UserInput or
+	// AMAZON.CodeInterpreter to add a built-in action group. Built-in action
+	// groups take no executor or schema.
+	ParentActionGroupSignature *string `json:"parentActionGroupSignature,omitempty"`
+}
+
+// InlineActionGroupExecutor specifies how the actions of an inline action
+// group are carried out. Exactly one member must be set.
+type InlineActionGroupExecutor struct {
+	// Set to RETURN_CONTROL to return the elicited information to the caller
+	// instead of invoking a Lambda function.
+	CustomControl *string `json:"customControl,omitempty"`
+	// The ARN of the Lambda function carrying out the actions.
+	Lambda *string `json:"lambda,omitempty"`
+}
+
+// InlineAPISchema holds the OpenAPI schema of an inline action group, either
+// inline or stored in S3. Exactly one member must be set.
+type InlineAPISchema struct {
+	// The JSON or YAML OpenAPI schema document.
+	Payload *string `json:"payload,omitempty"`
+	// The S3 object containing the OpenAPI schema document.
+	S3 *InlineS3Identifier `json:"s3,omitempty"`
+}
+
+// InlineS3Identifier is the location of an S3 object.
+type InlineS3Identifier struct {
+	S3BucketName *string `json:"s3BucketName,omitempty"`
+	S3ObjectKey  *string `json:"s3ObjectKey,omitempty"`
+}
+
+// InlineFunctionSchema lists the functions of an inline action group.
+type InlineFunctionSchema struct {
+	Functions []*InlineFunction `json:"functions,omitempty"`
+}
+
+// InlineFunction is a function the agent can elicit from the user.
+type InlineFunction struct {
+	Description *string `json:"description,omitempty"`
+	// +kubebuilder:validation:Required
+	Name       *string                           `json:"name"`
+	Parameters map[string]*InlineParameterDetail `json:"parameters,omitempty"`
+	// Whether the user must confirm before the function is invoked. One of
+	// ENABLED or DISABLED.
+	RequireConfirmation *string `json:"requireConfirmation,omitempty"`
+}
+
+// InlineParameterDetail describes a parameter of an InlineFunction.
+type InlineParameterDetail struct {
+	Description *string `json:"description,omitempty"`
+	Required    *bool   `json:"required,omitempty"`
+	// The data type of the parameter: string, number, integer, boolean or array.
+	// +kubebuilder:validation:Required
+	Type *string `json:"type"`
+}
+
+// InlineKnowledgeBase is a knowledge base that is associated with, and
+// disassociated from, the Agent that declares it. Knowledge bases are matched
+// by knowledgeBaseID.
+type InlineKnowledgeBase struct {
+	// Describes what the agent should use the knowledge base for.
+	// +kubebuilder:validation:Required
+	Description *string `json:"description"`
+	// The unique identifier of the knowledge base.
+	//
+	// Regex Pattern: `^[0-9a-zA-Z]{10}$`
+	// +kubebuilder:validation:Required
+	KnowledgeBaseID *string `json:"knowledgeBaseID"`
+	// Whether the agent uses the knowledge base when responding. Defaults to
+	// ENABLED.
+	KnowledgeBaseState *string `json:"knowledgeBaseState,omitempty"`
+}
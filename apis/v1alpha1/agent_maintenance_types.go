@@ -0,0 +1,30 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+// MaintenanceWindow is the recurring period in which the changes that
+// prepare the agent again are applied. The window opens on each activation of
+// its schedule and stays open for its duration.
+type MaintenanceWindow struct {
+	// How long the window stays open, as a Go duration such as 2h or 90m.
+	// +kubebuilder:validation:Required
+	Duration *string `json:"duration"`
+	// The standard cron schedule on which the window opens, such as
+	// "0 2 * * SAT" or "@daily".
+	// +kubebuilder:validation:Required
+	Schedule *string `json:"schedule"`
+	// The IANA time zone of the schedule, such as Europe/Paris. Defaults to
+	// UTC.
+	TimeZone *string `json:"timeZone,omitempty"`
+}
@@ -0,0 +1,77 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1alpha1
+
+import (
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AgentClassSpec holds the defaults of the spec of the Agents of the class.
+// Each field is applied to the Agents leaving it unset, and changes to the
+// class are applied to them.
+// +kubebuilder:validation:XValidation:rule="!(has(self.agentResourceRoleARN) && has(self.agentResourceRoleRef))",message="agentResourceRoleARN and agentResourceRoleRef are mutually exclusive"
+type AgentClassSpec struct {
+	// The Amazon Resource Name (ARN) of the IAM role of the Agents setting
+	// neither agentResourceRoleARN nor agentResourceRoleRef.
+	AgentResourceRoleARN *string `json:"agentResourceRoleARN,omitempty"`
+	// The reference to the IAM role of the Agents setting neither
+	// agentResourceRoleARN nor agentResourceRoleRef. The role is looked up in
+	// the namespace of each Agent unless the reference names one.
+	AgentResourceRoleRef *ackv1alpha1.AWSResourceReferenceWrapper `json:"agentResourceRoleRef,omitempty"`
+	// The Amazon Resource Name (ARN) of the KMS key with which to encrypt the
+	// Agents. The key of an Agent cannot change, so it is only applied when
+	// the Agent is created.
+	CustomerEncryptionKeyARN *string `json:"customerEncryptionKeyARN,omitempty"`
+	// The identifier of the model used for orchestration by the Agents.
+	FoundationModel *string `json:"foundationModel,omitempty"`
+	// The Guardrail configuration assigned to the Agents.
+	GuardrailConfiguration *GuardrailConfiguration `json:"guardrailConfiguration,omitempty"`
+	// The number of seconds for which Amazon Bedrock keeps information about a
+	// user's conversation with the Agents.
+	IdleSessionTTLInSeconds *int64 `json:"idleSessionTTLInSeconds,omitempty"`
+}
+
+// AgentClass holds defaults shared by the Agents naming it in their
+// agentClassName field. The AgentClass carrying the
+// bedrockagent.services.k8s.aws/is-default-class: "true" annotation applies
+// to the Agents without an agentClassName.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+type AgentClass struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              AgentClassSpec `json:"spec,omitempty"`
+}
+
+// AgentClassList contains a list of AgentClass
+// +kubebuilder:object:root=true
+type AgentClassList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AgentClass `json:"items"`
+}
+
+// AppliedAgentClass records the AgentClass whose defaults were applied to an
+// Agent.
+type AppliedAgentClass struct {
+	// The spec fields of the Agent whose value came from the class.
+	Fields []*string `json:"fields,omitempty"`
+	// The name of the class.
+	Name *string `json:"name,omitempty"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AgentClass{}, &AgentClassList{})
+}
@@ -288,6 +288,109 @@ func (in *AgentAliasSummary) DeepCopy() *AgentAliasSummary {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentClass) DeepCopyInto(out *AgentClass) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentClass.
+func (in *AgentClass) DeepCopy() *AgentClass {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgentClass) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentClassList) DeepCopyInto(out *AgentClassList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AgentClass, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentClassList.
+func (in *AgentClassList) DeepCopy() *AgentClassList {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentClassList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgentClassList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentClassSpec) DeepCopyInto(out *AgentClassSpec) {
+	*out = *in
+	if in.AgentResourceRoleARN != nil {
+		in, out := &in.AgentResourceRoleARN, &out.AgentResourceRoleARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.AgentResourceRoleRef != nil {
+		in, out := &in.AgentResourceRoleRef, &out.AgentResourceRoleRef
+		*out = new(corev1alpha1.AWSResourceReferenceWrapper)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomerEncryptionKeyARN != nil {
+		in, out := &in.CustomerEncryptionKeyARN, &out.CustomerEncryptionKeyARN
+		*out = new(string)
+		**out = **in
+	}
+	if in.FoundationModel != nil {
+		in, out := &in.FoundationModel, &out.FoundationModel
+		*out = new(string)
+		**out = **in
+	}
+	if in.GuardrailConfiguration != nil {
+		in, out := &in.GuardrailConfiguration, &out.GuardrailConfiguration
+		*out = new(GuardrailConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IdleSessionTTLInSeconds != nil {
+		in, out := &in.IdleSessionTTLInSeconds, &out.IdleSessionTTLInSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentClassSpec.
+func (in *AgentClassSpec) DeepCopy() *AgentClassSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentClassSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentCollaborator) DeepCopyInto(out *AgentCollaborator) {
 	*out = *in
@@ -483,9 +586,59 @@ func (in *AgentList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentRevision) DeepCopyInto(out *AgentRevision) {
+	*out = *in
+	if in.PreparedAt != nil {
+		in, out := &in.PreparedAt, &out.PreparedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Revision != nil {
+		in, out := &in.Revision, &out.Revision
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(string)
+		**out = **in
+	}
+	if in.SpecHash != nil {
+		in, out := &in.SpecHash, &out.SpecHash
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentRevision.
+func (in *AgentRevision) DeepCopy() *AgentRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 	*out = *in
+	if in.ActionGroups != nil {
+		in, out := &in.ActionGroups, &out.ActionGroups
+		*out = make([]*InlineActionGroup, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(InlineActionGroup)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.AgentClassName != nil {
+		in, out := &in.AgentClassName, &out.AgentClassName
+		*out = new(string)
+		**out = **in
+	}
 	if in.AgentCollaboration != nil {
 		in, out := &in.AgentCollaboration, &out.AgentCollaboration
 		*out = new(string)
@@ -541,6 +694,22 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.KnowledgeBases != nil {
+		in, out := &in.KnowledgeBases, &out.KnowledgeBases
+		*out = make([]*InlineKnowledgeBase, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(InlineKnowledgeBase)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindow)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MemoryConfiguration != nil {
 		in, out := &in.MemoryConfiguration, &out.MemoryConfiguration
 		*out = new(MemoryConfiguration)
@@ -603,6 +772,11 @@ func (in *AgentStatus) DeepCopyInto(out *AgentStatus) {
 			}
 		}
 	}
+	if in.AgentClass != nil {
+		in, out := &in.AgentClass, &out.AgentClass
+		*out = new(AppliedAgentClass)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AgentID != nil {
 		in, out := &in.AgentID, &out.AgentID
 		*out = new(string)
@@ -638,6 +812,31 @@ func (in *AgentStatus) DeepCopyInto(out *AgentStatus) {
 			}
 		}
 	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(string)
+		**out = **in
+	}
+	if in.InheritedTagKeys != nil {
+		in, out := &in.InheritedTagKeys, &out.InheritedTagKeys
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.OwnerCluster != nil {
+		in, out := &in.OwnerCluster, &out.OwnerCluster
+		*out = new(string)
+		**out = **in
+	}
+	if in.PausedAt != nil {
+		in, out := &in.PausedAt, &out.PausedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.PreparedAt != nil {
 		in, out := &in.PreparedAt, &out.PreparedAt
 		*out = (*in).DeepCopy()
@@ -653,6 +852,17 @@ func (in *AgentStatus) DeepCopyInto(out *AgentStatus) {
 			}
 		}
 	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make([]*AgentRevision, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(AgentRevision)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 	if in.UpdatedAt != nil {
 		in, out := &in.UpdatedAt, &out.UpdatedAt
 		*out = (*in).DeepCopy()
@@ -1020,6 +1230,37 @@ func (in *Agent_SDK) DeepCopy() *Agent_SDK {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppliedAgentClass) DeepCopyInto(out *AppliedAgentClass) {
+	*out = *in
+	if in.Fields != nil {
+		in, out := &in.Fields, &out.Fields
+		*out = make([]*string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(string)
+				**out = **in
+			}
+		}
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppliedAgentClass.
+func (in *AppliedAgentClass) DeepCopy() *AppliedAgentClass {
+	if in == nil {
+		return nil
+	}
+	out := new(AppliedAgentClass)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ByteContentDoc) DeepCopyInto(out *ByteContentDoc) {
 	*out = *in
@@ -1481,6 +1722,263 @@ func (in *IngestionJobSummary) DeepCopy() *IngestionJobSummary {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineAPISchema) DeepCopyInto(out *InlineAPISchema) {
+	*out = *in
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = new(string)
+		**out = **in
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(InlineS3Identifier)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineAPISchema.
+func (in *InlineAPISchema) DeepCopy() *InlineAPISchema {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineAPISchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineActionGroup) DeepCopyInto(out *InlineActionGroup) {
+	*out = *in
+	if in.ActionGroupExecutor != nil {
+		in, out := &in.ActionGroupExecutor, &out.ActionGroupExecutor
+		*out = new(InlineActionGroupExecutor)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActionGroupName != nil {
+		in, out := &in.ActionGroupName, &out.ActionGroupName
+		*out = new(string)
+		**out = **in
+	}
+	if in.ActionGroupState != nil {
+		in, out := &in.ActionGroupState, &out.ActionGroupState
+		*out = new(string)
+		**out = **in
+	}
+	if in.APISchema != nil {
+		in, out := &in.APISchema, &out.APISchema
+		*out = new(InlineAPISchema)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.FunctionSchema != nil {
+		in, out := &in.FunctionSchema, &out.FunctionSchema
+		*out = new(InlineFunctionSchema)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ParentActionGroupSignature != nil {
+		in, out := &in.ParentActionGroupSignature, &out.ParentActionGroupSignature
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineActionGroup.
+func (in *InlineActionGroup) DeepCopy() *InlineActionGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineActionGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineActionGroupExecutor) DeepCopyInto(out *InlineActionGroupExecutor) {
+	*out = *in
+	if in.CustomControl != nil {
+		in, out := &in.CustomControl, &out.CustomControl
+		*out = new(string)
+		**out = **in
+	}
+	if in.Lambda != nil {
+		in, out := &in.Lambda, &out.Lambda
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineActionGroupExecutor.
+func (in *InlineActionGroupExecutor) DeepCopy() *InlineActionGroupExecutor {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineActionGroupExecutor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineFunction) DeepCopyInto(out *InlineFunction) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]*InlineParameterDetail, len(*in))
+		for key, val := range *in {
+			var outVal *InlineParameterDetail
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = new(InlineParameterDetail)
+				(*in).DeepCopyInto(*out)
+			}
+			(*out)[key] = outVal
+		}
+	}
+	if in.RequireConfirmation != nil {
+		in, out := &in.RequireConfirmation, &out.RequireConfirmation
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineFunction.
+func (in *InlineFunction) DeepCopy() *InlineFunction {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineFunction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineFunctionSchema) DeepCopyInto(out *InlineFunctionSchema) {
+	*out = *in
+	if in.Functions != nil {
+		in, out := &in.Functions, &out.Functions
+		*out = make([]*InlineFunction, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(InlineFunction)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineFunctionSchema.
+func (in *InlineFunctionSchema) DeepCopy() *InlineFunctionSchema {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineFunctionSchema)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineKnowledgeBase) DeepCopyInto(out *InlineKnowledgeBase) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.KnowledgeBaseID != nil {
+		in, out := &in.KnowledgeBaseID, &out.KnowledgeBaseID
+		*out = new(string)
+		**out = **in
+	}
+	if in.KnowledgeBaseState != nil {
+		in, out := &in.KnowledgeBaseState, &out.KnowledgeBaseState
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineKnowledgeBase.
+func (in *InlineKnowledgeBase) DeepCopy() *InlineKnowledgeBase {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineKnowledgeBase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineParameterDetail) DeepCopyInto(out *InlineParameterDetail) {
+	*out = *in
+	if in.Description != nil {
+		in, out := &in.Description, &out.Description
+		*out = new(string)
+		**out = **in
+	}
+	if in.Required != nil {
+		in, out := &in.Required, &out.Required
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Type != nil {
+		in, out := &in.Type, &out.Type
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineParameterDetail.
+func (in *InlineParameterDetail) DeepCopy() *InlineParameterDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineParameterDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InlineS3Identifier) DeepCopyInto(out *InlineS3Identifier) {
+	*out = *in
+	if in.S3BucketName != nil {
+		in, out := &in.S3BucketName, &out.S3BucketName
+		*out = new(string)
+		**out = **in
+	}
+	if in.S3ObjectKey != nil {
+		in, out := &in.S3ObjectKey, &out.S3ObjectKey
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InlineS3Identifier.
+func (in *InlineS3Identifier) DeepCopy() *InlineS3Identifier {
+	if in == nil {
+		return nil
+	}
+	out := new(InlineS3Identifier)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KnowledgeBase) DeepCopyInto(out *KnowledgeBase) {
 	*out = *in
@@ -1638,6 +2136,36 @@ func (in *LambdaFunctionFlowNodeConfiguration) DeepCopy() *LambdaFunctionFlowNod
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(string)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(string)
+		**out = **in
+	}
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MemoryConfiguration) DeepCopyInto(out *MemoryConfiguration) {
 	*out = *in
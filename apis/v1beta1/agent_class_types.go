@@ -0,0 +1,23 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1beta1
+
+// AppliedAgentClass records the AgentClass whose defaults were applied to an
+// Agent.
+type AppliedAgentClass struct {
+	// The spec fields of the Agent whose value came from the class.
+	Fields []*string `json:"fields,omitempty"`
+	// The name of the class.
+	Name *string `json:"name,omitempty"`
+}
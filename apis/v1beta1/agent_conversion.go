@@ -0,0 +1,600 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1beta1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+var _ conversion.Convertible = &Agent{}
+
+// ConvertTo converts this Agent to the v1alpha1 hub version.
+func (src *Agent) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.Agent)
+	in := src.DeepCopy()
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = v1alpha1.AgentSpec{
+		ActionGroups:                convertActionGroupsToHub(in.Spec.ActionGroups),
+		AgentClassName:              in.Spec.AgentClassName,
+		AgentCollaboration:          (*string)(in.Spec.AgentCollaboration),
+		AgentName:                   in.Spec.AgentName,
+		AgentResourceRoleARN:        in.Spec.AgentResourceRoleARN,
+		AgentResourceRoleRef:        in.Spec.AgentResourceRoleRef,
+		CustomOrchestration:         convertCustomOrchestrationToHub(in.Spec.CustomOrchestration),
+		CustomerEncryptionKeyARN:    in.Spec.CustomerEncryptionKeyARN,
+		Description:                 in.Spec.Description,
+		FoundationModel:             in.Spec.FoundationModel,
+		GuardrailConfiguration:      convertGuardrailConfigurationToHub(in.Spec.GuardrailConfiguration),
+		IdleSessionTTLInSeconds:     in.Spec.IdleSessionTTLInSeconds,
+		Instruction:                 in.Spec.Instruction,
+		KnowledgeBases:              convertKnowledgeBasesToHub(in.Spec.KnowledgeBases),
+		MaintenanceWindow:           (*v1alpha1.MaintenanceWindow)(in.Spec.MaintenanceWindow),
+		MemoryConfiguration:         convertMemoryConfigurationToHub(in.Spec.MemoryConfiguration),
+		OrchestrationType:           (*string)(in.Spec.OrchestrationType),
+		PromptOverrideConfiguration: convertPromptOverrideConfigurationToHub(in.Spec.PromptOverrideConfiguration),
+		Tags:                        in.Spec.Tags,
+	}
+	dst.Status = convertStatusToHub(in.Status)
+	return nil
+}
+
+// ConvertFrom converts from the v1alpha1 hub version to this Agent. Hub
+// lists with nil entries, or entries without their v1beta1 list map key,
+// have no v1beta1 representation and are rejected rather than dropped.
+func (dst *Agent) ConvertFrom(srcRaw conversion.Hub) error {
+	in := srcRaw.(*v1alpha1.Agent).DeepCopy()
+	if errs := validateHubSpec(&in.Spec, field.NewPath("spec")); len(errs) > 0 {
+		return fmt.Errorf("cannot convert Agent %s/%s to v1beta1: %w", in.Namespace, in.Name, errs.ToAggregate())
+	}
+
+	dst.ObjectMeta = in.ObjectMeta
+	dst.Spec = AgentSpec{
+		ActionGroups:                convertActionGroupsFromHub(in.Spec.ActionGroups),
+		AgentClassName:              in.Spec.AgentClassName,
+		AgentCollaboration:          (*AgentCollaboration)(in.Spec.AgentCollaboration),
+		AgentName:                   in.Spec.AgentName,
+		AgentResourceRoleARN:        in.Spec.AgentResourceRoleARN,
+		AgentResourceRoleRef:        in.Spec.AgentResourceRoleRef,
+		CustomOrchestration:         convertCustomOrchestrationFromHub(in.Spec.CustomOrchestration),
+		CustomerEncryptionKeyARN:    in.Spec.CustomerEncryptionKeyARN,
+		Description:                 in.Spec.Description,
+		FoundationModel:             in.Spec.FoundationModel,
+		GuardrailConfiguration:      convertGuardrailConfigurationFromHub(in.Spec.GuardrailConfiguration),
+		IdleSessionTTLInSeconds:     in.Spec.IdleSessionTTLInSeconds,
+		Instruction:                 in.Spec.Instruction,
+		KnowledgeBases:              convertKnowledgeBasesFromHub(in.Spec.KnowledgeBases),
+		MaintenanceWindow:           (*MaintenanceWindow)(in.Spec.MaintenanceWindow),
+		MemoryConfiguration:         convertMemoryConfigurationFromHub(in.Spec.MemoryConfiguration),
+		OrchestrationType:           (*OrchestrationType)(in.Spec.OrchestrationType),
+		PromptOverrideConfiguration: convertPromptOverrideConfigurationFromHub(in.Spec.PromptOverrideConfiguration),
+		Tags:                        in.Spec.Tags,
+	}
+	dst.Status = convertStatusFromHub(in.Status)
+	return nil
+}
+
+// validateHubSpec returns the nil list entries and the entries without their
+// list map key in the spec.
+func validateHubSpec(spec *v1alpha1.AgentSpec, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for i, actionGroup := range spec.ActionGroups {
+		path := fldPath.Child("actionGroups").Index(i)
+		if actionGroup == nil {
+			errs = append(errs, field.Required(path, "nil entry"))
+			continue
+		}
+		if actionGroup.ActionGroupName == nil {
+			errs = append(errs, field.Required(path.Child("actionGroupName"), ""))
+		}
+		if actionGroup.FunctionSchema == nil {
+			continue
+		}
+		for j, function := range actionGroup.FunctionSchema.Functions {
+			path := path.Child("functionSchema", "functions").Index(j)
+			if function == nil {
+				errs = append(errs, field.Required(path, "nil entry"))
+				continue
+			}
+			if function.Name == nil {
+				errs = append(errs, field.Required(path.Child("name"), ""))
+			}
+			for key, parameter := range function.Parameters {
+				if parameter == nil {
+					errs = append(errs, field.Required(path.Child("parameters").Key(key), "nil entry"))
+				}
+			}
+		}
+	}
+	for i, knowledgeBase := range spec.KnowledgeBases {
+		path := fldPath.Child("knowledgeBases").Index(i)
+		if knowledgeBase == nil {
+			errs = append(errs, field.Required(path, "nil entry"))
+			continue
+		}
+		if knowledgeBase.KnowledgeBaseID == nil {
+			errs = append(errs, field.Required(path.Child("knowledgeBaseID"), ""))
+		}
+	}
+	if memory := spec.MemoryConfiguration; memory != nil {
+		for i, memoryType := range memory.EnabledMemoryTypes {
+			if memoryType == nil {
+				errs = append(errs, field.Required(fldPath.Child("memoryConfiguration", "enabledMemoryTypes").Index(i), "nil entry"))
+			}
+		}
+	}
+	if promptOverride := spec.PromptOverrideConfiguration; promptOverride != nil {
+		for i, promptConfig := range promptOverride.PromptConfigurations {
+			path := fldPath.Child("promptOverrideConfiguration", "promptConfigurations").Index(i)
+			if promptConfig == nil {
+				errs = append(errs, field.Required(path, "nil entry"))
+				continue
+			}
+			if promptConfig.PromptType == nil {
+				errs = append(errs, field.Required(path.Child("promptType"), ""))
+			}
+			if promptConfig.InferenceConfiguration == nil {
+				continue
+			}
+			for j, stopSequence := range promptConfig.InferenceConfiguration.StopSequences {
+				if stopSequence == nil {
+					errs = append(errs, field.Required(path.Child("inferenceConfiguration", "stopSequences").Index(j), "nil entry"))
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// convertStatusToHub converts the status field by field, as the revisions
+// have a type per version.
+func convertStatusToHub(in AgentStatus) v1alpha1.AgentStatus {
+	return v1alpha1.AgentStatus{
+		ACKResourceMetadata: in.ACKResourceMetadata,
+		Conditions:          in.Conditions,
+		AgentClass:          (*v1alpha1.AppliedAgentClass)(in.AgentClass),
+		AgentID:             in.AgentID,
+		AgentStatus:         in.AgentStatus,
+		AgentVersion:        in.AgentVersion,
+		ClientToken:         in.ClientToken,
+		CreatedAt:           in.CreatedAt,
+		FailureReasons:      in.FailureReasons,
+		InheritedTagKeys:    in.InheritedTagKeys,
+		OwnerCluster:        in.OwnerCluster,
+		PausedAt:            in.PausedAt,
+		Plan:                in.Plan,
+		PreparedAt:          in.PreparedAt,
+		RecommendedActions:  in.RecommendedActions,
+		Revisions:           convertRevisionsToHub(in.Revisions),
+		UpdatedAt:           in.UpdatedAt,
+	}
+}
+
+func convertStatusFromHub(in v1alpha1.AgentStatus) AgentStatus {
+	return AgentStatus{
+		ACKResourceMetadata: in.ACKResourceMetadata,
+		Conditions:          in.Conditions,
+		AgentClass:          (*AppliedAgentClass)(in.AgentClass),
+		AgentID:             in.AgentID,
+		AgentStatus:         in.AgentStatus,
+		AgentVersion:        in.AgentVersion,
+		ClientToken:         in.ClientToken,
+		CreatedAt:           in.CreatedAt,
+		FailureReasons:      in.FailureReasons,
+		InheritedTagKeys:    in.InheritedTagKeys,
+		OwnerCluster:        in.OwnerCluster,
+		PausedAt:            in.PausedAt,
+		Plan:                in.Plan,
+		PreparedAt:          in.PreparedAt,
+		RecommendedActions:  in.RecommendedActions,
+		Revisions:           convertRevisionsFromHub(in.Revisions),
+		UpdatedAt:           in.UpdatedAt,
+	}
+}
+
+func convertRevisionsToHub(in []*AgentRevision) []*v1alpha1.AgentRevision {
+	if in == nil {
+		return nil
+	}
+	out := make([]*v1alpha1.AgentRevision, 0, len(in))
+	for _, revision := range in {
+		if revision == nil {
+			out = append(out, nil)
+			continue
+		}
+		out = append(out, &v1alpha1.AgentRevision{
+			PreparedAt: revision.PreparedAt,
+			Revision:   revision.Revision,
+			Spec:       revision.Spec,
+			SpecHash:   revision.SpecHash,
+		})
+	}
+	return out
+}
+
+func convertRevisionsFromHub(in []*v1alpha1.AgentRevision) []*AgentRevision {
+	if in == nil {
+		return nil
+	}
+	out := make([]*AgentRevision, 0, len(in))
+	for _, revision := range in {
+		if revision == nil {
+			out = append(out, nil)
+			continue
+		}
+		out = append(out, &AgentRevision{
+			PreparedAt: revision.PreparedAt,
+			Revision:   revision.Revision,
+			Spec:       revision.Spec,
+			SpecHash:   revision.SpecHash,
+		})
+	}
+	return out
+}
+
+func convertCustomOrchestrationToHub(in *CustomOrchestration) *v1alpha1.CustomOrchestration {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.CustomOrchestration{}
+	if in.Executor != nil {
+		out.Executor = &v1alpha1.OrchestrationExecutor{Lambda: in.Executor.Lambda}
+	}
+	return out
+}
+
+func convertCustomOrchestrationFromHub(in *v1alpha1.CustomOrchestration) *CustomOrchestration {
+	if in == nil {
+		return nil
+	}
+	out := &CustomOrchestration{}
+	if in.Executor != nil {
+		out.Executor = &OrchestrationExecutor{Lambda: in.Executor.Lambda}
+	}
+	return out
+}
+
+func convertGuardrailConfigurationToHub(in *GuardrailConfiguration) *v1alpha1.GuardrailConfiguration {
+	if in == nil {
+		return nil
+	}
+	return &v1alpha1.GuardrailConfiguration{
+		GuardrailIdentifier: in.GuardrailIdentifier,
+		GuardrailVersion:    in.GuardrailVersion,
+	}
+}
+
+func convertGuardrailConfigurationFromHub(in *v1alpha1.GuardrailConfiguration) *GuardrailConfiguration {
+	if in == nil {
+		return nil
+	}
+	return &GuardrailConfiguration{
+		GuardrailIdentifier: in.GuardrailIdentifier,
+		GuardrailVersion:    in.GuardrailVersion,
+	}
+}
+
+func convertMemoryConfigurationToHub(in *MemoryConfiguration) *v1alpha1.MemoryConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.MemoryConfiguration{
+		StorageDays: in.StorageDays,
+	}
+	if in.EnabledMemoryTypes != nil {
+		out.EnabledMemoryTypes = make([]*string, 0, len(in.EnabledMemoryTypes))
+		for _, memoryType := range in.EnabledMemoryTypes {
+			memoryType := string(memoryType)
+			out.EnabledMemoryTypes = append(out.EnabledMemoryTypes, &memoryType)
+		}
+	}
+	if in.SessionSummaryConfiguration != nil {
+		out.SessionSummaryConfiguration = &v1alpha1.SessionSummaryConfiguration{
+			MaxRecentSessions: in.SessionSummaryConfiguration.MaxRecentSessions,
+		}
+	}
+	return out
+}
+
+func convertMemoryConfigurationFromHub(in *v1alpha1.MemoryConfiguration) *MemoryConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := &MemoryConfiguration{
+		StorageDays: in.StorageDays,
+	}
+	if in.EnabledMemoryTypes != nil {
+		out.EnabledMemoryTypes = make([]MemoryType, 0, len(in.EnabledMemoryTypes))
+		for _, memoryType := range in.EnabledMemoryTypes {
+			out.EnabledMemoryTypes = append(out.EnabledMemoryTypes, MemoryType(*memoryType))
+		}
+	}
+	if in.SessionSummaryConfiguration != nil {
+		out.SessionSummaryConfiguration = &SessionSummaryConfiguration{
+			MaxRecentSessions: in.SessionSummaryConfiguration.MaxRecentSessions,
+		}
+	}
+	return out
+}
+
+func convertPromptOverrideConfigurationToHub(in *PromptOverrideConfiguration) *v1alpha1.PromptOverrideConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.PromptOverrideConfiguration{
+		OverrideLambda: in.OverrideLambda,
+	}
+	if in.PromptConfigurations != nil {
+		out.PromptConfigurations = make([]*v1alpha1.PromptConfiguration, 0, len(in.PromptConfigurations))
+		for _, promptConfig := range in.PromptConfigurations {
+			out.PromptConfigurations = append(out.PromptConfigurations, convertPromptConfigurationToHub(promptConfig))
+		}
+	}
+	return out
+}
+
+func convertPromptOverrideConfigurationFromHub(in *v1alpha1.PromptOverrideConfiguration) *PromptOverrideConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := &PromptOverrideConfiguration{
+		OverrideLambda: in.OverrideLambda,
+	}
+	if in.PromptConfigurations != nil {
+		out.PromptConfigurations = make([]PromptConfiguration, 0, len(in.PromptConfigurations))
+		for _, promptConfig := range in.PromptConfigurations {
+			out.PromptConfigurations = append(out.PromptConfigurations, convertPromptConfigurationFromHub(promptConfig))
+		}
+	}
+	return out
+}
+
+// convertPromptConfigurationToHub maps an empty promptType back to nil, the
+// v1alpha1 representation of an unset promptType.
+func convertPromptConfigurationToHub(in PromptConfiguration) *v1alpha1.PromptConfiguration {
+	out := &v1alpha1.PromptConfiguration{
+		BasePromptTemplate:     in.BasePromptTemplate,
+		FoundationModel:        in.FoundationModel,
+		InferenceConfiguration: convertInferenceConfigurationToHub(in.InferenceConfiguration),
+		ParserMode:             (*string)(in.ParserMode),
+		PromptCreationMode:     (*string)(in.PromptCreationMode),
+		PromptState:            (*string)(in.PromptState),
+	}
+	if in.PromptType != "" {
+		promptType := string(in.PromptType)
+		out.PromptType = &promptType
+	}
+	return out
+}
+
+func convertPromptConfigurationFromHub(in *v1alpha1.PromptConfiguration) PromptConfiguration {
+	out := PromptConfiguration{
+		BasePromptTemplate:     in.BasePromptTemplate,
+		FoundationModel:        in.FoundationModel,
+		InferenceConfiguration: convertInferenceConfigurationFromHub(in.InferenceConfiguration),
+		ParserMode:             (*CreationMode)(in.ParserMode),
+		PromptCreationMode:     (*CreationMode)(in.PromptCreationMode),
+		PromptState:            (*PromptState)(in.PromptState),
+		PromptType:             PromptType(*in.PromptType),
+	}
+	return out
+}
+
+func convertInferenceConfigurationToHub(in *InferenceConfiguration) *v1alpha1.InferenceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := &v1alpha1.InferenceConfiguration{
+		MaximumLength: in.MaximumLength,
+		Temperature:   in.Temperature,
+		TopK:          in.TopK,
+		TopP:          in.TopP,
+	}
+	if in.StopSequences != nil {
+		out.StopSequences = make([]*string, 0, len(in.StopSequences))
+		for _, stopSequence := range in.StopSequences {
+			stopSequence := stopSequence
+			out.StopSequences = append(out.StopSequences, &stopSequence)
+		}
+	}
+	return out
+}
+
+func convertInferenceConfigurationFromHub(in *v1alpha1.InferenceConfiguration) *InferenceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := &InferenceConfiguration{
+		MaximumLength: in.MaximumLength,
+		Temperature:   in.Temperature,
+		TopK:          in.TopK,
+		TopP:          in.TopP,
+	}
+	if in.StopSequences != nil {
+		out.StopSequences = make([]string, 0, len(in.StopSequences))
+		for _, stopSequence := range in.StopSequences {
+			out.StopSequences = append(out.StopSequences, *stopSequence)
+		}
+	}
+	return out
+}
+
+func convertActionGroupsToHub(in []InlineActionGroup) []*v1alpha1.InlineActionGroup {
+	if in == nil {
+		return nil
+	}
+	out := make([]*v1alpha1.InlineActionGroup, 0, len(in))
+	for _, actionGroup := range in {
+		hubActionGroup := &v1alpha1.InlineActionGroup{
+			ActionGroupState:           actionGroup.ActionGroupState,
+			Description:                actionGroup.Description,
+			ParentActionGroupSignature: actionGroup.ParentActionGroupSignature,
+		}
+		if actionGroup.ActionGroupName != "" {
+			actionGroupName := actionGroup.ActionGroupName
+			hubActionGroup.ActionGroupName = &actionGroupName
+		}
+		if actionGroup.ActionGroupExecutor != nil {
+			hubActionGroup.ActionGroupExecutor = &v1alpha1.InlineActionGroupExecutor{
+				CustomControl: actionGroup.ActionGroupExecutor.CustomControl,
+				Lambda:        actionGroup.ActionGroupExecutor.Lambda,
+			}
+		}
+		if actionGroup.APISchema != nil {
+			hubActionGroup.APISchema = &v1alpha1.InlineAPISchema{
+				Payload: actionGroup.APISchema.Payload,
+			}
+			if actionGroup.APISchema.S3 != nil {
+				hubActionGroup.APISchema.S3 = &v1alpha1.InlineS3Identifier{
+					S3BucketName: actionGroup.APISchema.S3.S3BucketName,
+					S3ObjectKey:  actionGroup.APISchema.S3.S3ObjectKey,
+				}
+			}
+		}
+		if actionGroup.FunctionSchema != nil {
+			hubActionGroup.FunctionSchema = &v1alpha1.InlineFunctionSchema{}
+			if actionGroup.FunctionSchema.Functions != nil {
+				hubActionGroup.FunctionSchema.Functions = make([]*v1alpha1.InlineFunction, 0, len(actionGroup.FunctionSchema.Functions))
+				for _, function := range actionGroup.FunctionSchema.Functions {
+					hubActionGroup.FunctionSchema.Functions = append(hubActionGroup.FunctionSchema.Functions, convertFunctionToHub(function))
+				}
+			}
+		}
+		out = append(out, hubActionGroup)
+	}
+	return out
+}
+
+func convertFunctionToHub(in InlineFunction) *v1alpha1.InlineFunction {
+	out := &v1alpha1.InlineFunction{
+		Description:         in.Description,
+		RequireConfirmation: in.RequireConfirmation,
+	}
+	if in.Name != "" {
+		name := in.Name
+		out.Name = &name
+	}
+	if in.Parameters != nil {
+		out.Parameters = make(map[string]*v1alpha1.InlineParameterDetail, len(in.Parameters))
+		for key, parameter := range in.Parameters {
+			out.Parameters[key] = &v1alpha1.InlineParameterDetail{
+				Description: parameter.Description,
+				Required:    parameter.Required,
+				Type:        parameter.Type,
+			}
+		}
+	}
+	return out
+}
+
+func convertActionGroupsFromHub(in []*v1alpha1.InlineActionGroup) []InlineActionGroup {
+	if in == nil {
+		return nil
+	}
+	out := make([]InlineActionGroup, 0, len(in))
+	for _, hubActionGroup := range in {
+		actionGroup := InlineActionGroup{
+			ActionGroupName:            *hubActionGroup.ActionGroupName,
+			ActionGroupState:           hubActionGroup.ActionGroupState,
+			Description:                hubActionGroup.Description,
+			ParentActionGroupSignature: hubActionGroup.ParentActionGroupSignature,
+		}
+		if hubActionGroup.ActionGroupExecutor != nil {
+			actionGroup.ActionGroupExecutor = &InlineActionGroupExecutor{
+				CustomControl: hubActionGroup.ActionGroupExecutor.CustomControl,
+				Lambda:        hubActionGroup.ActionGroupExecutor.Lambda,
+			}
+		}
+		if hubActionGroup.APISchema != nil {
+			actionGroup.APISchema = &InlineAPISchema{
+				Payload: hubActionGroup.APISchema.Payload,
+			}
+			if hubActionGroup.APISchema.S3 != nil {
+				actionGroup.APISchema.S3 = &InlineS3Identifier{
+					S3BucketName: hubActionGroup.APISchema.S3.S3BucketName,
+					S3ObjectKey:  hubActionGroup.APISchema.S3.S3ObjectKey,
+				}
+			}
+		}
+		if hubActionGroup.FunctionSchema != nil {
+			actionGroup.FunctionSchema = &InlineFunctionSchema{}
+			if hubActionGroup.FunctionSchema.Functions != nil {
+				actionGroup.FunctionSchema.Functions = make([]InlineFunction, 0, len(hubActionGroup.FunctionSchema.Functions))
+				for _, function := range hubActionGroup.FunctionSchema.Functions {
+					actionGroup.FunctionSchema.Functions = append(actionGroup.FunctionSchema.Functions, convertFunctionFromHub(function))
+				}
+			}
+		}
+		out = append(out, actionGroup)
+	}
+	return out
+}
+
+func convertFunctionFromHub(in *v1alpha1.InlineFunction) InlineFunction {
+	out := InlineFunction{
+		Description:         in.Description,
+		Name:                *in.Name,
+		RequireConfirmation: in.RequireConfirmation,
+	}
+	if in.Parameters != nil {
+		out.Parameters = make(map[string]InlineParameterDetail, len(in.Parameters))
+		for key, parameter := range in.Parameters {
+			out.Parameters[key] = InlineParameterDetail{
+				Description: parameter.Description,
+				Required:    parameter.Required,
+				Type:        parameter.Type,
+			}
+		}
+	}
+	return out
+}
+
+func convertKnowledgeBasesToHub(in []InlineKnowledgeBase) []*v1alpha1.InlineKnowledgeBase {
+	if in == nil {
+		return nil
+	}
+	out := make([]*v1alpha1.InlineKnowledgeBase, 0, len(in))
+	for _, knowledgeBase := range in {
+		hubKnowledgeBase := &v1alpha1.InlineKnowledgeBase{
+			Description:        knowledgeBase.Description,
+			KnowledgeBaseState: knowledgeBase.KnowledgeBaseState,
+		}
+		if knowledgeBase.KnowledgeBaseID != "" {
+			knowledgeBaseID := knowledgeBase.KnowledgeBaseID
+			hubKnowledgeBase.KnowledgeBaseID = &knowledgeBaseID
+		}
+		out = append(out, hubKnowledgeBase)
+	}
+	return out
+}
+
+func convertKnowledgeBasesFromHub(in []*v1alpha1.InlineKnowledgeBase) []InlineKnowledgeBase {
+	if in == nil {
+		return nil
+	}
+	out := make([]InlineKnowledgeBase, 0, len(in))
+	for _, hubKnowledgeBase := range in {
+		out = append(out, InlineKnowledgeBase{
+			Description:        hubKnowledgeBase.Description,
+			KnowledgeBaseID:    *hubKnowledgeBase.KnowledgeBaseID,
+			KnowledgeBaseState: hubKnowledgeBase.KnowledgeBaseState,
+		})
+	}
+	return out
+}
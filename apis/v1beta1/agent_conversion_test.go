@@ -0,0 +1,269 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1beta1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+func fullHubAgent() *v1alpha1.Agent {
+	createdAt := metav1.Unix(1700000000, 0)
+	return &v1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "my-agent",
+			Namespace:   "default",
+			Annotations: map[string]string{"services.k8s.aws/region": "us-west-2"},
+		},
+		Spec: v1alpha1.AgentSpec{
+			ActionGroups: []*v1alpha1.InlineActionGroup{
+				{
+					ActionGroupName: aws.String("weather"),
+					ActionGroupExecutor: &v1alpha1.InlineActionGroupExecutor{
+						Lambda: aws.String("arn:aws:lambda:us-west-2:123456789012:function:weather"),
+					},
+					FunctionSchema: &v1alpha1.InlineFunctionSchema{
+						Functions: []*v1alpha1.InlineFunction{
+							{
+								Name: aws.String("get_forecast"),
+								Parameters: map[string]*v1alpha1.InlineParameterDetail{
+									"city": {Type: aws.String("string"), Required: aws.Bool(true)},
+								},
+							},
+						},
+					},
+				},
+				{
+					ActionGroupName:            aws.String("user-input"),
+					ParentActionGroupSignature: aws.String("AMAZON.UserInput"),
+				},
+			},
+			AgentClassName:           aws.String("support"),
+			AgentCollaboration:       aws.String("SUPERVISOR"),
+			AgentName:                aws.String("my-agent"),
+			AgentResourceRoleARN:     aws.String("arn:aws:iam::123456789012:role/agent-role"),
+			CustomerEncryptionKeyARN: aws.String("arn:aws:kms:us-west-2:123456789012:key/11111111-2222-3333-4444-555555555555"),
+			CustomOrchestration: &v1alpha1.CustomOrchestration{
+				Executor: &v1alpha1.OrchestrationExecutor{
+					Lambda: aws.String("arn:aws:lambda:us-west-2:123456789012:function:orchestrator"),
+				},
+			},
+			Description:     aws.String("an agent"),
+			FoundationModel: aws.String("anthropic.claude-3-haiku-20240307-v1:0"),
+			GuardrailConfiguration: &v1alpha1.GuardrailConfiguration{
+				GuardrailIdentifier: aws.String("abcdef123456"),
+				GuardrailVersion:    aws.String("DRAFT"),
+			},
+			IdleSessionTTLInSeconds: aws.Int64(600),
+			Instruction:             aws.String("You are a helpful agent."),
+			KnowledgeBases: []*v1alpha1.InlineKnowledgeBase{
+				{
+					Description:     aws.String("product documentation"),
+					KnowledgeBaseID: aws.String("KB12345678"),
+				},
+			},
+			MaintenanceWindow: &v1alpha1.MaintenanceWindow{
+				Duration: aws.String("2h"),
+				Schedule: aws.String("0 2 * * SAT"),
+				TimeZone: aws.String("Europe/Paris"),
+			},
+			MemoryConfiguration: &v1alpha1.MemoryConfiguration{
+				EnabledMemoryTypes: []*string{aws.String("SESSION_SUMMARY")},
+				SessionSummaryConfiguration: &v1alpha1.SessionSummaryConfiguration{
+					MaxRecentSessions: aws.Int64(5),
+				},
+				StorageDays: aws.Int64(30),
+			},
+			OrchestrationType: aws.String("CUSTOM_ORCHESTRATION"),
+			PromptOverrideConfiguration: &v1alpha1.PromptOverrideConfiguration{
+				OverrideLambda: aws.String("arn:aws:lambda:us-west-2:123456789012:function:parser"),
+				PromptConfigurations: []*v1alpha1.PromptConfiguration{
+					{
+						BasePromptTemplate: aws.String("{{prompt}}"),
+						InferenceConfiguration: &v1alpha1.InferenceConfiguration{
+							MaximumLength: aws.Int64(2048),
+							StopSequences: []*string{aws.String("</answer>")},
+							Temperature:   aws.Float64(0),
+							TopK:          aws.Int64(250),
+							TopP:          aws.Float64(1),
+						},
+						ParserMode:         aws.String("OVERRIDDEN"),
+						PromptCreationMode: aws.String("OVERRIDDEN"),
+						PromptState:        aws.String("ENABLED"),
+						PromptType:         aws.String("ORCHESTRATION"),
+					},
+					{
+						PromptCreationMode: aws.String("DEFAULT"),
+						PromptState:        aws.String("DISABLED"),
+						PromptType:         aws.String("PRE_PROCESSING"),
+					},
+				},
+			},
+			Tags: map[string]*string{"team": aws.String("ml")},
+		},
+		Status: v1alpha1.AgentStatus{
+			ACKResourceMetadata: &ackv1alpha1.ResourceMetadata{
+				Region: (*ackv1alpha1.AWSRegion)(aws.String("us-west-2")),
+			},
+			Conditions: []*ackv1alpha1.Condition{
+				{
+					Type:   ackv1alpha1.ConditionTypeResourceSynced,
+					Status: corev1.ConditionTrue,
+				},
+			},
+			AgentClass: &v1alpha1.AppliedAgentClass{
+				Fields: aws.StringSlice([]string{"foundationModel"}),
+				Name:   aws.String("support"),
+			},
+			AgentID:          aws.String("ABCDEFGHIJ"),
+			AgentStatus:      aws.String("PREPARED"),
+			AgentVersion:     aws.String("DRAFT"),
+			CreatedAt:        &createdAt,
+			InheritedTagKeys: aws.StringSlice([]string{"team"}),
+			OwnerCluster:     aws.String("blue"),
+			PausedAt:         &createdAt,
+			PreparedAt:       &createdAt,
+			Revisions: []*v1alpha1.AgentRevision{
+				{
+					PreparedAt: &createdAt,
+					Revision:   aws.Int64(1),
+					Spec:       aws.String(`{"agentName":"my-agent"}`),
+					SpecHash:   aws.String("9c1d0c1f"),
+				},
+			},
+		},
+	}
+}
+
+func TestAgentConversion_HubRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		hub  *v1alpha1.Agent
+	}{
+		{
+			name: "empty agent",
+			hub:  &v1alpha1.Agent{},
+		},
+		{
+			name: "minimal agent",
+			hub: &v1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-agent"},
+				Spec: v1alpha1.AgentSpec{
+					AgentName: aws.String("my-agent"),
+				},
+			},
+		},
+		{
+			name: "fully populated agent",
+			hub:  fullHubAgent(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spoke := &Agent{}
+			if err := spoke.ConvertFrom(tt.hub); err != nil {
+				t.Fatalf("ConvertFrom() unexpected error = %v", err)
+			}
+			got := &v1alpha1.Agent{}
+			if err := spoke.ConvertTo(got); err != nil {
+				t.Fatalf("ConvertTo() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.hub) {
+				t.Errorf("round trip = %+v, want %+v", got, tt.hub)
+			}
+		})
+	}
+}
+
+func TestAgentConversion_SpokeRoundTrip(t *testing.T) {
+	spoke := &Agent{}
+	if err := spoke.ConvertFrom(fullHubAgent()); err != nil {
+		t.Fatalf("ConvertFrom() unexpected error = %v", err)
+	}
+	hub := &v1alpha1.Agent{}
+	if err := spoke.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo() unexpected error = %v", err)
+	}
+	got := &Agent{}
+	if err := got.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom() unexpected error = %v", err)
+	}
+	if !reflect.DeepEqual(got, spoke) {
+		t.Errorf("round trip = %+v, want %+v", got, spoke)
+	}
+}
+
+func TestAgentConversion_TypedFields(t *testing.T) {
+	spoke := &Agent{}
+	if err := spoke.ConvertFrom(fullHubAgent()); err != nil {
+		t.Fatalf("ConvertFrom() unexpected error = %v", err)
+	}
+	memoryTypes := spoke.Spec.MemoryConfiguration.EnabledMemoryTypes
+	if !reflect.DeepEqual(memoryTypes, []MemoryType{MemoryType_SESSION_SUMMARY}) {
+		t.Errorf("EnabledMemoryTypes = %v, want [%s]", memoryTypes, MemoryType_SESSION_SUMMARY)
+	}
+	promptConfigs := spoke.Spec.PromptOverrideConfiguration.PromptConfigurations
+	if len(promptConfigs) != 2 {
+		t.Fatalf("PromptConfigurations = %v, want 2 entries", promptConfigs)
+	}
+	if promptConfigs[0].PromptType != PromptType_ORCHESTRATION {
+		t.Errorf("PromptConfigurations[0].PromptType = %s, want %s", promptConfigs[0].PromptType, PromptType_ORCHESTRATION)
+	}
+	if *promptConfigs[1].PromptState != PromptState_DISABLED {
+		t.Errorf("PromptConfigurations[1].PromptState = %s, want %s", *promptConfigs[1].PromptState, PromptState_DISABLED)
+	}
+}
+
+func TestAgentConversion_RejectsUnconvertibleListEntries(t *testing.T) {
+	hub := &v1alpha1.Agent{
+		Spec: v1alpha1.AgentSpec{
+			ActionGroups: []*v1alpha1.InlineActionGroup{nil},
+			KnowledgeBases: []*v1alpha1.InlineKnowledgeBase{
+				{Description: aws.String("no ID")},
+			},
+			MemoryConfiguration: &v1alpha1.MemoryConfiguration{
+				EnabledMemoryTypes: []*string{nil, aws.String("SESSION_SUMMARY")},
+			},
+			PromptOverrideConfiguration: &v1alpha1.PromptOverrideConfiguration{
+				PromptConfigurations: []*v1alpha1.PromptConfiguration{
+					{PromptState: aws.String("ENABLED")},
+					{PromptType: aws.String("ORCHESTRATION")},
+				},
+			},
+		},
+	}
+	spoke := &Agent{}
+	err := spoke.ConvertFrom(hub)
+	if err == nil {
+		t.Fatalf("ConvertFrom() error = nil, want the unconvertible entries rejected")
+	}
+	for _, want := range []string{
+		"spec.actionGroups[0]",
+		"spec.knowledgeBases[0].knowledgeBaseID",
+		"spec.memoryConfiguration.enabledMemoryTypes[0]",
+		"spec.promptOverrideConfiguration.promptConfigurations[0].promptType",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ConvertFrom() error = %v, want it to name %s", err, want)
+		}
+	}
+}
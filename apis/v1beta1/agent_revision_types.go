@@ -0,0 +1,34 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AgentRevision is a spec the agent was successfully prepared with. The
+// agent is rolled back to a revision with the
+// bedrockagent.services.k8s.aws/rollback-to annotation.
+type AgentRevision struct {
+	// The time at which the agent was last prepared with the spec.
+	PreparedAt *metav1.Time `json:"preparedAt,omitempty"`
+	// The number of the revision, increasing with each new spec.
+	Revision *int64 `json:"revision,omitempty"`
+	// The JSON encoding of the spec, without its tags and with its references
+	// resolved. Only kept for the newest revisions, whose specs add up to
+	// 256 KiB at most.
+	Spec *string `json:"spec,omitempty"`
+	// The SHA-256 hash of the spec.
+	SpecHash *string `json:"specHash,omitempty"`
+}
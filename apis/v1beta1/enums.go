@@ -0,0 +1,65 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1beta1
+
+// +kubebuilder:validation:Enum=SUPERVISOR;SUPERVISOR_ROUTER;DISABLED
+type AgentCollaboration string
+
+const (
+	AgentCollaboration_DISABLED          AgentCollaboration = "DISABLED"
+	AgentCollaboration_SUPERVISOR        AgentCollaboration = "SUPERVISOR"
+	AgentCollaboration_SUPERVISOR_ROUTER AgentCollaboration = "SUPERVISOR_ROUTER"
+)
+
+// +kubebuilder:validation:Enum=DEFAULT;OVERRIDDEN
+type CreationMode string
+
+const (
+	CreationMode_DEFAULT    CreationMode = "DEFAULT"
+	CreationMode_OVERRIDDEN CreationMode = "OVERRIDDEN"
+)
+
+// +kubebuilder:validation:Enum=SESSION_SUMMARY
+type MemoryType string
+
+const (
+	MemoryType_SESSION_SUMMARY MemoryType = "SESSION_SUMMARY"
+)
+
+// +kubebuilder:validation:Enum=DEFAULT;CUSTOM_ORCHESTRATION
+type OrchestrationType string
+
+const (
+	OrchestrationType_CUSTOM_ORCHESTRATION OrchestrationType = "CUSTOM_ORCHESTRATION"
+	OrchestrationType_DEFAULT              OrchestrationType = "DEFAULT"
+)
+
+// +kubebuilder:validation:Enum=ENABLED;DISABLED
+type PromptState string
+
+const (
+	PromptState_DISABLED PromptState = "DISABLED"
+	PromptState_ENABLED  PromptState = "ENABLED"
+)
+
+// +kubebuilder:validation:Enum=PRE_PROCESSING;ORCHESTRATION;POST_PROCESSING;KNOWLEDGE_BASE_RESPONSE_GENERATION;MEMORY_SUMMARIZATION
+type PromptType string
+
+const (
+	PromptType_KNOWLEDGE_BASE_RESPONSE_GENERATION PromptType = "KNOWLEDGE_BASE_RESPONSE_GENERATION"
+	PromptType_MEMORY_SUMMARIZATION               PromptType = "MEMORY_SUMMARIZATION"
+	PromptType_ORCHESTRATION                      PromptType = "ORCHESTRATION"
+	PromptType_POST_PROCESSING                    PromptType = "POST_PROCESSING"
+	PromptType_PRE_PROCESSING                     PromptType = "PRE_PROCESSING"
+)
@@ -0,0 +1,87 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package v1beta1
+
+// Details of custom orchestration.
+type CustomOrchestration struct {
+	// Contains details about the Lambda function containing the orchestration logic
+	// carried out upon invoking the custom orchestration.
+	Executor *OrchestrationExecutor `json:"executor,omitempty"`
+}
+
+// Details about a guardrail associated with a resource.
+type GuardrailConfiguration struct {
+	GuardrailIdentifier *string `json:"guardrailIdentifier,omitempty"`
+	GuardrailVersion    *string `json:"guardrailVersion,omitempty"`
+}
+
+// Contains inference parameters to use when the agent invokes a foundation
+// model in the part of the agent sequence defined by the promptType. For more
+// information, see Inference parameters for foundation models (https://docs.aws.amazon.com/bedrock/latest/userguide/model-parameters.html).
+type InferenceConfiguration struct {
+	MaximumLength *int64   `json:"maximumLength,omitempty"`
+	StopSequences []string `json:"stopSequences,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopK          *int64   `json:"topK,omitempty"`
+	TopP          *float64 `json:"topP,omitempty"`
+}
+
+// Details of the memory configuration.
+type MemoryConfiguration struct {
+	// The memory types to enable for the agent.
+	// +listType=set
+	EnabledMemoryTypes []MemoryType `json:"enabledMemoryTypes,omitempty"`
+	// Configuration for SESSION_SUMMARY memory type enabled for the agent.
+	SessionSummaryConfiguration *SessionSummaryConfiguration `json:"sessionSummaryConfiguration,omitempty"`
+	StorageDays                 *int64                       `json:"storageDays,omitempty"`
+}
+
+// Contains details about the Lambda function containing the orchestration logic
+// carried out upon invoking the custom orchestration.
+type OrchestrationExecutor struct {
+	Lambda *string `json:"lambda,omitempty"`
+}
+
+// Contains configurations to override a prompt template in one part of an
+// agent sequence. For more information, see Advanced prompts (https://docs.aws.amazon.com/bedrock/latest/userguide/advanced-prompts.html).
+type PromptConfiguration struct {
+	BasePromptTemplate *string `json:"basePromptTemplate,omitempty"`
+	FoundationModel    *string `json:"foundationModel,omitempty"`
+	// Contains inference parameters to use when the agent invokes a foundation
+	// model in the part of the agent sequence defined by the promptType. For more
+	// information, see Inference parameters for foundation models (https://docs.aws.amazon.com/bedrock/latest/userguide/model-parameters.html).
+	InferenceConfiguration *InferenceConfiguration `json:"inferenceConfiguration,omitempty"`
+	ParserMode             *CreationMode           `json:"parserMode,omitempty"`
+	PromptCreationMode     *CreationMode           `json:"promptCreationMode,omitempty"`
+	PromptState            *PromptState            `json:"promptState,omitempty"`
+	// The step in the agent sequence that this prompt configuration applies
+	// to. Each step can be configured at most once.
+	// +kubebuilder:validation:Required
+	PromptType PromptType `json:"promptType"`
+}
+
+// Contains configurations to override prompts in different parts of an agent
+// sequence. For more information, see Advanced prompts (https://docs.aws.amazon.com/bedrock/latest/userguide/advanced-prompts.html).
+type PromptOverrideConfiguration struct {
+	OverrideLambda *string `json:"overrideLambda,omitempty"`
+	// The prompt configurations, keyed by promptType.
+	// +listType=map
+	// +listMapKey=promptType
+	PromptConfigurations []PromptConfiguration `json:"promptConfigurations,omitempty"`
+}
+
+// Configuration for SESSION_SUMMARY memory type enabled for the agent.
+type SessionSummaryConfiguration struct {
+	MaxRecentSessions *int64 `json:"maxRecentSessions,omitempty"`
+}
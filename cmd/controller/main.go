@@ -40,6 +40,7 @@ import (
 	ctrlrtwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	svctypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	svccontroller "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/controller"
 	svcresource "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource"
 
 	_ "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/agent"
@@ -79,7 +80,9 @@ func init() {
 
 func main() {
 	var ackCfg ackcfg.Config
+	var svcCfg svccontroller.Config
 	ackCfg.BindFlags()
+	svcCfg.BindFlags()
 	flag.Parse()
 	ackCfg.SetupLogger()
 
@@ -189,6 +192,14 @@ func main() {
 		ctrlrtmetrics.Registry,
 	)
 
+	if err = svccontroller.Setup(ctx, mgr, sc, ackCfg, svcCfg); err != nil {
+		setupLog.Error(
+			err, "unable to set up the service controller",
+			"aws.service", awsServiceAlias,
+		)
+		os.Exit(1)
+	}
+
 	if ackCfg.EnableWebhookServer {
 		webhooks := ackrtwebhook.GetWebhooks()
 		for _, webhook := range webhooks {
@@ -69,6 +69,14 @@ rules:
   - get
   - list
   - watch
+- apiGroups:
+  - bedrockagent.services.k8s.aws
+  resources:
+  - agentclasses
+  verbs:
+  - get
+  - list
+  - watch
 - apiGroups:
   - bedrockagent.services.k8s.aws
   resources:
@@ -89,6 +97,13 @@ rules:
   - get
   - patch
   - update
+- apiGroups:
+  - events.k8s.io
+  resources:
+  - events
+  verbs:
+  - create
+  - patch
 - apiGroups:
   - iam.services.k8s.aws
   resources:
@@ -0,0 +1,37 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package cluster gives the resource managers cached access to the
+// Kubernetes objects they read besides their own resources, e.g. the
+// AgentClasses and the Namespaces. The ACK runtime only hands them an
+// uncached reader, so the controller sets a cached one at startup, see
+// pkg/controller.
+package cluster
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var reader client.Reader
+
+// SetReader sets the reader of the objects of the cluster.
+func SetReader(r client.Reader) {
+	reader = r
+}
+
+// Reader returns the reader set with SetReader, or nil when the resource
+// managers aren't run by the controller, e.g. in tests, which then read
+// through the reader of the runtime.
+func Reader() client.Reader {
+	return reader
+}
@@ -0,0 +1,280 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package controller holds the flags of the controller that aren't part of
+// the ACK runtime config, and sets up with the controller manager what the
+// resource managers use besides the reconcilers of the runtime. The main of
+// the controller, rendered from templates/cmd/controller/main.go.tpl, calls
+// Config.BindFlags and Setup.
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	flag "github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	ctrlrtcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrlrtcluster "sigs.k8s.io/controller-runtime/pkg/cluster"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/cluster"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/agent"
+	svcevents "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/events"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/plan"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/revision"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/tracing"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/version"
+)
+
+const (
+	// controllerName identifies the controller in its Events and traces.
+	controllerName = "ack-bedrockagent-controller"
+	// stopTimeout bounds the time the functions run when the manager stops.
+	stopTimeout = 10 * time.Second
+)
+
+// Config holds the flags of the controller that aren't part of the ACK
+// runtime config.
+type Config struct {
+	// DryRun plans the changes to every resource instead of applying them,
+	// see plan.IsDryRun.
+	DryRun bool
+	// RequireApproval applies the changes to every existing resource only
+	// once their plan is approved, see plan.RequiresApproval.
+	RequireApproval bool
+	// RevisionHistoryLimit is the number of revisions kept per resource, see
+	// revision.HistoryLimit.
+	RevisionHistoryLimit int
+	// ClusterID is the ID of the cluster written in the owner tag of the
+	// resources, see tags.SetClusterID.
+	ClusterID string
+	// DefaultTagsConfigMap is the name of the ConfigMap, in the namespace of
+	// the controller, holding the cluster default tags, see tags.Propagator.
+	DefaultTagsConfigMap string
+	// Tracing configures the export of the spans, see tracing.Setup.
+	Tracing tracing.Options
+	// OrphanSweep configures the sweeps for the orphaned agents, which are
+	// disabled when its Interval is zero, see agent.Sweeper.
+	OrphanSweep agent.SweeperOptions
+}
+
+// BindFlags binds the flags of the controller to the Config. Like
+// ackcfg.Config.BindFlags, it is called before the flags are parsed.
+func (cfg *Config) BindFlags() {
+	flag.BoolVar(
+		&cfg.DryRun, "dry-run", false,
+		"Plan the changes to the AWS resources, in their status.plan field, "+
+			"instead of applying them. Resources can opt out with the "+
+			plan.DryRunAnnotation+": \"false\" annotation.",
+	)
+	flag.BoolVar(
+		&cfg.RequireApproval, "require-approval", false,
+		"Apply the changes to existing AWS resources only once the resource "+
+			"carries the hash of their plan in the "+plan.ApprovedPlanAnnotation+
+			" annotation. Resources can opt out with the "+
+			plan.RequireApprovalAnnotation+": \"false\" annotation.",
+	)
+	flag.IntVar(
+		&cfg.RevisionHistoryLimit, "revision-history-limit", revision.DefaultHistoryLimit,
+		"Number of successfully prepared specs kept in the status.revisions "+
+			"field of each resource, which the "+revision.RollbackAnnotation+
+			" annotation rolls the resource back to. Set to 0 to stop recording "+
+			"revisions.",
+	)
+	flag.StringVar(
+		&cfg.ClusterID, "cluster-id", "",
+		"ID of the cluster, written in the "+tags.OwnerClusterTagKey+" tag of "+
+			"the AWS resources the controller writes to. Resources owned by "+
+			"another cluster are left alone unless they carry the "+
+			tags.TakeoverAnnotation+": \"true\" annotation. Ownership isn't "+
+			"tracked when empty.",
+	)
+	flag.StringVar(
+		&cfg.DefaultTagsConfigMap, "default-tags-configmap", tags.DefaultTagsConfigMapName,
+		"Name of the ConfigMap, in the controller's namespace, whose data holds "+
+			"the default tags of every resource. Set to an empty string to "+
+			"disable the cluster default tags.",
+	)
+	flag.StringVar(
+		&cfg.Tracing.Endpoint, "tracing-otlp-endpoint", "",
+		"host:port of the OTLP gRPC collector receiving the traces of the "+
+			"controller. Tracing is disabled when empty.",
+	)
+	flag.BoolVar(
+		&cfg.Tracing.Insecure, "tracing-otlp-insecure", false,
+		"Disable TLS on the connection to the OTLP collector.",
+	)
+	flag.Float64Var(
+		&cfg.Tracing.SampleRatio, "tracing-sample-ratio", 1,
+		"Fraction of the traces sampled, between 0 and 1.",
+	)
+	flag.DurationVar(
+		&cfg.OrphanSweep.Interval, "orphan-sweep-interval", 0,
+		"Interval between two sweeps for the agents tagged by the controller "+
+			"that no longer have an Agent resource, which are reported with "+
+			"Events and metrics. Set to 0 to disable the sweeps.",
+	)
+	flag.BoolVar(
+		&cfg.OrphanSweep.Delete, "orphan-sweep-delete", false,
+		"Delete the agents found orphaned for longer than the "+
+			"--orphan-sweep-grace-period.",
+	)
+	flag.DurationVar(
+		&cfg.OrphanSweep.GracePeriod, "orphan-sweep-grace-period", 24*time.Hour,
+		"Time an agent stays orphaned before --orphan-sweep-delete deletes it.",
+	)
+}
+
+// Setup configures the resource managers with the Config, and adds what they
+// run besides the reconcilers to the manager, which starts and stops it. It
+// is called once the manager and the service controller are created, before
+// the manager is started.
+func Setup(
+	ctx context.Context,
+	mgr ctrlrt.Manager,
+	sc acktypes.ServiceController,
+	ackCfg ackcfg.Config,
+	cfg Config,
+) error {
+	plan.SetDryRun(cfg.DryRun)
+	plan.SetRequireApproval(cfg.RequireApproval)
+	revision.SetHistoryLimit(cfg.RevisionHistoryLimit)
+	if err := tags.SetClusterID(cfg.ClusterID); err != nil {
+		return fmt.Errorf("invalid --cluster-id: %w", err)
+	}
+
+	// The rate limits of the Events about a resource are released once it
+	// is deleted, see events.Recorder.ForgetDeleted.
+	recorder := svcevents.NewRecorder(mgr.GetEventRecorder(controllerName))
+	informer, err := mgr.GetCache().GetInformer(ctx, &svcapitypes.Agent{})
+	if err != nil {
+		return err
+	}
+	if _, err := informer.AddEventHandler(recorder.ForgetDeleted()); err != nil {
+		return err
+	}
+	svcevents.SetRecorder(recorder)
+
+	defaultTags := types.NamespacedName{
+		Namespace: os.Getenv("ACK_SYSTEM_NAMESPACE"),
+		Name:      cfg.DefaultTagsConfigMap,
+	}
+	reader, err := newClusterCache(mgr, defaultTags)
+	if err != nil {
+		return err
+	}
+	cluster.SetReader(reader)
+	tags.SetPropagator(tags.NewPropagator(reader, defaultTags))
+
+	tracingOpts := cfg.Tracing
+	tracingOpts.ServiceName = controllerName
+	tracingOpts.ServiceVersion = version.GitVersion
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
+	if err != nil {
+		return err
+	}
+	// The spans still batched are exported once the reconcilers stop.
+	if err := mgr.Add(onStop(shutdownTracing)); err != nil {
+		return err
+	}
+
+	if cfg.OrphanSweep.Interval > 0 {
+		sweeper, err := newSweeper(ctx, mgr, sc, ackCfg, cfg.OrphanSweep, recorder)
+		if err != nil {
+			return fmt.Errorf("unable to create the orphan sweeper: %w", err)
+		}
+		if err := mgr.Add(sweeper); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newClusterCache returns the cache of the objects the resource managers
+// read besides their own resources, e.g. the AgentClasses and the
+// Namespaces. The cache of the manager, restricted to the namespaces and
+// selectors watched by the controller, cannot read them, so the cache is the
+// one of a cluster sharing the connection of the manager, which starts it
+// along with its own cache. Only the cluster default tags ConfigMap is cached
+// of the ConfigMaps.
+func newClusterCache(mgr ctrlrt.Manager, defaultTags types.NamespacedName) (ctrlrtcache.Cache, error) {
+	c, err := ctrlrtcluster.New(mgr.GetConfig(), func(o *ctrlrtcluster.Options) {
+		o.Scheme = mgr.GetScheme()
+		o.HTTPClient = mgr.GetHTTPClient()
+		o.MapperProvider = func(*rest.Config, *http.Client) (meta.RESTMapper, error) {
+			return mgr.GetRESTMapper(), nil
+		}
+		o.Cache.ByObject = tags.CacheByObject(defaultTags)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(c); err != nil {
+		return nil, err
+	}
+	return c.GetCache(), nil
+}
+
+// newSweeper returns the Sweeper of the agents in the account and region of
+// the controller, in the namespaces it watches. It reads the Agents from the
+// cache of the manager, and recognizes the agents of the controller by its
+// resource tags.
+func newSweeper(
+	ctx context.Context,
+	mgr ctrlrt.Manager,
+	sc acktypes.ServiceController,
+	ackCfg ackcfg.Config,
+	opts agent.SweeperOptions,
+	recorder *svcevents.Recorder,
+) (*agent.Sweeper, error) {
+	awsCfg, err := sc.NewAWSConfig(
+		ctx, ackv1alpha1.AWSRegion(ackCfg.Region), &ackCfg.EndpointURL, "",
+		svcapitypes.GroupVersion.WithKind("Agent"), nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := ackCfg.GetWatchNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	opts.Namespaces = namespaces
+	opts.ResourceTags = ackCfg.ResourceTags
+	return agent.NewSweeper(awsCfg, mgr.GetClient(), recorder, mgr.GetLogger().WithName("orphan-sweeper"), opts)
+}
+
+// onStop is a Runnable calling its function, with a timeout, when the
+// manager stops. It runs on every replica, whether it leads or not.
+type onStop func(context.Context) error
+
+func (f onStop) Start(ctx context.Context) error {
+	<-ctx.Done()
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), stopTimeout)
+	defer cancel()
+	return f(ctx)
+}
+
+func (onStop) NeedLeaderElection() bool {
+	return false
+}
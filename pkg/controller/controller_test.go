@@ -0,0 +1,163 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/cluster"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/agent"
+	svcevents "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/events"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+)
+
+// newManager returns a manager of an unreachable cluster, which Setup only
+// adds to. Its REST mapper knows the Agents and ConfigMaps, so that their
+// informers can be created without the cluster. The state Setup sets is reset
+// when the test ends.
+func newManager(t *testing.T) ctrlrt.Manager {
+	t.Helper()
+	t.Cleanup(func() {
+		_ = tags.SetClusterID("")
+		svcevents.SetRecorder(nil)
+		cluster.SetReader(nil)
+		tags.SetPropagator(nil)
+	})
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(svcapitypes.GroupVersion.WithKind("Agent"), meta.RESTScopeNamespace)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	mgr, err := ctrlrt.NewManager(&rest.Config{Host: "https://127.0.0.1:1"}, ctrlrt.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+		MapperProvider: func(*rest.Config, *http.Client) (meta.RESTMapper, error) {
+			return mapper, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create manager: %v", err)
+	}
+	return mgr
+}
+
+func TestSetup_ClusterID(t *testing.T) {
+	ctx := context.Background()
+
+	if err := Setup(ctx, newManager(t), nil, ackcfg.Config{}, Config{ClusterID: "green!"}); err == nil {
+		t.Error("Setup() of an invalid --cluster-id error = nil")
+	}
+	if err := Setup(ctx, newManager(t), nil, ackcfg.Config{}, Config{ClusterID: "green"}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if got := tags.ClusterID(); got != "green" {
+		t.Errorf("tags.ClusterID() = %q, want green", got)
+	}
+}
+
+func TestSetup_Recorder(t *testing.T) {
+	if err := Setup(context.Background(), newManager(t), nil, ackcfg.Config{}, Config{}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if svcevents.GetRecorder() == nil {
+		t.Error("events.GetRecorder() = nil, want the recorder of the manager")
+	}
+}
+
+func TestSetup_ClusterCache(t *testing.T) {
+	if err := Setup(context.Background(), newManager(t), nil, ackcfg.Config{}, Config{}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if cluster.Reader() == nil {
+		t.Error("cluster.Reader() = nil, want the cluster cache")
+	}
+	if tags.GetPropagator() == nil {
+		t.Error("tags.GetPropagator() = nil, want a propagator reading the cluster cache")
+	}
+}
+
+func TestOnStop(t *testing.T) {
+	stopped := make(chan struct{})
+	runnable := onStop(func(ctx context.Context) error {
+		if err := ctx.Err(); err != nil {
+			t.Errorf("context of the function error = %v, want a live context", err)
+		}
+		close(stopped)
+		return nil
+	})
+	if runnable.NeedLeaderElection() {
+		t.Error("NeedLeaderElection() = true, want the function called on every replica")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- runnable.Start(ctx) }()
+	select {
+	case <-stopped:
+		t.Fatal("function called before the manager stopped")
+	default:
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start() error = %v", err)
+	}
+	select {
+	case <-stopped:
+	default:
+		t.Error("function not called once the manager stopped")
+	}
+}
+
+func TestSetup_OrphanSweep(t *testing.T) {
+	// The AWS config of the Sweeper is loaded from the environment.
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_CA_BUNDLE", "")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	sc := ackrt.NewServiceController("bedrockagent", "bedrockagent.services.k8s.aws", acktypes.VersionInfo{})
+	cfg := Config{OrphanSweep: agent.SweeperOptions{Interval: time.Hour}}
+	ackCfg := ackcfg.Config{Region: "us-west-2"}
+
+	// The agents of the controller are recognized by its resource tags.
+	if err := Setup(context.Background(), newManager(t), sc, ackCfg, cfg); err == nil {
+		t.Error("Setup() without resource tags error = nil, want an error")
+	}
+	ackCfg.ResourceTags = []string{"services.k8s.aws/controller-version=%CONTROLLER_SERVICE%-%CONTROLLER_VERSION%"}
+	if err := Setup(context.Background(), newManager(t), sc, ackCfg, cfg); err != nil {
+		t.Errorf("Setup() error = %v", err)
+	}
+}
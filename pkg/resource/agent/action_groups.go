@@ -0,0 +1,469 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"sort"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+// draftAgentVersion is the working version of an agent. Action groups and
+// knowledge bases can only be modified on the DRAFT version.
+const draftAgentVersion = "DRAFT"
+
+type actionGroupsClient interface {
+	ListAgentActionGroups(context.Context, *svcsdk.ListAgentActionGroupsInput, ...func(*svcsdk.Options)) (*svcsdk.ListAgentActionGroupsOutput, error)
+	GetAgentActionGroup(context.Context, *svcsdk.GetAgentActionGroupInput, ...func(*svcsdk.Options)) (*svcsdk.GetAgentActionGroupOutput, error)
+	CreateAgentActionGroup(context.Context, *svcsdk.CreateAgentActionGroupInput, ...func(*svcsdk.Options)) (*svcsdk.CreateAgentActionGroupOutput, error)
+	UpdateAgentActionGroup(context.Context, *svcsdk.UpdateAgentActionGroupInput, ...func(*svcsdk.Options)) (*svcsdk.UpdateAgentActionGroupOutput, error)
+	DeleteAgentActionGroup(context.Context, *svcsdk.DeleteAgentActionGroupInput, ...func(*svcsdk.Options)) (*svcsdk.DeleteAgentActionGroupOutput, error)
+}
+
+// listActionGroupSummaries returns the summaries of every action group on the
+// agent's DRAFT version.
+func listActionGroupSummaries(
+	ctx context.Context,
+	client actionGroupsClient,
+	metrics metricsRecorder,
+	agentID string,
+) ([]svcsdktypes.ActionGroupSummary, error) {
+	var summaries []svcsdktypes.ActionGroupSummary
+	input := &svcsdk.ListAgentActionGroupsInput{
+		AgentId:      aws.String(agentID),
+		AgentVersion: aws.String(draftAgentVersion),
+	}
+	for {
+		resp, err := client.ListAgentActionGroups(ctx, input)
+		metrics.RecordAPICall("READ_MANY", "ListAgentActionGroups", err)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, resp.ActionGroupSummaries...)
+		if resp.NextToken == nil {
+			return summaries, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
+
+// getActionGroups returns the action groups of the agent's DRAFT version,
+// sorted by name.
+func getActionGroups(
+	ctx context.Context,
+	client actionGroupsClient,
+	metrics metricsRecorder,
+	agentID string,
+) (actionGroups []*svcapitypes.InlineActionGroup, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("hooks.getActionGroups")
+	defer func() {
+		exit(err)
+	}()
+
+	summaries, err := listActionGroupSummaries(ctx, client, metrics, agentID)
+	if err != nil {
+		return nil, err
+	}
+	actionGroups = []*svcapitypes.InlineActionGroup{}
+	for _, summary := range summaries {
+		resp, err := client.GetAgentActionGroup(ctx, &svcsdk.GetAgentActionGroupInput{
+			ActionGroupId: summary.ActionGroupId,
+			AgentId:       aws.String(agentID),
+			AgentVersion:  aws.String(draftAgentVersion),
+		})
+		metrics.RecordAPICall("READ_ONE", "GetAgentActionGroup", err)
+		if err != nil {
+			return nil, err
+		}
+		actionGroups = append(actionGroups, newInlineActionGroup(resp.AgentActionGroup))
+	}
+	sort.Slice(actionGroups, func(i, j int) bool {
+		return aws.ToString(actionGroups[i].ActionGroupName) < aws.ToString(actionGroups[j].ActionGroupName)
+	})
+	return actionGroups, nil
+}
+
+// syncActionGroups creates, updates and deletes the action groups of the
+// agent's DRAFT version so that they match the desired list. Action groups are
+// matched by name. It returns whether any action group was modified.
+func syncActionGroups(
+	ctx context.Context,
+	client actionGroupsClient,
+	metrics metricsRecorder,
+	agentID string,
+	desired []*svcapitypes.InlineActionGroup,
+	latest []*svcapitypes.InlineActionGroup,
+) (modified bool, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("hooks.syncActionGroups")
+	defer func() {
+		exit(err)
+	}()
+
+	summaries, err := listActionGroupSummaries(ctx, client, metrics, agentID)
+	if err != nil {
+		return false, err
+	}
+	existingIDs := make(map[string]*string, len(summaries))
+	for _, summary := range summaries {
+		existingIDs[aws.ToString(summary.ActionGroupName)] = summary.ActionGroupId
+	}
+	latestByName := make(map[string]*svcapitypes.InlineActionGroup, len(latest))
+	for _, actionGroup := range latest {
+		if actionGroup != nil {
+			latestByName[aws.ToString(actionGroup.ActionGroupName)] = actionGroup
+		}
+	}
+	desiredByName := make(map[string]*svcapitypes.InlineActionGroup, len(desired))
+	for _, actionGroup := range desired {
+		if actionGroup != nil {
+			desiredByName[aws.ToString(actionGroup.ActionGroupName)] = actionGroup
+		}
+	}
+
+	// Delete first so that replaced action groups don't count towards the
+	// per-agent action group quota.
+	for name, actionGroupID := range existingIDs {
+		if _, ok := desiredByName[name]; ok {
+			continue
+		}
+		_, err = client.DeleteAgentActionGroup(ctx, &svcsdk.DeleteAgentActionGroupInput{
+			ActionGroupId:          actionGroupID,
+			AgentId:                aws.String(agentID),
+			AgentVersion:           aws.String(draftAgentVersion),
+			SkipResourceInUseCheck: true,
+		})
+		metrics.RecordAPICall("DELETE", "DeleteAgentActionGroup", err)
+		if err != nil {
+			return modified, err
+		}
+		modified = true
+	}
+
+	for _, actionGroup := range desired {
+		if actionGroup == nil {
+			continue
+		}
+		name := aws.ToString(actionGroup.ActionGroupName)
+		actionGroupID, exists := existingIDs[name]
+		if !exists {
+			_, err = client.CreateAgentActionGroup(ctx, newCreateAgentActionGroupInput(agentID, actionGroup))
+			metrics.RecordAPICall("CREATE", "CreateAgentActionGroup", err)
+			if err != nil {
+				return modified, err
+			}
+			modified = true
+			continue
+		}
+		if latestActionGroup, ok := latestByName[name]; ok && equalActionGroups(actionGroup, latestActionGroup) {
+			continue
+		}
+		_, err = client.UpdateAgentActionGroup(ctx, newUpdateAgentActionGroupInput(agentID, aws.ToString(actionGroupID), actionGroup))
+		metrics.RecordAPICall("UPDATE", "UpdateAgentActionGroup", err)
+		if err != nil {
+			return modified, err
+		}
+		modified = true
+	}
+	return modified, nil
+}
+
+// compareActionGroups adds Spec.ActionGroups to the delta when the desired
+// action groups differ from the latest ones. Action groups are only compared
+// when the desired resource declares them.
+func compareActionGroups(
+	delta *ackcompare.Delta,
+	desired *resource,
+	latest *resource,
+) {
+	if desired.ko.Spec.ActionGroups == nil {
+		return
+	}
+	if !equalActionGroupLists(desired.ko.Spec.ActionGroups, latest.ko.Spec.ActionGroups) {
+		delta.Add("Spec.ActionGroups", desired.ko.Spec.ActionGroups, latest.ko.Spec.ActionGroups)
+	}
+}
+
+// equalActionGroupLists returns true if both lists contain the same action
+// groups, regardless of order.
+func equalActionGroupLists(
+	a []*svcapitypes.InlineActionGroup,
+	b []*svcapitypes.InlineActionGroup,
+) bool {
+	aByName := map[string]*svcapitypes.InlineActionGroup{}
+	for _, actionGroup := range a {
+		if actionGroup != nil {
+			aByName[aws.ToString(actionGroup.ActionGroupName)] = actionGroup
+		}
+	}
+	bByName := map[string]*svcapitypes.InlineActionGroup{}
+	for _, actionGroup := range b {
+		if actionGroup != nil {
+			bByName[aws.ToString(actionGroup.ActionGroupName)] = actionGroup
+		}
+	}
+	if len(aByName) != len(bByName) {
+		return false
+	}
+	for name, actionGroup := range aByName {
+		other, ok := bByName[name]
+		if !ok || !equalActionGroups(actionGroup, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalActionGroups compares two action groups after filling in the values
+// Bedrock assigns to omitted fields.
+func equalActionGroups(
+	a *svcapitypes.InlineActionGroup,
+	b *svcapitypes.InlineActionGroup,
+) bool {
+	return equality.Semantic.DeepEqual(
+		normalizeActionGroup(a),
+		normalizeActionGroup(b),
+	)
+}
+
+// normalizeActionGroup returns a copy of the action group with the Bedrock
+// server-side defaults applied and without nil functions.
+func normalizeActionGroup(
+	in *svcapitypes.InlineActionGroup,
+) *svcapitypes.InlineActionGroup {
+	out := in.DeepCopy()
+	if out.ActionGroupState == nil {
+		out.ActionGroupState = aws.String(string(svcsdktypes.ActionGroupStateEnabled))
+	}
+	if out.FunctionSchema != nil && out.FunctionSchema.Functions != nil {
+		functions := make([]*svcapitypes.InlineFunction, 0, len(out.FunctionSchema.Functions))
+		for _, function := range out.FunctionSchema.Functions {
+			if function == nil {
+				continue
+			}
+			if function.RequireConfirmation == nil {
+				function.RequireConfirmation = aws.String(string(svcsdktypes.RequireConfirmationDisabled))
+			}
+			for _, parameter := range function.Parameters {
+				if parameter != nil && parameter.Required == nil {
+					parameter.Required = aws.Bool(false)
+				}
+			}
+			functions = append(functions, function)
+		}
+		sort.Slice(functions, func(i, j int) bool {
+			return aws.ToString(functions[i].Name) < aws.ToString(functions[j].Name)
+		})
+		out.FunctionSchema.Functions = functions
+	}
+	return out
+}
+
+// newInlineActionGroup converts an action group returned by
+// GetAgentActionGroup into its Spec representation.
+func newInlineActionGroup(
+	in *svcsdktypes.AgentActionGroup,
+) *svcapitypes.InlineActionGroup {
+	out := &svcapitypes.InlineActionGroup{
+		ActionGroupName: in.ActionGroupName,
+		Description:     in.Description,
+	}
+	if in.ActionGroupState != "" {
+		out.ActionGroupState = aws.String(string(in.ActionGroupState))
+	}
+	if in.ParentActionSignature != "" {
+		out.ParentActionGroupSignature = aws.String(string(in.ParentActionSignature))
+	}
+	switch executor := in.ActionGroupExecutor.(type) {
+	case *svcsdktypes.ActionGroupExecutorMemberLambda:
+		out.ActionGroupExecutor = &svcapitypes.InlineActionGroupExecutor{
+			Lambda: aws.String(executor.Value),
+		}
+	case *svcsdktypes.ActionGroupExecutorMemberCustomControl:
+		out.ActionGroupExecutor = &svcapitypes.InlineActionGroupExecutor{
+			CustomControl: aws.String(string(executor.Value)),
+		}
+	}
+	switch schema := in.ApiSchema.(type) {
+	case *svcsdktypes.APISchemaMemberPayload:
+		out.APISchema = &svcapitypes.InlineAPISchema{
+			Payload: aws.String(schema.Value),
+		}
+	case *svcsdktypes.APISchemaMemberS3:
+		out.APISchema = &svcapitypes.InlineAPISchema{
+			S3: &svcapitypes.InlineS3Identifier{
+				S3BucketName: schema.Value.S3BucketName,
+				S3ObjectKey:  schema.Value.S3ObjectKey,
+			},
+		}
+	}
+	if schema, ok := in.FunctionSchema.(*svcsdktypes.FunctionSchemaMemberFunctions); ok {
+		out.FunctionSchema = &svcapitypes.InlineFunctionSchema{}
+		for _, function := range schema.Value {
+			outFunction := &svcapitypes.InlineFunction{
+				Description: function.Description,
+				Name:        function.Name,
+			}
+			if function.RequireConfirmation != "" {
+				outFunction.RequireConfirmation = aws.String(string(function.RequireConfirmation))
+			}
+			if function.Parameters != nil {
+				outFunction.Parameters = make(map[string]*svcapitypes.InlineParameterDetail, len(function.Parameters))
+				for key, parameter := range function.Parameters {
+					outFunction.Parameters[key] = &svcapitypes.InlineParameterDetail{
+						Description: parameter.Description,
+						Required:    parameter.Required,
+						Type:        aws.String(string(parameter.Type)),
+					}
+				}
+			}
+			out.FunctionSchema.Functions = append(out.FunctionSchema.Functions, outFunction)
+		}
+	}
+	return out
+}
+
+// newActionGroupExecutor returns the SDK union member for the executor, or nil
+// if none is set.
+func newActionGroupExecutor(
+	in *svcapitypes.InlineActionGroupExecutor,
+) svcsdktypes.ActionGroupExecutor {
+	if in == nil {
+		return nil
+	}
+	if in.Lambda != nil {
+		return &svcsdktypes.ActionGroupExecutorMemberLambda{Value: *in.Lambda}
+	}
+	if in.CustomControl != nil {
+		return &svcsdktypes.ActionGroupExecutorMemberCustomControl{
+			Value: svcsdktypes.CustomControlMethod(*in.CustomControl),
+		}
+	}
+	return nil
+}
+
+// newAPISchema returns the SDK union member for the API schema, or nil if
+// none is set.
+func newAPISchema(
+	in *svcapitypes.InlineAPISchema,
+) svcsdktypes.APISchema {
+	if in == nil {
+		return nil
+	}
+	if in.Payload != nil {
+		return &svcsdktypes.APISchemaMemberPayload{Value: *in.Payload}
+	}
+	if in.S3 != nil {
+		return &svcsdktypes.APISchemaMemberS3{
+			Value: svcsdktypes.S3Identifier{
+				S3BucketName: in.S3.S3BucketName,
+				S3ObjectKey:  in.S3.S3ObjectKey,
+			},
+		}
+	}
+	return nil
+}
+
+// newFunctionSchema returns the SDK union member for the function schema, or
+// nil if none is set.
+func newFunctionSchema(
+	in *svcapitypes.InlineFunctionSchema,
+) svcsdktypes.FunctionSchema {
+	if in == nil {
+		return nil
+	}
+	functions := make([]svcsdktypes.Function, 0, len(in.Functions))
+	for _, function := range in.Functions {
+		if function == nil {
+			continue
+		}
+		outFunction := svcsdktypes.Function{
+			Description: function.Description,
+			Name:        function.Name,
+		}
+		if function.RequireConfirmation != nil {
+			outFunction.RequireConfirmation = svcsdktypes.RequireConfirmation(*function.RequireConfirmation)
+		}
+		if function.Parameters != nil {
+			outFunction.Parameters = make(map[string]svcsdktypes.ParameterDetail, len(function.Parameters))
+			for key, parameter := range function.Parameters {
+				if parameter == nil {
+					continue
+				}
+				outFunction.Parameters[key] = svcsdktypes.ParameterDetail{
+					Description: parameter.Description,
+					Required:    parameter.Required,
+					Type:        svcsdktypes.Type(aws.ToString(parameter.Type)),
+				}
+			}
+		}
+		functions = append(functions, outFunction)
+	}
+	return &svcsdktypes.FunctionSchemaMemberFunctions{Value: functions}
+}
+
+func newCreateAgentActionGroupInput(
+	agentID string,
+	in *svcapitypes.InlineActionGroup,
+) *svcsdk.CreateAgentActionGroupInput {
+	input := &svcsdk.CreateAgentActionGroupInput{
+		ActionGroupExecutor: newActionGroupExecutor(in.ActionGroupExecutor),
+		ActionGroupName:     in.ActionGroupName,
+		AgentId:             aws.String(agentID),
+		AgentVersion:        aws.String(draftAgentVersion),
+		ApiSchema:           newAPISchema(in.APISchema),
+		Description:         in.Description,
+		FunctionSchema:      newFunctionSchema(in.FunctionSchema),
+	}
+	if in.ActionGroupState != nil {
+		input.ActionGroupState = svcsdktypes.ActionGroupState(*in.ActionGroupState)
+	}
+	if in.ParentActionGroupSignature != nil {
+		input.ParentActionGroupSignature = svcsdktypes.ActionGroupSignature(*in.ParentActionGroupSignature)
+	}
+	return input
+}
+
+func newUpdateAgentActionGroupInput(
+	agentID string,
+	actionGroupID string,
+	in *svcapitypes.InlineActionGroup,
+) *svcsdk.UpdateAgentActionGroupInput {
+	input := &svcsdk.UpdateAgentActionGroupInput{
+		ActionGroupExecutor: newActionGroupExecutor(in.ActionGroupExecutor),
+		ActionGroupId:       aws.String(actionGroupID),
+		ActionGroupName:     in.ActionGroupName,
+		AgentId:             aws.String(agentID),
+		AgentVersion:        aws.String(draftAgentVersion),
+		ApiSchema:           newAPISchema(in.APISchema),
+		Description:         in.Description,
+		FunctionSchema:      newFunctionSchema(in.FunctionSchema),
+	}
+	if in.ActionGroupState != nil {
+		input.ActionGroupState = svcsdktypes.ActionGroupState(*in.ActionGroupState)
+	}
+	if in.ParentActionGroupSignature != nil {
+		input.ParentActionGroupSignature = svcsdktypes.ActionGroupSignature(*in.ParentActionGroupSignature)
+	}
+	return input
+}
@@ -0,0 +1,303 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+// mockActionGroupsClient is a mock implementation of the actionGroupsClient
+// interface. Mutating calls are recorded in calls as "<Operation>:<name or ID>".
+type mockActionGroupsClient struct {
+	summaries    []svcsdktypes.ActionGroupSummary
+	actionGroups map[string]*svcsdktypes.AgentActionGroup
+	deleteErr    error
+	calls        []string
+}
+
+func (m *mockActionGroupsClient) ListAgentActionGroups(ctx context.Context, input *svcsdk.ListAgentActionGroupsInput, opts ...func(*svcsdk.Options)) (*svcsdk.ListAgentActionGroupsOutput, error) {
+	// Return one summary per page to exercise pagination.
+	page := 0
+	if input.NextToken != nil {
+		page = len(*input.NextToken)
+	}
+	if page >= len(m.summaries) {
+		return &svcsdk.ListAgentActionGroupsOutput{}, nil
+	}
+	out := &svcsdk.ListAgentActionGroupsOutput{
+		ActionGroupSummaries: m.summaries[page : page+1],
+	}
+	if page+1 < len(m.summaries) {
+		out.NextToken = aws.String(strings.Repeat("x", page+1))
+	}
+	return out, nil
+}
+
+func (m *mockActionGroupsClient) GetAgentActionGroup(ctx context.Context, input *svcsdk.GetAgentActionGroupInput, opts ...func(*svcsdk.Options)) (*svcsdk.GetAgentActionGroupOutput, error) {
+	actionGroup, ok := m.actionGroups[aws.ToString(input.ActionGroupId)]
+	if !ok {
+		return nil, errors.New("action group not found")
+	}
+	return &svcsdk.GetAgentActionGroupOutput{AgentActionGroup: actionGroup}, nil
+}
+
+func (m *mockActionGroupsClient) CreateAgentActionGroup(ctx context.Context, input *svcsdk.CreateAgentActionGroupInput, opts ...func(*svcsdk.Options)) (*svcsdk.CreateAgentActionGroupOutput, error) {
+	m.calls = append(m.calls, "CreateAgentActionGroup:"+aws.ToString(input.ActionGroupName))
+	return &svcsdk.CreateAgentActionGroupOutput{}, nil
+}
+
+func (m *mockActionGroupsClient) UpdateAgentActionGroup(ctx context.Context, input *svcsdk.UpdateAgentActionGroupInput, opts ...func(*svcsdk.Options)) (*svcsdk.UpdateAgentActionGroupOutput, error) {
+	m.calls = append(m.calls, "UpdateAgentActionGroup:"+aws.ToString(input.ActionGroupId))
+	return &svcsdk.UpdateAgentActionGroupOutput{}, nil
+}
+
+func (m *mockActionGroupsClient) DeleteAgentActionGroup(ctx context.Context, input *svcsdk.DeleteAgentActionGroupInput, opts ...func(*svcsdk.Options)) (*svcsdk.DeleteAgentActionGroupOutput, error) {
+	m.calls = append(m.calls, "DeleteAgentActionGroup:"+aws.ToString(input.ActionGroupId))
+	if !input.SkipResourceInUseCheck {
+		return nil, errors.New("expected SkipResourceInUseCheck to be set")
+	}
+	return &svcsdk.DeleteAgentActionGroupOutput{}, m.deleteErr
+}
+
+// mockMetricsRecorder is a mock implementation of the metricsRecorder interface
+type mockMetricsRecorder struct{}
+
+func (m *mockMetricsRecorder) RecordAPICall(opType string, opID string, err error) {}
+
+func returnControlActionGroup(name string) *svcapitypes.InlineActionGroup {
+	return &svcapitypes.InlineActionGroup{
+		ActionGroupName: aws.String(name),
+		ActionGroupExecutor: &svcapitypes.InlineActionGroupExecutor{
+			CustomControl: aws.String("RETURN_CONTROL"),
+		},
+		FunctionSchema: &svcapitypes.InlineFunctionSchema{
+			Functions: []*svcapitypes.InlineFunction{
+				{
+					Name: aws.String("lookup"),
+					Parameters: map[string]*svcapitypes.InlineParameterDetail{
+						"id": {Type: aws.String("string")},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestGetActionGroups(t *testing.T) {
+	client := &mockActionGroupsClient{
+		summaries: []svcsdktypes.ActionGroupSummary{
+			{ActionGroupId: aws.String("AG2"), ActionGroupName: aws.String("weather")},
+			{ActionGroupId: aws.String("AG1"), ActionGroupName: aws.String("user-input")},
+		},
+		actionGroups: map[string]*svcsdktypes.AgentActionGroup{
+			"AG1": {
+				ActionGroupName:       aws.String("user-input"),
+				ActionGroupState:      svcsdktypes.ActionGroupStateEnabled,
+				ParentActionSignature: svcsdktypes.ActionGroupSignatureAmazonUserinput,
+			},
+			"AG2": {
+				ActionGroupName:     aws.String("weather"),
+				ActionGroupState:    svcsdktypes.ActionGroupStateDisabled,
+				ActionGroupExecutor: &svcsdktypes.ActionGroupExecutorMemberLambda{Value: "arn:aws:lambda:us-west-2:123456789012:function:weather"},
+				ApiSchema:           &svcsdktypes.APISchemaMemberPayload{Value: "openapi: 3.0.0"},
+			},
+		},
+	}
+
+	got, err := getActionGroups(context.TODO(), client, &mockMetricsRecorder{}, "AGENT12345")
+	if err != nil {
+		t.Fatalf("getActionGroups() error = %v", err)
+	}
+	want := []*svcapitypes.InlineActionGroup{
+		{
+			ActionGroupName:            aws.String("user-input"),
+			ActionGroupState:           aws.String("ENABLED"),
+			ParentActionGroupSignature: aws.String("AMAZON.UserInput"),
+		},
+		{
+			ActionGroupName:  aws.String("weather"),
+			ActionGroupState: aws.String("DISABLED"),
+			ActionGroupExecutor: &svcapitypes.InlineActionGroupExecutor{
+				Lambda: aws.String("arn:aws:lambda:us-west-2:123456789012:function:weather"),
+			},
+			APISchema: &svcapitypes.InlineAPISchema{Payload: aws.String("openapi: 3.0.0")},
+		},
+	}
+	if !equalActionGroupLists(got, want) || aws.ToString(got[0].ActionGroupName) != "user-input" {
+		t.Errorf("getActionGroups() = %v, want %v", got, want)
+	}
+}
+
+func TestSyncActionGroups(t *testing.T) {
+	existing := []svcsdktypes.ActionGroupSummary{
+		{ActionGroupId: aws.String("AG1"), ActionGroupName: aws.String("unchanged")},
+		{ActionGroupId: aws.String("AG2"), ActionGroupName: aws.String("changed")},
+		{ActionGroupId: aws.String("AG3"), ActionGroupName: aws.String("removed")},
+	}
+	changed := returnControlActionGroup("changed")
+	changed.Description = aws.String("new description")
+
+	tests := []struct {
+		name         string
+		summaries    []svcsdktypes.ActionGroupSummary
+		desired      []*svcapitypes.InlineActionGroup
+		latest       []*svcapitypes.InlineActionGroup
+		deleteErr    error
+		wantCalls    []string
+		wantModified bool
+		wantErr      bool
+	}{
+		{
+			name:      "create, update and delete",
+			summaries: existing,
+			desired: []*svcapitypes.InlineActionGroup{
+				returnControlActionGroup("unchanged"),
+				changed,
+				returnControlActionGroup("added"),
+			},
+			latest: []*svcapitypes.InlineActionGroup{
+				returnControlActionGroup("changed"),
+				returnControlActionGroup("removed"),
+				returnControlActionGroup("unchanged"),
+			},
+			wantCalls: []string{
+				"CreateAgentActionGroup:added",
+				"DeleteAgentActionGroup:AG3",
+				"UpdateAgentActionGroup:AG2",
+			},
+			wantModified: true,
+		},
+		{
+			name:      "nothing to do",
+			summaries: existing[:1],
+			desired:   []*svcapitypes.InlineActionGroup{returnControlActionGroup("unchanged")},
+			latest:    []*svcapitypes.InlineActionGroup{returnControlActionGroup("unchanged")},
+			wantCalls: []string{},
+		},
+		{
+			name:      "empty desired list deletes everything",
+			summaries: existing,
+			desired:   []*svcapitypes.InlineActionGroup{},
+			wantCalls: []string{
+				"DeleteAgentActionGroup:AG1",
+				"DeleteAgentActionGroup:AG2",
+				"DeleteAgentActionGroup:AG3",
+			},
+			wantModified: true,
+		},
+		{
+			name:      "delete error",
+			summaries: existing[2:],
+			desired:   []*svcapitypes.InlineActionGroup{returnControlActionGroup("added")},
+			deleteErr: errors.New("API error"),
+			wantCalls: []string{"DeleteAgentActionGroup:AG3"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockActionGroupsClient{
+				summaries: tt.summaries,
+				deleteErr: tt.deleteErr,
+				calls:     []string{},
+			}
+			modified, err := syncActionGroups(context.TODO(), client, &mockMetricsRecorder{}, "AGENT12345", tt.desired, tt.latest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("syncActionGroups() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if modified != tt.wantModified {
+				t.Errorf("syncActionGroups() modified = %v, want %v", modified, tt.wantModified)
+			}
+			sort.Strings(client.calls)
+			if strings.Join(client.calls, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("syncActionGroups() calls = %v, want %v", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCompareActionGroups(t *testing.T) {
+	withDefaults := returnControlActionGroup("lookup")
+	withDefaults.ActionGroupState = aws.String("ENABLED")
+	withDefaults.FunctionSchema.Functions[0].RequireConfirmation = aws.String("DISABLED")
+	withDefaults.FunctionSchema.Functions[0].Parameters["id"].Required = aws.Bool(false)
+
+	disabled := returnControlActionGroup("lookup")
+	disabled.ActionGroupState = aws.String("DISABLED")
+
+	withNilFunction := returnControlActionGroup("lookup")
+	withNilFunction.FunctionSchema.Functions = append(withNilFunction.FunctionSchema.Functions, nil)
+
+	tests := []struct {
+		name      string
+		desired   []*svcapitypes.InlineActionGroup
+		latest    []*svcapitypes.InlineActionGroup
+		wantDelta bool
+	}{
+		{
+			name:   "unmanaged",
+			latest: []*svcapitypes.InlineActionGroup{returnControlActionGroup("lookup")},
+		},
+		{
+			name:    "server-side defaults are ignored",
+			desired: []*svcapitypes.InlineActionGroup{returnControlActionGroup("lookup")},
+			latest:  []*svcapitypes.InlineActionGroup{withDefaults},
+		},
+		{
+			name:    "nil functions are ignored",
+			desired: []*svcapitypes.InlineActionGroup{withNilFunction},
+			latest:  []*svcapitypes.InlineActionGroup{withDefaults},
+		},
+		{
+			name:      "changed state",
+			desired:   []*svcapitypes.InlineActionGroup{disabled},
+			latest:    []*svcapitypes.InlineActionGroup{withDefaults},
+			wantDelta: true,
+		},
+		{
+			name:      "missing action group",
+			desired:   []*svcapitypes.InlineActionGroup{returnControlActionGroup("lookup"), returnControlActionGroup("other")},
+			latest:    []*svcapitypes.InlineActionGroup{withDefaults},
+			wantDelta: true,
+		},
+		{
+			name:      "extra action group",
+			desired:   []*svcapitypes.InlineActionGroup{},
+			latest:    []*svcapitypes.InlineActionGroup{withDefaults},
+			wantDelta: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := &resource{ko: &svcapitypes.Agent{Spec: svcapitypes.AgentSpec{ActionGroups: tt.desired}}}
+			latest := &resource{ko: &svcapitypes.Agent{Spec: svcapitypes.AgentSpec{ActionGroups: tt.latest}}}
+			delta := ackcompare.NewDelta()
+			compareActionGroups(delta, desired, latest)
+			if got := delta.DifferentAt("Spec.ActionGroups"); got != tt.wantDelta {
+				t.Errorf("compareActionGroups() delta = %v, want %v", got, tt.wantDelta)
+			}
+		})
+	}
+}
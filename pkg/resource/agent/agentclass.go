@@ -0,0 +1,92 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/cluster"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/agentclass"
+)
+
+// +kubebuilder:rbac:groups=bedrockagent.services.k8s.aws,resources=agentclasses,verbs=get;list;watch
+
+// applyAgentClass merges the defaults of the AgentClass of the agent into its
+// desired spec, and records the class and the fields it set in the status.
+// The stored spec is left alone, since the defaults are on both sides of the
+// patch of the agent. The idle session TTL Bedrock assigns is then applied
+// the same way, so that a class setting it later still applies. It returns
+// whether the agent has a class, and an error when the class it names
+// doesn't exist.
+func (rm *resourceManager) applyAgentClass(
+	ctx context.Context,
+	apiReader client.Reader,
+	ko *svcapitypes.Agent,
+) (bool, error) {
+	ko.Status.AgentClass = nil
+	class, err := agentclass.ForAgent(ctx, clusterReader(apiReader), ko)
+	if err != nil {
+		return ko.Spec.AgentClassName != nil, err
+	}
+	if class != nil {
+		spec := class.Spec
+		if ko.Status.AgentID != nil {
+			// The key of an agent cannot change, see keepEncryptionKey.
+			spec.CustomerEncryptionKeyARN = nil
+		}
+		fields := agentclass.Apply(&ko.Spec, &spec)
+		ko.Status.AgentClass = &svcapitypes.AppliedAgentClass{
+			Fields: aws.StringSlice(fields),
+			Name:   aws.String(class.Name),
+		}
+	}
+	if ko.Spec.IdleSessionTTLInSeconds == nil {
+		ko.Spec.IdleSessionTTLInSeconds = aws.Int64(defaultIdleSessionTTLInSeconds)
+	}
+	return class != nil, nil
+}
+
+// keepEncryptionKey returns copies of the agents with the same
+// customerEncryptionKeyARN when only one of them sets it, so that the key
+// isn't compared. The key of an agent cannot change: it is only applied from
+// its AgentClass at creation, and the webhook keeps it from changing in the
+// stored spec.
+func keepEncryptionKey(a *resource, b *resource) (*resource, *resource) {
+	keyA, keyB := a.ko.Spec.CustomerEncryptionKeyARN, b.ko.Spec.CustomerEncryptionKeyARN
+	switch {
+	case keyA == nil && keyB != nil:
+		ko := a.ko.DeepCopy()
+		ko.Spec.CustomerEncryptionKeyARN = keyB
+		a = &resource{ko}
+	case keyA != nil && keyB == nil:
+		ko := b.ko.DeepCopy()
+		ko.Spec.CustomerEncryptionKeyARN = keyA
+		b = &resource{ko}
+	}
+	return a, b
+}
+
+// clusterReader returns the cache of the cluster, which the AgentClasses are
+// read from, or the reader of the runtime when the controller hasn't set it,
+// see cluster.SetReader.
+func clusterReader(apiReader client.Reader) client.Reader {
+	if reader := cluster.Reader(); reader != nil {
+		return reader
+	}
+	return apiReader
+}
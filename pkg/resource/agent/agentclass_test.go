@@ -0,0 +1,208 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/agentclass"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+// newAgentClassReader returns a reader holding the classes.
+func newAgentClassReader(t *testing.T, classes ...*svcapitypes.AgentClass) client.Reader {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	for _, class := range classes {
+		builder = builder.WithObjects(class)
+	}
+	return builder.Build()
+}
+
+func TestAgentClass(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+	reader := newAgentClassReader(t,
+		&svcapitypes.AgentClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "support"},
+			Spec: svcapitypes.AgentClassSpec{
+				AgentResourceRoleARN:    aws.String("arn:aws:iam::123456789012:role/support-role"),
+				FoundationModel:         aws.String("anthropic.claude-3-sonnet-20240229-v1:0"),
+				IdleSessionTTLInSeconds: aws.Int64(900),
+			},
+		},
+		&svcapitypes.AgentClass{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "platform",
+				Annotations: map[string]string{agentclass.DefaultClassAnnotation: "true"},
+			},
+			Spec: svcapitypes.AgentClassSpec{
+				GuardrailConfiguration: &svcapitypes.GuardrailConfiguration{
+					GuardrailIdentifier: aws.String("abc123"),
+					GuardrailVersion:    aws.String("1"),
+				},
+			},
+		},
+	)
+
+	// The class fills in the fields the agent leaves unset.
+	desired := dryRunAgent()
+	desired.ko.Annotations = nil
+	desired.ko.Spec.AgentClassName = aws.String("support")
+	desired.ko.Spec.AgentResourceRoleARN = nil
+	desired.ko.Spec.IdleSessionTTLInSeconds = nil
+	resolved, hasReferences, err := rm.ResolveReferences(ctx, reader, desired)
+	if err != nil {
+		t.Fatalf("ResolveReferences() error = %v", err)
+	}
+	if !hasReferences {
+		t.Errorf("ResolveReferences() hasReferences = false, want true for an agent with a class")
+	}
+	ko := resolved.(*resource).ko
+	if got := aws.ToString(ko.Spec.AgentResourceRoleARN); got != "arn:aws:iam::123456789012:role/support-role" {
+		t.Errorf("agentResourceRoleARN = %q, want the role of the class", got)
+	}
+	if got := aws.ToString(ko.Spec.FoundationModel); got != "anthropic.claude-3-haiku-20240307-v1:0" {
+		t.Errorf("foundationModel = %q, want the model of the agent", got)
+	}
+	if got := aws.ToInt64(ko.Spec.IdleSessionTTLInSeconds); got != 900 {
+		t.Errorf("idleSessionTTLInSeconds = %d, want the TTL of the class", got)
+	}
+	if ko.Spec.GuardrailConfiguration != nil {
+		t.Errorf("guardrailConfiguration = %v, want none from the default class", ko.Spec.GuardrailConfiguration)
+	}
+	want := &svcapitypes.AppliedAgentClass{
+		Fields: aws.StringSlice([]string{"agentResourceRoleARN", "idleSessionTTLInSeconds"}),
+		Name:   aws.String("support"),
+	}
+	if !reflect.DeepEqual(ko.Status.AgentClass, want) {
+		t.Errorf("status.agentClass = %+v, want %+v", ko.Status.AgentClass, want)
+	}
+
+	res, err := rm.Create(ctx, resolved)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if got := aws.ToInt64(res.(*resource).ko.Spec.IdleSessionTTLInSeconds); got != 900 {
+		t.Errorf("created idleSessionTTLInSeconds = %d, want the TTL of the class", got)
+	}
+
+	// The default class applies to the agents naming no class.
+	desired = dryRunAgent()
+	resolved, _, err = rm.ResolveReferences(ctx, reader, desired)
+	if err != nil {
+		t.Fatalf("ResolveReferences() error = %v", err)
+	}
+	ko = resolved.(*resource).ko
+	if got := aws.ToString(ko.Status.AgentClass.Name); got != "platform" {
+		t.Errorf("status.agentClass.name = %q, want the default class", got)
+	}
+	if ko.Spec.GuardrailConfiguration == nil || aws.ToString(ko.Spec.GuardrailConfiguration.GuardrailIdentifier) != "abc123" {
+		t.Errorf("guardrailConfiguration = %v, want the guardrail of the default class", ko.Spec.GuardrailConfiguration)
+	}
+
+	// A class that doesn't exist is reported, and nothing is applied.
+	desired = dryRunAgent()
+	desired.ko.Spec.AgentClassName = aws.String("missing")
+	desired.ko.Status.AgentClass = want
+	resolved, _, err = rm.ResolveReferences(ctx, reader, desired)
+	if err == nil {
+		t.Fatalf("ResolveReferences() error = nil, want an error for a missing class")
+	}
+	if ko := resolved.(*resource).ko; ko.Status.AgentClass != nil {
+		t.Errorf("status.agentClass = %+v, want none for a missing class", ko.Status.AgentClass)
+	}
+}
+
+func TestAgentClass_IdleSessionTTL(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	// The defaulting webhook leaves the TTL unset, so that a class setting
+	// it later still applies.
+	ko := &svcapitypes.Agent{Spec: svcapitypes.AgentSpec{AgentName: aws.String("my-agent")}}
+	if err := (&agentDefaulter{}).Default(ctx, ko); err != nil {
+		t.Fatalf("Default() error = %v", err)
+	}
+	if ko.Spec.IdleSessionTTLInSeconds != nil {
+		t.Errorf("idleSessionTTLInSeconds = %d, want it left to the class", *ko.Spec.IdleSessionTTLInSeconds)
+	}
+
+	// The TTL Bedrock assigns applies to the desired spec when neither the
+	// agent nor its class set one.
+	desired := dryRunAgent()
+	desired.ko.Spec.IdleSessionTTLInSeconds = nil
+	resolved, _, err := rm.ResolveReferences(ctx, newAgentClassReader(t), desired)
+	if err != nil {
+		t.Fatalf("ResolveReferences() error = %v", err)
+	}
+	if got := aws.ToInt64(resolved.(*resource).ko.Spec.IdleSessionTTLInSeconds); got != defaultIdleSessionTTLInSeconds {
+		t.Errorf("idleSessionTTLInSeconds = %d, want the default without a class", got)
+	}
+}
+
+func TestAgentClass_EncryptionKeyAtCreation(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+	key := "arn:aws:kms:us-west-2:123456789012:key/11111111-2222-3333-4444-555555555555"
+	reader := newAgentClassReader(t, &svcapitypes.AgentClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "encrypted"},
+		Spec:       svcapitypes.AgentClassSpec{CustomerEncryptionKeyARN: aws.String(key)},
+	})
+
+	desired := dryRunAgent()
+	desired.ko.Spec.AgentClassName = aws.String("encrypted")
+	resolved, _, err := rm.ResolveReferences(ctx, reader, desired.DeepCopy())
+	if err != nil {
+		t.Fatalf("ResolveReferences() error = %v", err)
+	}
+	if got := aws.ToString(resolved.(*resource).ko.Spec.CustomerEncryptionKeyARN); got != key {
+		t.Errorf("customerEncryptionKeyARN = %q, want the key of the class at creation", got)
+	}
+
+	// Once the agent exists, a new key in the class isn't applied, and the
+	// key of the agent isn't reported as changed.
+	latest := resolved.DeepCopy().(*resource)
+	desired.ko.Status.AgentID = aws.String("AGENT12345")
+	reader = newAgentClassReader(t, &svcapitypes.AgentClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "encrypted"},
+		Spec:       svcapitypes.AgentClassSpec{CustomerEncryptionKeyARN: aws.String(key + "-rotated")},
+	})
+	resolved, _, err = rm.ResolveReferences(ctx, reader, desired.DeepCopy())
+	if err != nil {
+		t.Fatalf("ResolveReferences() error = %v", err)
+	}
+	if got := resolved.(*resource).ko.Spec.CustomerEncryptionKeyARN; got != nil {
+		t.Errorf("customerEncryptionKeyARN = %q, want none from the class once created", *got)
+	}
+	if delta := newResourceDelta(resolved.(*resource), latest); delta.DifferentAt("Spec.CustomerEncryptionKeyARN") {
+		t.Errorf("delta = %v, want the key of the agent kept", delta.Differences)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/plan"
+)
+
+// ConditionTypeAwaitingApproval reports, for an agent in approval mode, that
+// its changes wait for the ApprovedPlanAnnotation to carry the hash of their
+// plan.
+const ConditionTypeAwaitingApproval ackv1alpha1.ConditionType = "AwaitingApproval"
+
+// awaitApproval returns the desired agent, with the status of the latest one
+// and the plan of its changes, when the changes wait for an approval. It
+// returns false when the changes can be applied: the agent isn't in approval
+// mode, only needs to be prepared again, or its plan is approved.
+func awaitApproval(
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (*resource, bool) {
+	if !plan.RequiresApproval(desired.ko) {
+		return nil, false
+	}
+	p := newUpdatePlan(desired, latest, delta)
+	if len(p.Changes) == 0 {
+		return nil, false
+	}
+	hash := p.Hash(desired.ko.Generation)
+	if plan.IsApproved(desired.ko, hash) {
+		setCondition(desired.ko, ConditionTypeAwaitingApproval, corev1.ConditionFalse,
+			aws.String(fmt.Sprintf("Applying the approved plan %s", hash)))
+		return nil, false
+	}
+
+	awaiting := &resource{ko: desired.ko.DeepCopy()}
+	latest.ko.Status.DeepCopyInto(&awaiting.ko.Status)
+	awaiting.ko.Status.Plan = aws.String(p.String())
+	message := fmt.Sprintf(
+		"%s. Approve the plan with the annotation %s: %s",
+		p.Summary(), plan.ApprovedPlanAnnotation, hash,
+	)
+	setCondition(awaiting.ko, ConditionTypeAwaitingApproval, corev1.ConditionTrue, aws.String(message))
+	// The agent isn't synced, so that it is requeued and the approval, which
+	// doesn't change the generation, is noticed.
+	ackcondition.SetSynced(awaiting, corev1.ConditionFalse, aws.String("Changes await approval"), nil)
+	recordApprovalEvent(awaiting.ko, hash)
+	return awaiting, true
+}
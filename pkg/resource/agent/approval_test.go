@@ -0,0 +1,107 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/plan"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func TestApproval_Update(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	// The agent is created, then put in approval mode.
+	desired := dryRunAgent()
+	desired.ko.Annotations = map[string]string{plan.RequireApprovalAnnotation: "true"}
+	desired.ko.Generation = 1
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	desired.ko.Status = res.(*resource).ko.Status
+	waitForAgent(t, rm, desired)
+	syncAgent(t, rm, desired)
+	waitForAgent(t, rm, desired)
+
+	desired.ko.Generation = 2
+	desired.ko.Spec.Instruction = aws.String(strings.Repeat("You are a concise support agent. ", 5))
+	server.ResetCalls()
+
+	update := func() *resource {
+		t.Helper()
+		latest := waitForAgent(t, rm, desired)
+		res, err := rm.Update(ctx, desired, latest, newResourceDelta(desired, latest))
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		return res.(*resource)
+	}
+	awaitingHash := func(r *resource) string {
+		t.Helper()
+		c := agentCondition(r.ko, ConditionTypeAwaitingApproval)
+		if c == nil || c.Status != corev1.ConditionTrue {
+			t.Fatalf("AwaitingApproval condition = %v, want True", c)
+		}
+		message := aws.ToString(c.Message)
+		return message[strings.LastIndex(message, " ")+1:]
+	}
+
+	awaiting := update()
+	hash := awaitingHash(awaiting)
+	if got := aws.ToString(awaiting.ko.Status.Plan); !strings.Contains(got, "Spec.Instruction") {
+		t.Errorf("plan = %q, want the Instruction change", got)
+	}
+	if c := ackcondition.Synced(awaiting); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("Synced condition = %v, want False while changes await approval", c)
+	}
+
+	// The approval of an earlier plan doesn't apply to a later spec.
+	desired.ko.Annotations[plan.ApprovedPlanAnnotation] = hash
+	desired.ko.Generation = 3
+	desired.ko.Spec.Instruction = aws.String(strings.Repeat("You are a curt support agent. ", 5))
+	if next := awaitingHash(update()); next == hash {
+		t.Errorf("the hash of the plan of generation 3 is the one of generation 2")
+	}
+	for _, call := range server.Calls() {
+		switch call {
+		case "GetAgent", "ListTagsForResource":
+		default:
+			t.Errorf("%s called before the approval", call)
+		}
+	}
+
+	desired.ko.Annotations[plan.ApprovedPlanAnnotation] = awaitingHash(update())
+	approved := update()
+	if c := agentCondition(approved.ko, ConditionTypeAwaitingApproval); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("AwaitingApproval condition = %v, want False once approved", c)
+	}
+	var updated, prepared bool
+	for _, call := range server.Calls() {
+		updated = updated || call == "UpdateAgent"
+		prepared = prepared || call == "PrepareAgent"
+	}
+	if !updated || !prepared {
+		t.Errorf("calls = %v, want UpdateAgent and PrepareAgent once approved", server.Calls())
+	}
+}
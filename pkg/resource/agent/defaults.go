@@ -0,0 +1,51 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+const (
+	// defaultIdleSessionTTLInSeconds is the idle session timeout Bedrock
+	// assigns to agents created without one.
+	defaultIdleSessionTTLInSeconds = 600
+	// defaultMemoryStorageDays is the memory retention Bedrock assigns to
+	// agents with memory enabled but no storageDays.
+	defaultMemoryStorageDays = 30
+)
+
+// setAgentSpecDefaults fills in the values the Bedrock Agent API assigns
+// server-side when they are omitted from CreateAgent/UpdateAgent. Applying
+// them up front keeps the stored spec identical to what GetAgent reports, so
+// omitted fields don't show up as a permanent difference in the delta.
+//
+// PromptOverrideConfiguration is intentionally left alone: the DEFAULT
+// prompt templates are model specific and are still late initialized from
+// the GetAgent output.
+// IdleSessionTTLInSeconds is left to the AgentClass of the agent, and only
+// defaulted in its desired spec, see applyAgentClass.
+func setAgentSpecDefaults(spec *svcapitypes.AgentSpec) {
+	if spec.AgentCollaboration == nil {
+		spec.AgentCollaboration = aws.String(string(svcapitypes.AgentCollaboration_DISABLED))
+	}
+	if spec.OrchestrationType == nil {
+		spec.OrchestrationType = aws.String(string(svcapitypes.OrchestrationType_DEFAULT))
+	}
+	if spec.MemoryConfiguration != nil && spec.MemoryConfiguration.StorageDays == nil {
+		spec.MemoryConfiguration.StorageDays = aws.Int64(defaultMemoryStorageDays)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+func TestSetAgentSpecDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		spec svcapitypes.AgentSpec
+		want svcapitypes.AgentSpec
+	}{
+		{
+			name: "empty spec",
+			spec: svcapitypes.AgentSpec{
+				AgentName: aws.String("my-agent"),
+			},
+			want: svcapitypes.AgentSpec{
+				AgentName:          aws.String("my-agent"),
+				AgentCollaboration: aws.String("DISABLED"),
+				OrchestrationType:  aws.String("DEFAULT"),
+			},
+		},
+		{
+			name: "user values are kept",
+			spec: svcapitypes.AgentSpec{
+				AgentCollaboration:      aws.String("SUPERVISOR"),
+				OrchestrationType:       aws.String("CUSTOM_ORCHESTRATION"),
+				IdleSessionTTLInSeconds: aws.Int64(900),
+				MemoryConfiguration: &svcapitypes.MemoryConfiguration{
+					EnabledMemoryTypes: []*string{aws.String("SESSION_SUMMARY")},
+					StorageDays:        aws.Int64(7),
+				},
+			},
+			want: svcapitypes.AgentSpec{
+				AgentCollaboration:      aws.String("SUPERVISOR"),
+				OrchestrationType:       aws.String("CUSTOM_ORCHESTRATION"),
+				IdleSessionTTLInSeconds: aws.Int64(900),
+				MemoryConfiguration: &svcapitypes.MemoryConfiguration{
+					EnabledMemoryTypes: []*string{aws.String("SESSION_SUMMARY")},
+					StorageDays:        aws.Int64(7),
+				},
+			},
+		},
+		{
+			name: "memory storage days",
+			spec: svcapitypes.AgentSpec{
+				MemoryConfiguration: &svcapitypes.MemoryConfiguration{
+					EnabledMemoryTypes: []*string{aws.String("SESSION_SUMMARY")},
+				},
+			},
+			want: svcapitypes.AgentSpec{
+				AgentCollaboration: aws.String("DISABLED"),
+				OrchestrationType:  aws.String("DEFAULT"),
+				MemoryConfiguration: &svcapitypes.MemoryConfiguration{
+					EnabledMemoryTypes: []*string{aws.String("SESSION_SUMMARY")},
+					StorageDays:        aws.Int64(30),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setAgentSpecDefaults(&tt.spec)
+			if !reflect.DeepEqual(tt.spec, tt.want) {
+				t.Errorf("setAgentSpecDefaults() = %+v, want %+v", tt.spec, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentDefaulter_DefaultedSpecIsValid(t *testing.T) {
+	ko := &svcapitypes.Agent{
+		Spec: svcapitypes.AgentSpec{
+			AgentName:            aws.String("my-agent"),
+			AgentResourceRoleARN: aws.String("arn:aws:iam::123456789012:role/agent-role"),
+		},
+	}
+	if err := (&agentDefaulter{}).Default(context.Background(), ko); err != nil {
+		t.Fatalf("Default() unexpected error = %v", err)
+	}
+	if _, err := (&agentValidator{}).ValidateCreate(context.Background(), ko); err != nil {
+		t.Errorf("ValidateCreate() on defaulted spec unexpected error = %v", err)
+	}
+}
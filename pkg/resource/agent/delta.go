@@ -41,11 +41,19 @@ func newResourceDelta(
 		delta.Add("", a, b)
 		return delta
 	}
+	// A rolled back agent is compared with the spec of the revision it
+	// names, see revisions.go.
+	a = compareRollback(delta, a)
+	// A key set on one side only isn't compared, see agentclass.go.
+	a, b = keepEncryptionKey(a, b)
+
 	// Hack to ensure that reconcile loop triggers update for PrepareAgent call
 	// if AgentStatus is not in PREPARED state.
 	compareAgentStatus(delta, b.ko.Status.AgentStatus)
 
 	comparePropertyOverrideConfiguration(delta, a, b)
+	compareActionGroups(delta, a, b)
+	compareKnowledgeBases(delta, a, b)
 
 	if ackcompare.HasNilDifference(a.ko.Spec.AgentCollaboration, b.ko.Spec.AgentCollaboration) {
 		delta.Add("Spec.AgentCollaboration", a.ko.Spec.AgentCollaboration, b.ko.Spec.AgentCollaboration)
@@ -0,0 +1,426 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+// agentGenerator generates random Agents. Values are drawn from small pools
+// so that two generated Agents often share fields.
+type agentGenerator struct {
+	*rand.Rand
+}
+
+func (g agentGenerator) pick(values ...string) *string {
+	// One in four values is left unset.
+	i := g.Intn(len(values) + len(values)/3 + 1)
+	if i >= len(values) {
+		return nil
+	}
+	return aws.String(values[i])
+}
+
+func (g agentGenerator) int64(values ...int64) *int64 {
+	i := g.Intn(len(values) + 1)
+	if i == len(values) {
+		return nil
+	}
+	return aws.Int64(values[i])
+}
+
+func (g agentGenerator) float64(values ...float64) *float64 {
+	i := g.Intn(len(values) + 1)
+	if i == len(values) {
+		return nil
+	}
+	return aws.Float64(values[i])
+}
+
+// strings returns a nil, empty or non-empty list.
+func (g agentGenerator) strings(values ...string) []*string {
+	switch g.Intn(3) {
+	case 0:
+		return nil
+	case 1:
+		return []*string{}
+	}
+	var out []*string
+	for _, value := range values {
+		if g.Intn(2) == 0 {
+			out = append(out, aws.String(value))
+		}
+	}
+	return out
+}
+
+func (g agentGenerator) promptConfiguration(promptType string) *svcapitypes.PromptConfiguration {
+	promptConfig := &svcapitypes.PromptConfiguration{
+		BasePromptTemplate: g.pick("Default template.", "You are a support agent. $instruction$"),
+		FoundationModel:    g.pick("anthropic.claude-3-haiku-20240307-v1:0"),
+		ParserMode:         g.pick("DEFAULT", "OVERRIDDEN"),
+		PromptCreationMode: g.pick("DEFAULT", "OVERRIDDEN"),
+		PromptState:        g.pick("ENABLED", "DISABLED"),
+		PromptType:         aws.String(promptType),
+	}
+	if g.Intn(2) == 0 {
+		promptConfig.InferenceConfiguration = &svcapitypes.InferenceConfiguration{
+			MaximumLength: g.int64(2048, 4096),
+			StopSequences: g.strings("</answer>", "</invoke>"),
+			Temperature:   g.float64(0, 0.5),
+			TopK:          g.int64(100, 250),
+			TopP:          g.float64(0.9, 1),
+		}
+	}
+	return promptConfig
+}
+
+func (g agentGenerator) actionGroups() []*svcapitypes.InlineActionGroup {
+	if g.Intn(3) == 0 {
+		return nil
+	}
+	actionGroups := []*svcapitypes.InlineActionGroup{}
+	for _, name := range []string{"orders", "refunds"} {
+		if g.Intn(2) == 0 {
+			continue
+		}
+		actionGroup := &svcapitypes.InlineActionGroup{
+			ActionGroupName:  aws.String(name),
+			ActionGroupState: g.pick("ENABLED", "DISABLED"),
+			Description:      g.pick("Looks up orders."),
+			ActionGroupExecutor: &svcapitypes.InlineActionGroupExecutor{
+				CustomControl: aws.String("RETURN_CONTROL"),
+			},
+		}
+		if g.Intn(2) == 0 {
+			parameters := map[string]*svcapitypes.InlineParameterDetail{}
+			if g.Intn(2) == 0 {
+				parameters["orderID"] = &svcapitypes.InlineParameterDetail{Type: aws.String("string")}
+			}
+			actionGroup.FunctionSchema = &svcapitypes.InlineFunctionSchema{
+				Functions: []*svcapitypes.InlineFunction{{
+					Name:       aws.String("getOrder"),
+					Parameters: parameters,
+				}},
+			}
+		}
+		actionGroups = append(actionGroups, actionGroup)
+	}
+	return actionGroups
+}
+
+func (g agentGenerator) knowledgeBases() []*svcapitypes.InlineKnowledgeBase {
+	if g.Intn(3) == 0 {
+		return nil
+	}
+	knowledgeBases := []*svcapitypes.InlineKnowledgeBase{}
+	for _, id := range []string{"KB00001", "KB00002"} {
+		if g.Intn(2) == 0 {
+			continue
+		}
+		knowledgeBases = append(knowledgeBases, &svcapitypes.InlineKnowledgeBase{
+			KnowledgeBaseID:    aws.String(id),
+			Description:        aws.String("Order history."),
+			KnowledgeBaseState: g.pick("ENABLED", "DISABLED"),
+		})
+	}
+	return knowledgeBases
+}
+
+func (g agentGenerator) tags() map[string]*string {
+	switch g.Intn(3) {
+	case 0:
+		return nil
+	case 1:
+		return map[string]*string{}
+	}
+	tags := map[string]*string{}
+	for _, key := range []string{"team", "env"} {
+		if value := g.pick("support", "prod"); value != nil {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// agent returns a random PREPARED Agent.
+func (g agentGenerator) agent() *resource {
+	spec := svcapitypes.AgentSpec{
+		ActionGroups:             g.actionGroups(),
+		AgentCollaboration:       g.pick("DISABLED", "SUPERVISOR"),
+		AgentName:                g.pick("support", "billing"),
+		AgentResourceRoleARN:     g.pick("arn:aws:iam::123456789012:role/agent-role"),
+		CustomerEncryptionKeyARN: g.pick("arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"),
+		Description:              g.pick("Answers questions about orders."),
+		FoundationModel:          g.pick("anthropic.claude-3-haiku-20240307-v1:0", "anthropic.claude-3-sonnet-20240229-v1:0"),
+		IdleSessionTTLInSeconds:  g.int64(600, 1800),
+		Instruction:              g.pick("You are a helpful support agent, answer questions about orders."),
+		KnowledgeBases:           g.knowledgeBases(),
+		OrchestrationType:        g.pick("DEFAULT"),
+		Tags:                     g.tags(),
+	}
+	if g.Intn(2) == 0 {
+		spec.GuardrailConfiguration = &svcapitypes.GuardrailConfiguration{
+			GuardrailIdentifier: g.pick("gr-0123456789"),
+			GuardrailVersion:    g.pick("1", "DRAFT"),
+		}
+	}
+	if g.Intn(2) == 0 {
+		spec.MemoryConfiguration = &svcapitypes.MemoryConfiguration{
+			EnabledMemoryTypes: g.strings("SESSION_SUMMARY"),
+			StorageDays:        g.int64(30),
+		}
+	}
+	if g.Intn(3) != 0 {
+		spec.PromptOverrideConfiguration = &svcapitypes.PromptOverrideConfiguration{
+			OverrideLambda: g.pick("arn:aws:lambda:us-west-2:123456789012:function:parser"),
+		}
+		if g.Intn(4) != 0 {
+			spec.PromptOverrideConfiguration.PromptConfigurations = []*svcapitypes.PromptConfiguration{}
+		}
+		for _, promptType := range []string{"PRE_PROCESSING", "ORCHESTRATION", "POST_PROCESSING"} {
+			if g.Intn(2) == 0 {
+				spec.PromptOverrideConfiguration.PromptConfigurations = append(spec.PromptOverrideConfiguration.PromptConfigurations, g.promptConfiguration(promptType))
+			}
+		}
+	}
+	return &resource{ko: &svcapitypes.Agent{
+		Spec: spec,
+		Status: svcapitypes.AgentStatus{
+			AgentStatus: aws.String("PREPARED"),
+		},
+	}}
+}
+
+// deltaPathSet returns the sorted paths of the differences in the delta.
+func deltaPathSet(t *testing.T, delta *ackcompare.Delta) []string {
+	t.Helper()
+	var paths []string
+	for _, difference := range delta.Differences {
+		data, err := json.Marshal(difference.Path)
+		if err != nil {
+			t.Fatalf("unable to encode path: %v", err)
+		}
+		var path struct{ Parts []string }
+		if err := json.Unmarshal(data, &path); err != nil {
+			t.Fatalf("unable to decode path: %v", err)
+		}
+		paths = append(paths, strings.Join(path.Parts, "."))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// withDefaultPromptChanges returns a copy of the Agent whose prompt
+// configurations that use the default template are changed, and which uses
+// the default template for one more prompt type.
+func withDefaultPromptChanges(g agentGenerator, r *resource) *resource {
+	changed := r.ko.DeepCopy()
+	promptOverrides := changed.Spec.PromptOverrideConfiguration
+	if promptOverrides == nil {
+		return &resource{changed}
+	}
+	for i, promptConfig := range promptOverrides.PromptConfigurations {
+		if promptConfig.PromptCreationMode == nil || *promptConfig.PromptCreationMode == "DEFAULT" {
+			replacement := g.promptConfiguration(*promptConfig.PromptType)
+			replacement.PromptCreationMode = promptConfig.PromptCreationMode
+			promptOverrides.PromptConfigurations[i] = replacement
+		}
+	}
+	added := g.promptConfiguration("KNOWLEDGE_BASE_RESPONSE_GENERATION")
+	added.PromptCreationMode = aws.String("DEFAULT")
+	promptOverrides.PromptConfigurations = append(promptOverrides.PromptConfigurations, added)
+	return &resource{changed}
+}
+
+// withSwappedEmptyLists returns a copy of the Agent where nil lists and maps
+// are empty and empty ones are nil. Spec.ActionGroups and Spec.KnowledgeBases
+// are left unchanged: unset, they aren't managed by the controller, while
+// empty, they remove every action group or knowledge base.
+func withSwappedEmptyLists(r *resource) *resource {
+	swapped := r.ko.DeepCopy()
+	swapStrings := func(values *[]*string) {
+		if *values == nil {
+			*values = []*string{}
+		} else if len(*values) == 0 {
+			*values = nil
+		}
+	}
+	if swapped.Spec.Tags == nil {
+		swapped.Spec.Tags = map[string]*string{}
+	} else if len(swapped.Spec.Tags) == 0 {
+		swapped.Spec.Tags = nil
+	}
+	if memory := swapped.Spec.MemoryConfiguration; memory != nil {
+		swapStrings(&memory.EnabledMemoryTypes)
+	}
+	if promptOverrides := swapped.Spec.PromptOverrideConfiguration; promptOverrides != nil {
+		if promptOverrides.PromptConfigurations == nil {
+			promptOverrides.PromptConfigurations = []*svcapitypes.PromptConfiguration{}
+		} else if len(promptOverrides.PromptConfigurations) == 0 {
+			promptOverrides.PromptConfigurations = nil
+		}
+		for _, promptConfig := range promptOverrides.PromptConfigurations {
+			if promptConfig.InferenceConfiguration != nil {
+				swapStrings(&promptConfig.InferenceConfiguration.StopSequences)
+			}
+		}
+	}
+	for _, actionGroup := range swapped.Spec.ActionGroups {
+		if actionGroup.FunctionSchema == nil {
+			continue
+		}
+		for _, function := range actionGroup.FunctionSchema.Functions {
+			if function.Parameters == nil {
+				function.Parameters = map[string]*svcapitypes.InlineParameterDetail{}
+			} else if len(function.Parameters) == 0 {
+				function.Parameters = nil
+			}
+		}
+	}
+	return &resource{swapped}
+}
+
+// checkDeltaInvariants checks the properties newResourceDelta must have so
+// that the controller neither misses changes nor updates the Agent forever.
+func checkDeltaInvariants(t *testing.T, seed int64, a *resource, b *resource) {
+	t.Helper()
+	g := agentGenerator{rand.New(rand.NewSource(seed))}
+
+	if paths := deltaPathSet(t, newResourceDelta(a, a.DeepCopy().(*resource))); len(paths) > 0 {
+		t.Errorf("delta(a, a) = %v, want no differences", paths)
+	}
+
+	// Action groups and knowledge bases are only compared when the desired
+	// Agent declares them.
+	symmetricB := b.DeepCopy().(*resource)
+	if a.ko.Spec.ActionGroups == nil {
+		symmetricB.ko.Spec.ActionGroups = nil
+	}
+	if a.ko.Spec.KnowledgeBases == nil {
+		symmetricB.ko.Spec.KnowledgeBases = nil
+	}
+	symmetricA := a.DeepCopy().(*resource)
+	if symmetricB.ko.Spec.ActionGroups == nil {
+		symmetricA.ko.Spec.ActionGroups = nil
+	}
+	if symmetricB.ko.Spec.KnowledgeBases == nil {
+		symmetricA.ko.Spec.KnowledgeBases = nil
+	}
+	ab := deltaPathSet(t, newResourceDelta(symmetricA, symmetricB))
+	ba := deltaPathSet(t, newResourceDelta(symmetricB, symmetricA))
+	if !reflect.DeepEqual(ab, ba) {
+		t.Errorf("delta(a, b) = %v, delta(b, a) = %v, want the same paths", ab, ba)
+	}
+
+	defaultPromptChanges := withDefaultPromptChanges(g, a)
+	if paths := deltaPathSet(t, newResourceDelta(a, defaultPromptChanges)); len(paths) > 0 {
+		t.Errorf("changes to DEFAULT prompt configurations caused differences %v", paths)
+	}
+	if paths := deltaPathSet(t, newResourceDelta(defaultPromptChanges, a)); len(paths) > 0 {
+		t.Errorf("changes to DEFAULT prompt configurations caused differences %v", paths)
+	}
+
+	swapped := withSwappedEmptyLists(a)
+	if paths := deltaPathSet(t, newResourceDelta(a, swapped)); len(paths) > 0 {
+		t.Errorf("nil and empty lists caused differences %v", paths)
+	}
+	if paths := deltaPathSet(t, newResourceDelta(swapped, a)); len(paths) > 0 {
+		t.Errorf("nil and empty lists caused differences %v", paths)
+	}
+}
+
+func FuzzNewResourceDelta(f *testing.F) {
+	for seed := int64(0); seed < 64; seed++ {
+		f.Add(seed, seed*7919+1)
+	}
+	f.Fuzz(func(t *testing.T, seedA int64, seedB int64) {
+		a := agentGenerator{rand.New(rand.NewSource(seedA))}.agent()
+		b := agentGenerator{rand.New(rand.NewSource(seedB))}.agent()
+		checkDeltaInvariants(t, seedA^seedB, a, b)
+	})
+}
+
+func TestComparePropertyOverrideConfiguration(t *testing.T) {
+	promptOverrides := func(promptConfigs ...*svcapitypes.PromptConfiguration) *resource {
+		return &resource{ko: &svcapitypes.Agent{Spec: svcapitypes.AgentSpec{
+			PromptOverrideConfiguration: &svcapitypes.PromptOverrideConfiguration{
+				PromptConfigurations: promptConfigs,
+			},
+		}}}
+	}
+	overridden := &svcapitypes.PromptConfiguration{
+		BasePromptTemplate: aws.String("You are a support agent. $instruction$"),
+		PromptCreationMode: aws.String("OVERRIDDEN"),
+		PromptType:         aws.String("ORCHESTRATION"),
+	}
+	tests := []struct {
+		name      string
+		desired   *resource
+		latest    *resource
+		wantDelta bool
+	}{
+		{
+			name:    "unset creation mode uses the default template",
+			desired: promptOverrides(&svcapitypes.PromptConfiguration{PromptType: aws.String("ORCHESTRATION")}),
+			latest: promptOverrides(&svcapitypes.PromptConfiguration{
+				BasePromptTemplate: aws.String("Default template."),
+				PromptCreationMode: aws.String("DEFAULT"),
+				PromptType:         aws.String("ORCHESTRATION"),
+			}),
+			wantDelta: false,
+		},
+		{
+			name:      "overridden prompt added",
+			desired:   promptOverrides(overridden),
+			latest:    promptOverrides(),
+			wantDelta: true,
+		},
+		{
+			name:    "overridden prompt changed",
+			desired: promptOverrides(overridden),
+			latest: promptOverrides(&svcapitypes.PromptConfiguration{
+				BasePromptTemplate: aws.String("You are a billing agent. $instruction$"),
+				PromptCreationMode: aws.String("OVERRIDDEN"),
+				PromptType:         aws.String("ORCHESTRATION"),
+			}),
+			wantDelta: true,
+		},
+		{
+			name:      "nil prompt configuration",
+			desired:   promptOverrides(nil, overridden),
+			latest:    promptOverrides(overridden),
+			wantDelta: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delta := ackcompare.NewDelta()
+			comparePropertyOverrideConfiguration(delta, tt.desired, tt.latest)
+			if got := delta.DifferentAt("Spec.PromptOverrideConfiguration.PromptConfigurations"); got != tt.wantDelta {
+				t.Errorf("DifferentAt(PromptConfigurations) = %t, want %t", got, tt.wantDelta)
+			}
+		})
+	}
+}
@@ -0,0 +1,484 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build envtest
+
+// The envtest suite runs the ACK reconciler for Agents against a local API
+// server and the fake Bedrock Agent API. It requires the envtest binaries:
+//
+//	KUBEBUILDER_ASSETS=$(setup-envtest use -p path) go test -tags envtest ./pkg/resource/agent/...
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	iamapitypes "github.com/aws-controllers-k8s/iam-controller/apis/v1alpha1"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	"github.com/aws-controllers-k8s/runtime/pkg/featuregate"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	ackrtcache "github.com/aws-controllers-k8s/runtime/pkg/runtime/cache"
+	"github.com/aws-controllers-k8s/runtime/pkg/runtime/iamroleselector"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/observe"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/revision"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+// maxReconciles bounds the number of reconciliations a test runs while
+// waiting for an Agent to reach the expected state.
+const maxReconciles = 10
+
+var (
+	envtestConfig *rest.Config
+	envtestScheme = runtime.NewScheme()
+)
+
+func TestMain(m *testing.M) {
+	_ = clientgoscheme.AddToScheme(envtestScheme)
+	_ = svcapitypes.AddToScheme(envtestScheme)
+	_ = ackv1alpha1.AddToScheme(envtestScheme)
+	_ = iamapitypes.AddToScheme(envtestScheme)
+
+	env := &envtest.Environment{
+		CRDInstallOptions: envtest.CRDInstallOptions{
+			Paths: []string{filepath.Join("..", "..", "..", "config", "crd", "bases")},
+			CRDs:  []*apiextensionsv1.CustomResourceDefinition{roleCRD()},
+		},
+		ErrorIfCRDPathMissing: true,
+	}
+	cfg, err := env.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to start envtest: %v\n", err)
+		os.Exit(1)
+	}
+	envtestConfig = cfg
+	code := m.Run()
+	if err := env.Stop(); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to stop envtest: %v\n", err)
+	}
+	os.Exit(code)
+}
+
+// roleCRD returns a minimal CustomResourceDefinition for the IAM controller's
+// Role, which Agents reference through Spec.AgentResourceRoleRef.
+func roleCRD() *apiextensionsv1.CustomResourceDefinition {
+	preserveUnknownFields := true
+	return &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "roles.iam.services.k8s.aws"},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: iamapitypes.GroupVersion.Group,
+			Names: apiextensionsv1.CustomResourceDefinitionNames{
+				Kind:     "Role",
+				ListKind: "RoleList",
+				Plural:   "roles",
+				Singular: "role",
+			},
+			Scope: apiextensionsv1.NamespaceScoped,
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{
+				Name:    iamapitypes.GroupVersion.Version,
+				Served:  true,
+				Storage: true,
+				Schema: &apiextensionsv1.CustomResourceValidation{
+					OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+						Type:                   "object",
+						XPreserveUnknownFields: &preserveUnknownFields,
+					},
+				},
+				Subresources: &apiextensionsv1.CustomResourceSubresources{
+					Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+				},
+			}},
+		},
+	}
+}
+
+// envtestHarness reconciles Agents in a dedicated namespace with the ACK
+// reconciler, which sends its requests to a fake Bedrock Agent API server.
+type envtestHarness struct {
+	t          *testing.T
+	client     client.Client
+	server     *fakebedrockagent.Server
+	reconciler acktypes.AWSResourceReconciler
+	namespace  string
+}
+
+// newEnvtestHarness returns a harness whose runtime config is changed by the
+// given functions.
+func newEnvtestHarness(t *testing.T, configure ...func(*ackcfg.Config)) *envtestHarness {
+	t.Helper()
+	// The AWS config is loaded by the runtime from the environment.
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "SECRET")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	server := fakebedrockagent.NewServer(t)
+	cfg := ackcfg.Config{
+		AccountID:      fakebedrockagent.AccountID,
+		Region:         fakebedrockagent.Region,
+		EndpointURL:    server.URL,
+		DeletionPolicy: ackv1alpha1.DeletionPolicyDelete,
+	}
+	for _, f := range configure {
+		f(&cfg)
+	}
+
+	// The manager isn't started: it only provides the clients to the
+	// reconciler, which the tests call directly.
+	mgr, err := ctrlrt.NewManager(envtestConfig, ctrlrt.Options{
+		Scheme:  envtestScheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		t.Fatalf("unable to create manager: %v", err)
+	}
+	log := logr.Discard()
+	rmf := newResourceManagerFactory()
+	sc := ackrt.NewServiceController(
+		"bedrockagent", "bedrockagent.services.k8s.aws",
+		acktypes.VersionInfo{},
+	).WithLogger(log)
+	reconciler := ackrt.NewReconciler(
+		sc, rmf, log, cfg,
+		ackmetrics.NewMetrics("bedrockagent"),
+		ackrtcache.New(log, ackrtcache.Config{}, cfg.FeatureGates),
+		iamroleselector.NewCache(log),
+	)
+	if err := reconciler.BindControllerManager(mgr); err != nil {
+		t.Fatalf("unable to bind reconciler: %v", err)
+	}
+
+	c, err := client.New(envtestConfig, client.Options{Scheme: envtestScheme})
+	if err != nil {
+		t.Fatalf("unable to create client: %v", err)
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "agent-"}}
+	if err := c.Create(context.Background(), ns); err != nil {
+		t.Fatalf("unable to create namespace: %v", err)
+	}
+	return &envtestHarness{
+		t:          t,
+		client:     c,
+		server:     server,
+		reconciler: reconciler,
+		namespace:  ns.Name,
+	}
+}
+
+// newAgent returns an Agent in the harness namespace.
+func (h *envtestHarness) newAgent(name string) *svcapitypes.Agent {
+	return &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: h.namespace},
+		Spec: svcapitypes.AgentSpec{
+			AgentName:       aws.String(name),
+			FoundationModel: aws.String("anthropic.claude-3-haiku-20240307-v1:0"),
+			Instruction:     aws.String("You are a helpful support agent, answer questions about orders."),
+		},
+	}
+}
+
+// get returns the Agent as stored in the API server, or nil if it doesn't
+// exist.
+func (h *envtestHarness) get(name string) *svcapitypes.Agent {
+	h.t.Helper()
+	agent := &svcapitypes.Agent{}
+	err := h.client.Get(context.Background(), types.NamespacedName{Namespace: h.namespace, Name: name}, agent)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		h.t.Fatalf("unable to get Agent %s: %v", name, err)
+	}
+	return agent
+}
+
+// reconcileUntil reconciles the Agent until done returns true for the stored
+// Agent, which is nil once it has been deleted.
+func (h *envtestHarness) reconcileUntil(name string, done func(*svcapitypes.Agent) bool) *svcapitypes.Agent {
+	h.t.Helper()
+	req := ctrlrt.Request{NamespacedName: types.NamespacedName{Namespace: h.namespace, Name: name}}
+	for i := 0; i < maxReconciles; i++ {
+		// Requeues and errors are expected while the agent isn't PREPARED.
+		_, _ = h.reconciler.Reconcile(context.Background(), req)
+		agent := h.get(name)
+		if done(agent) {
+			return agent
+		}
+	}
+	h.t.Fatalf("Agent %s didn't reach the expected state after %d reconciliations", name, maxReconciles)
+	return nil
+}
+
+// condition returns the condition of the given type, or nil.
+func condition(agent *svcapitypes.Agent, conditionType ackv1alpha1.ConditionType) *ackv1alpha1.Condition {
+	for _, c := range agent.Status.Conditions {
+		if c.Type == conditionType {
+			return c
+		}
+	}
+	return nil
+}
+
+// hasCondition returns true if the Agent has the condition with the status.
+func hasCondition(agent *svcapitypes.Agent, conditionType ackv1alpha1.ConditionType, status corev1.ConditionStatus) bool {
+	c := condition(agent, conditionType)
+	return c != nil && c.Status == status
+}
+
+func TestEnvtest_AgentLifecycle(t *testing.T) {
+	h := newEnvtestHarness(t)
+	ctx := context.Background()
+
+	agent := h.newAgent("support")
+	agent.Spec.AgentResourceRoleARN = aws.String("arn:aws:iam::123456789012:role/agent-role")
+	if err := h.client.Create(ctx, agent); err != nil {
+		t.Fatalf("unable to create Agent: %v", err)
+	}
+
+	// The first reconciliation adds the finalizer and creates the agent.
+	created := h.reconcileUntil("support", func(a *svcapitypes.Agent) bool {
+		return a.Status.AgentID != nil
+	})
+	if !containsFinalizer(created, FinalizerString) {
+		t.Errorf("finalizers = %v, want %s", created.Finalizers, FinalizerString)
+	}
+	if created.Status.ACKResourceMetadata == nil || created.Status.ACKResourceMetadata.ARN == nil {
+		t.Fatal("Status.ACKResourceMetadata.ARN isn't set")
+	}
+	if owner := created.Status.ACKResourceMetadata.OwnerAccountID; owner == nil || *owner != fakebedrockagent.AccountID {
+		t.Errorf("Status.ACKResourceMetadata.OwnerAccountID = %v, want %s", owner, fakebedrockagent.AccountID)
+	}
+	agentID := *created.Status.AgentID
+
+	// The agent is prepared, and the prompt override configuration that the
+	// service defaulted is late initialized.
+	synced := h.reconcileUntil("support", func(a *svcapitypes.Agent) bool {
+		return hasCondition(a, ackv1alpha1.ConditionTypeResourceSynced, corev1.ConditionTrue)
+	})
+	if status := aws.ToString(synced.Status.AgentStatus); status != fakebedrockagent.StatusPrepared {
+		t.Errorf("Status.AgentStatus = %s, want PREPARED", status)
+	}
+	if !hasCondition(synced, ackv1alpha1.ConditionTypeLateInitialized, corev1.ConditionTrue) {
+		t.Errorf("%s condition = %v, want True", ackv1alpha1.ConditionTypeLateInitialized, condition(synced, ackv1alpha1.ConditionTypeLateInitialized))
+	}
+	if synced.Spec.PromptOverrideConfiguration == nil || len(synced.Spec.PromptOverrideConfiguration.PromptConfigurations) == 0 {
+		t.Errorf("Spec.PromptOverrideConfiguration = %v, want the late initialized default", synced.Spec.PromptOverrideConfiguration)
+	}
+	for _, conditionType := range []ackv1alpha1.ConditionType{ackv1alpha1.ConditionTypeTerminal, ackv1alpha1.ConditionTypeRecoverable} {
+		if c := condition(synced, conditionType); c != nil && c.Status == corev1.ConditionTrue {
+			t.Errorf("unexpected %s condition: %s", conditionType, aws.ToString(c.Message))
+		}
+	}
+
+	// Deleting the Agent deletes the agent, then removes the finalizer.
+	if err := h.client.Delete(ctx, synced); err != nil {
+		t.Fatalf("unable to delete Agent: %v", err)
+	}
+	h.reconcileUntil("support", func(a *svcapitypes.Agent) bool {
+		return a == nil
+	})
+	if observed, ok := h.server.Agent(agentID); !ok || observed["agentStatus"] != fakebedrockagent.StatusDeleting {
+		t.Errorf("agent %s = %v, want DELETING", agentID, observed["agentStatus"])
+	}
+}
+
+func TestEnvtest_AgentResourceRoleRef(t *testing.T) {
+	h := newEnvtestHarness(t)
+	ctx := context.Background()
+
+	role := &iamapitypes.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-role", Namespace: h.namespace},
+		Spec: iamapitypes.RoleSpec{
+			Name:                     aws.String("agent-role"),
+			AssumeRolePolicyDocument: aws.String("{}"),
+		},
+	}
+	if err := h.client.Create(ctx, role); err != nil {
+		t.Fatalf("unable to create Role: %v", err)
+	}
+	agent := h.newAgent("referencing")
+	agent.Spec.AgentResourceRoleRef = &ackv1alpha1.AWSResourceReferenceWrapper{
+		From: &ackv1alpha1.AWSResourceReference{Name: aws.String("agent-role")},
+	}
+	if err := h.client.Create(ctx, agent); err != nil {
+		t.Fatalf("unable to create Agent: %v", err)
+	}
+
+	// The Role isn't synced yet: the agent isn't created.
+	unresolved := h.reconcileUntil("referencing", func(a *svcapitypes.Agent) bool {
+		return condition(a, ackv1alpha1.ConditionTypeReferencesResolved) != nil
+	})
+	if !hasCondition(unresolved, ackv1alpha1.ConditionTypeReferencesResolved, corev1.ConditionFalse) {
+		t.Errorf("%s condition = %v, want False", ackv1alpha1.ConditionTypeReferencesResolved, condition(unresolved, ackv1alpha1.ConditionTypeReferencesResolved))
+	}
+	for _, call := range h.server.Calls() {
+		if call == "CreateAgent" {
+			t.Fatal("CreateAgent was called before the role reference was resolved")
+		}
+	}
+
+	roleARN := ackv1alpha1.AWSResourceName("arn:aws:iam::123456789012:role/agent-role")
+	role.Status.ACKResourceMetadata = &ackv1alpha1.ResourceMetadata{ARN: &roleARN}
+	role.Status.Conditions = []*ackv1alpha1.Condition{{
+		Type:   ackv1alpha1.ConditionTypeResourceSynced,
+		Status: corev1.ConditionTrue,
+	}}
+	if err := h.client.Status().Update(ctx, role); err != nil {
+		t.Fatalf("unable to update Role status: %v", err)
+	}
+
+	// The resolved ARN is sent to the service but isn't persisted, so that
+	// the reference is resolved again on every reconciliation.
+	created := h.reconcileUntil("referencing", func(a *svcapitypes.Agent) bool {
+		return a.Status.AgentID != nil
+	})
+	if !hasCondition(created, ackv1alpha1.ConditionTypeReferencesResolved, corev1.ConditionTrue) {
+		t.Errorf("%s condition = %v, want True", ackv1alpha1.ConditionTypeReferencesResolved, condition(created, ackv1alpha1.ConditionTypeReferencesResolved))
+	}
+	observed, ok := h.server.Agent(*created.Status.AgentID)
+	if !ok {
+		t.Fatalf("agent %s doesn't exist", *created.Status.AgentID)
+	}
+	if got := observed["agentResourceRoleArn"]; got != string(roleARN) {
+		t.Errorf("agentResourceRoleArn = %v, want %s", got, roleARN)
+	}
+	if created.Spec.AgentResourceRoleARN != nil {
+		t.Errorf("Spec.AgentResourceRoleARN = %s, want the reference to stay unresolved in the Spec", *created.Spec.AgentResourceRoleARN)
+	}
+}
+
+func TestEnvtest_AgentRollback(t *testing.T) {
+	h := newEnvtestHarness(t, func(cfg *ackcfg.Config) {
+		cfg.FeatureGates = featuregate.FeatureGates{featuregate.IgnoreFieldDrift: {Enabled: true}}
+	})
+	ctx := context.Background()
+
+	agent := h.newAgent("rollback")
+	agent.Spec.AgentResourceRoleARN = aws.String("arn:aws:iam::123456789012:role/agent-role")
+	first := aws.ToString(agent.Spec.Instruction)
+	if err := h.client.Create(ctx, agent); err != nil {
+		t.Fatalf("unable to create Agent: %v", err)
+	}
+	synced := h.reconcileUntil("rollback", func(a *svcapitypes.Agent) bool {
+		return len(a.Status.Revisions) == 1
+	})
+
+	second := "You are a terse support agent, answer questions about orders."
+	synced.Spec.Instruction = aws.String(second)
+	if err := h.client.Update(ctx, synced); err != nil {
+		t.Fatalf("unable to update Agent: %v", err)
+	}
+	synced = h.reconcileUntil("rollback", func(a *svcapitypes.Agent) bool {
+		return len(a.Status.Revisions) == 2
+	})
+
+	// The agent is in sync with its spec: the annotation alone rolls it back.
+	synced.Annotations = map[string]string{revision.RollbackAnnotation: "1"}
+	if err := h.client.Update(ctx, synced); err != nil {
+		t.Fatalf("unable to annotate Agent: %v", err)
+	}
+	rolledBack := h.reconcileUntil("rollback", func(a *svcapitypes.Agent) bool {
+		return hasCondition(a, ConditionTypeRolledBack, corev1.ConditionTrue)
+	})
+	observed, _ := h.server.Agent(*rolledBack.Status.AgentID)
+	if got := observed["instruction"]; got != first {
+		t.Errorf("instruction in AWS = %v, want the one of revision 1", got)
+	}
+	if got := aws.ToString(rolledBack.Spec.Instruction); got != second {
+		t.Errorf("Spec.Instruction = %q, want the spec of the Agent kept", got)
+	}
+}
+
+func TestEnvtest_AgentObserveOnly(t *testing.T) {
+	h := newEnvtestHarness(t)
+	ctx := context.Background()
+
+	// The agent is owned by another system: its Agent is deleted, but the
+	// agent is retained.
+	owned := h.newAgent("owned")
+	owned.Annotations = map[string]string{ackv1alpha1.AnnotationDeletionPolicy: string(ackv1alpha1.DeletionPolicyRetain)}
+	owned.Spec.AgentResourceRoleARN = aws.String("arn:aws:iam::123456789012:role/agent-role")
+	if err := h.client.Create(ctx, owned); err != nil {
+		t.Fatalf("unable to create Agent: %v", err)
+	}
+	owned = h.reconcileUntil("owned", func(a *svcapitypes.Agent) bool {
+		return a.Status.AgentID != nil
+	})
+	agentID := *owned.Status.AgentID
+	if err := h.client.Delete(ctx, owned); err != nil {
+		t.Fatalf("unable to delete Agent: %v", err)
+	}
+	h.reconcileUntil("owned", func(a *svcapitypes.Agent) bool { return a == nil })
+	h.server.ResetCalls()
+
+	// The observed Agent only names the agent.
+	observed := &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "observed",
+			Namespace:   h.namespace,
+			Annotations: map[string]string{observe.PolicyAnnotation: observe.PolicyObserve},
+		},
+		Spec: svcapitypes.AgentSpec{AgentName: aws.String("owned")},
+	}
+	if err := h.client.Create(ctx, observed); err != nil {
+		t.Fatalf("unable to create Agent: %v", err)
+	}
+	mirrored := h.reconcileUntil("observed", func(a *svcapitypes.Agent) bool {
+		return hasCondition(a, ConditionTypeObserveOnly, corev1.ConditionTrue) && a.Spec.Instruction != nil
+	})
+	if got := aws.ToString(mirrored.Status.AgentID); got != agentID {
+		t.Errorf("Status.AgentID = %q, want the adopted %q", got, agentID)
+	}
+	if got := aws.ToString(mirrored.Spec.Instruction); got != aws.ToString(owned.Spec.Instruction) {
+		t.Errorf("Spec.Instruction = %q, want it mirrored from AWS", got)
+	}
+
+	if err := h.client.Delete(ctx, mirrored); err != nil {
+		t.Fatalf("unable to delete Agent: %v", err)
+	}
+	h.reconcileUntil("observed", func(a *svcapitypes.Agent) bool { return a == nil })
+	if _, ok := h.server.Agent(agentID); !ok {
+		t.Errorf("agent %s deleted, want it left to its owner", agentID)
+	}
+	for _, call := range h.server.Calls() {
+		switch call {
+		case "GetAgent", "ListAgents", "ListTagsForResource", "ListAgentActionGroups", "ListAgentKnowledgeBases":
+		default:
+			t.Errorf("%s called for an observed agent", call)
+		}
+	}
+}
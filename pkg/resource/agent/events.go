@@ -0,0 +1,146 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	svcevents "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/events"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/pause"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+)
+
+// Reasons of the Events emitted about Agents.
+const (
+	EventReasonAgentStatusChanged        = "AgentStatusChanged"
+	EventReasonAgentFailed               = "AgentFailed"
+	EventReasonPrepareAgent              = "PrepareAgent"
+	EventReasonPrepareAgentFailed        = "PrepareAgentFailed"
+	EventReasonTagsSynced                = "TagsSynced"
+	EventReasonTagsSyncFailed            = "TagsSyncFailed"
+	EventReasonReferenceResolutionFailed = "ReferenceResolutionFailed"
+	EventReasonAwaitingApproval          = "AwaitingApproval"
+	EventReasonReconciliationPaused      = "ReconciliationPaused"
+	EventReasonReconciliationResumed     = "ReconciliationResumed"
+	EventReasonOwnedByOtherCluster       = "OwnedByOtherCluster"
+	EventReasonAgentTakenOver            = "AgentTakenOver"
+)
+
+// recordAgentStatusEvent emits an Event when the AgentStatus of the agent
+// moved away from previous. A FAILED agent emits a Warning with the failure
+// reasons.
+func recordAgentStatusEvent(previous *string, ko *svcapitypes.Agent) {
+	current := aws.ToString(ko.Status.AgentStatus)
+	if current == "" || current == aws.ToString(previous) {
+		return
+	}
+	recorder := svcevents.GetRecorder()
+	if current == string(svcsdktypes.AgentStatusFailed) {
+		recorder.Warning(
+			ko, EventReasonAgentFailed, "GetAgent",
+			"Agent status changed from %s to %s: %s",
+			statusOrNone(previous), current, strings.Join(aws.ToStringSlice(ko.Status.FailureReasons), "; "),
+		)
+		return
+	}
+	recorder.Normal(
+		ko, EventReasonAgentStatusChanged, "GetAgent",
+		"Agent status changed from %s to %s", statusOrNone(previous), current,
+	)
+}
+
+func statusOrNone(status *string) string {
+	if status == nil || *status == "" {
+		return "<none>"
+	}
+	return *status
+}
+
+// recordPrepareEvent emits an Event about a PrepareAgent call.
+func recordPrepareEvent(ko *svcapitypes.Agent, err error) {
+	if err != nil {
+		svcevents.GetRecorder().Warning(ko, EventReasonPrepareAgentFailed, "PrepareAgent", "PrepareAgent failed: %v", err)
+		return
+	}
+	svcevents.GetRecorder().Normal(ko, EventReasonPrepareAgent, "PrepareAgent", "Preparing the agent")
+}
+
+// recordTagsEvent emits an Event about a synchronisation of the agent's tags.
+func recordTagsEvent(ko *svcapitypes.Agent, err error) {
+	if err != nil {
+		svcevents.GetRecorder().Warning(ko, EventReasonTagsSyncFailed, "TagResource", "%v", err)
+		return
+	}
+	svcevents.GetRecorder().Normal(ko, EventReasonTagsSynced, "TagResource", "Updated the agent tags")
+}
+
+// recordReferenceEvent emits a Warning when the references of the agent
+// can't be resolved.
+func recordReferenceEvent(ko *svcapitypes.Agent, err error) {
+	if err == nil {
+		return
+	}
+	svcevents.GetRecorder().Warning(ko, EventReasonReferenceResolutionFailed, "ResolveReferences", "%v", err)
+}
+
+// recordApprovalEvent emits an Event when the changes to the agent wait for
+// the approval of their plan.
+func recordApprovalEvent(ko *svcapitypes.Agent, hash string) {
+	svcevents.GetRecorder().Normal(
+		ko, EventReasonAwaitingApproval, "UpdateAgent",
+		"Changes await the approval of plan %s", hash,
+	)
+}
+
+// recordPauseEvent emits an Event when the reconciliation of the agent is
+// paused.
+func recordPauseEvent(ko *svcapitypes.Agent) {
+	svcevents.GetRecorder().Normal(
+		ko, EventReasonReconciliationPaused, "ReadOne",
+		"Reconciliation paused by the %s annotation", pause.Annotation,
+	)
+}
+
+// recordResumeEvent emits an Event when the reconciliation of the agent
+// resumes, a Warning when the agent drifted from its spec in the pause.
+func recordResumeEvent(ko *svcapitypes.Agent, message string, drifted bool) {
+	if drifted {
+		svcevents.GetRecorder().Warning(ko, EventReasonReconciliationResumed, "ReadOne", "%s", message)
+		return
+	}
+	svcevents.GetRecorder().Normal(ko, EventReasonReconciliationResumed, "ReadOne", "%s", message)
+}
+
+// recordOwnershipEvent emits a Warning when the agent isn't updated or
+// deleted because another cluster owns it.
+func recordOwnershipEvent(ko *svcapitypes.Agent, action string) {
+	svcevents.GetRecorder().Warning(
+		ko, EventReasonOwnedByOtherCluster, "ReadOne",
+		"Agent is owned by cluster %s and isn't %s; set the %s: \"true\" annotation to take it over",
+		aws.ToString(ko.Status.OwnerCluster), action, tags.TakeoverAnnotation,
+	)
+}
+
+// recordTakeoverEvent emits an Event when the agent is taken over from
+// another cluster.
+func recordTakeoverEvent(ko *svcapitypes.Agent, owner string) {
+	svcevents.GetRecorder().Normal(
+		ko, EventReasonAgentTakenOver, "TagResource",
+		"Took the agent over from cluster %s", owner,
+	)
+}
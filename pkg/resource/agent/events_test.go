@@ -0,0 +1,121 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/events"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	svcevents "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/events"
+)
+
+// withFakeRecorder makes the Agent Events go to a fake recorder until the end
+// of the test.
+func withFakeRecorder(t *testing.T) *events.FakeRecorder {
+	fake := events.NewFakeRecorder(10)
+	svcevents.SetRecorder(svcevents.NewRecorder(fake))
+	t.Cleanup(func() { svcevents.SetRecorder(nil) })
+	return fake
+}
+
+func TestRecordAgentEvents(t *testing.T) {
+	agent := func(status string, failureReasons ...string) *svcapitypes.Agent {
+		ko := &svcapitypes.Agent{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "agent", UID: "uid"}}
+		if status != "" {
+			ko.Status.AgentStatus = aws.String(status)
+		}
+		ko.Status.FailureReasons = aws.StringSlice(failureReasons)
+		return ko
+	}
+	for _, tc := range []struct {
+		name string
+		emit func()
+		want string
+	}{
+		{
+			name: "created",
+			emit: func() { recordAgentStatusEvent(nil, agent("CREATING")) },
+			want: "Normal AgentStatusChanged Agent status changed from <none> to CREATING",
+		},
+		{
+			name: "transition",
+			emit: func() { recordAgentStatusEvent(aws.String("PREPARING"), agent("PREPARED")) },
+			want: "Normal AgentStatusChanged Agent status changed from PREPARING to PREPARED",
+		},
+		{
+			name: "unchanged",
+			emit: func() { recordAgentStatusEvent(aws.String("PREPARED"), agent("PREPARED")) },
+		},
+		{
+			name: "no status",
+			emit: func() { recordAgentStatusEvent(aws.String("PREPARED"), agent("")) },
+		},
+		{
+			name: "failed",
+			emit: func() {
+				recordAgentStatusEvent(aws.String("PREPARING"), agent("FAILED", "invalid instruction", "missing role"))
+			},
+			want: "Warning AgentFailed Agent status changed from PREPARING to FAILED: invalid instruction; missing role",
+		},
+		{
+			name: "prepare",
+			emit: func() { recordPrepareEvent(agent("NOT_PREPARED"), nil) },
+			want: "Normal PrepareAgent Preparing the agent",
+		},
+		{
+			name: "prepare failed",
+			emit: func() { recordPrepareEvent(agent("NOT_PREPARED"), errors.New("ConflictException")) },
+			want: "Warning PrepareAgentFailed PrepareAgent failed: ConflictException",
+		},
+		{
+			name: "tags synced",
+			emit: func() { recordTagsEvent(agent("PREPARED"), nil) },
+			want: "Normal TagsSynced Updated the agent tags",
+		},
+		{
+			name: "tags sync failed",
+			emit: func() { recordTagsEvent(agent("PREPARED"), errors.New("failed to add tags: team")) },
+			want: "Warning TagsSyncFailed failed to add tags: team",
+		},
+		{
+			name: "reference resolution failed",
+			emit: func() { recordReferenceEvent(agent(""), errors.New("role not synced")) },
+			want: "Warning ReferenceResolutionFailed role not synced",
+		},
+		{
+			name: "references resolved",
+			emit: func() { recordReferenceEvent(agent(""), nil) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := withFakeRecorder(t)
+			tc.emit()
+			select {
+			case got := <-fake.Events:
+				if got != tc.want {
+					t.Errorf("event = %q, want %q", got, tc.want)
+				}
+			default:
+				if tc.want != "" {
+					t.Errorf("no event, want %q", tc.want)
+				}
+			}
+		})
+	}
+}
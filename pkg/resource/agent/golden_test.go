@@ -0,0 +1,212 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"sigs.k8s.io/yaml"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+// updateGolden rewrites the golden files with the actual results:
+//
+//	go test ./pkg/resource/agent/ -run Golden -update
+var updateGolden = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// Each directory in testdata/golden holds one case, see
+// testdata/golden/README.md:
+//
+//   - desired.yaml is the Agent passed to the resource manager.
+//   - response.json is the response of the operation under test, replayed
+//     by the fake Bedrock Agent server.
+//   - agent.yaml is the Agent returned by the resource manager.
+//   - request.json is the request sent for desired.yaml (CreateAgent and
+//     UpdateAgent only).
+const goldenDir = "testdata/golden"
+
+// newGoldenServer returns a fake Bedrock Agent server answering the
+// operation under test with the response.json of the case.
+func newGoldenServer(t *testing.T, dir string, operation string) *fakebedrockagent.Server {
+	server := fakebedrockagent.NewServer(t)
+	server.Replay(operation, readGoldenFile(t, dir, "response.json"))
+	return server
+}
+
+func readGoldenFile(t *testing.T, dir string, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(goldenDir, dir, name))
+	if err != nil {
+		t.Fatalf("unable to read golden file: %v", err)
+	}
+	return data
+}
+
+func readGoldenAgent(t *testing.T, dir string) *resource {
+	t.Helper()
+	ko := &svcapitypes.Agent{}
+	if err := yaml.UnmarshalStrict(readGoldenFile(t, dir, "desired.yaml"), ko); err != nil {
+		t.Fatalf("unable to decode desired.yaml: %v", err)
+	}
+	return &resource{ko}
+}
+
+// compareGoldenAgent compares an Agent to the agent.yaml golden file. The
+// transition times of the conditions are ignored.
+func compareGoldenAgent(t *testing.T, dir string, ko *svcapitypes.Agent) {
+	t.Helper()
+	ko = ko.DeepCopy()
+	for _, condition := range ko.Status.Conditions {
+		condition.LastTransitionTime = nil
+	}
+	got, err := yaml.Marshal(ko)
+	if err != nil {
+		t.Fatalf("unable to encode Agent: %v", err)
+	}
+	path := filepath.Join(goldenDir, dir, "agent.yaml")
+	if *updateGolden {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("unable to update golden file: %v", err)
+		}
+		return
+	}
+	if want := readGoldenFile(t, dir, "agent.yaml"); !bytes.Equal(got, want) {
+		t.Errorf("Agent differs from %s:\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
+
+// compareGoldenRequest compares a JSON request body to the request.json
+// golden file. Keys order and formatting are ignored.
+func compareGoldenRequest(t *testing.T, dir string, body []byte) {
+	t.Helper()
+	var got any
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("unable to decode request %s: %v", body, err)
+	}
+	path := filepath.Join(goldenDir, dir, "request.json")
+	if *updateGolden {
+		var indented bytes.Buffer
+		encoder := json.NewEncoder(&indented)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		_ = encoder.Encode(got)
+		if err := os.WriteFile(path, indented.Bytes(), 0o644); err != nil {
+			t.Fatalf("unable to update golden file: %v", err)
+		}
+		return
+	}
+	var want any
+	if err := json.Unmarshal(readGoldenFile(t, dir, "request.json"), &want); err != nil {
+		t.Fatalf("unable to decode %s: %v", path, err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("request differs from %s:\n--- got\n%s\n--- want\n%s", path, body, readGoldenFile(t, dir, "request.json"))
+	}
+}
+
+// goldenUpdateDelta is the delta of the UpdateAgent cases: a change of the
+// instruction makes sdkUpdate call UpdateAgent, then prepare the agent.
+func goldenUpdateDelta(desired *resource) *ackcompare.Delta {
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.Instruction", desired.ko.Spec.Instruction, nil)
+	return delta
+}
+
+func TestSDKOutput_Golden(t *testing.T) {
+	tests := []struct {
+		dir       string
+		operation string
+	}{
+		{"get_agent_minimal", "GetAgent"},
+		{"get_agent_full", "GetAgent"},
+		{"create_agent_minimal", "CreateAgent"},
+		{"create_agent_full", "CreateAgent"},
+		{"update_agent_full", "UpdateAgent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			ctx := context.Background()
+			server := newGoldenServer(t, tt.dir, tt.operation)
+			rm := newFakeResourceManager(t, server)
+			desired := readGoldenAgent(t, tt.dir)
+
+			var got *resource
+			var err error
+			switch tt.operation {
+			case "GetAgent":
+				got, err = rm.sdkFind(ctx, desired)
+			case "CreateAgent":
+				got, err = rm.sdkCreate(ctx, desired)
+			case "UpdateAgent":
+				got, err = rm.sdkUpdate(ctx, desired, desired.DeepCopy().(*resource), goldenUpdateDelta(desired))
+			}
+			if err != nil {
+				t.Fatalf("%s error = %v", tt.operation, err)
+			}
+			compareGoldenAgent(t, tt.dir, got.ko)
+		})
+	}
+}
+
+func TestNewRequestPayload_Golden(t *testing.T) {
+	tests := []struct {
+		dir       string
+		operation string
+	}{
+		{"create_agent_minimal", "CreateAgent"},
+		{"create_agent_full", "CreateAgent"},
+		{"update_agent_full", "UpdateAgent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.dir, func(t *testing.T) {
+			ctx := context.Background()
+			server := newGoldenServer(t, tt.dir, tt.operation)
+			rm := newFakeResourceManager(t, server)
+			desired := readGoldenAgent(t, tt.dir)
+
+			var err error
+			switch tt.operation {
+			case "CreateAgent":
+				input, perr := rm.newCreateRequestPayload(ctx, desired)
+				if perr != nil {
+					t.Fatalf("newCreateRequestPayload() error = %v", perr)
+				}
+				// The client token is generated by the SDK when unset.
+				input.ClientToken = aws.String("golden-client-token")
+				_, err = rm.sdkapi.CreateAgent(ctx, input)
+			case "UpdateAgent":
+				input, perr := rm.newUpdateRequestPayload(ctx, desired, goldenUpdateDelta(desired))
+				if perr != nil {
+					t.Fatalf("newUpdateRequestPayload() error = %v", perr)
+				}
+				_, err = rm.sdkapi.UpdateAgent(ctx, input)
+			}
+			if err != nil {
+				t.Fatalf("%s error = %v", tt.operation, err)
+			}
+			compareGoldenRequest(t, tt.dir, server.Request(tt.operation))
+		})
+	}
+}
@@ -2,16 +2,44 @@ package agent
 
 import (
 	"context"
-	"reflect"
+	"fmt"
+	"time"
 
 	"github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
 	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/tracing"
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
 	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	ackutil "github.com/aws-controllers-k8s/runtime/pkg/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
 	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// ConditionTypeTagsSynced reports whether the last tag synchronisation
+// succeeded. When it didn't, the message lists the tags that failed.
+const ConditionTypeTagsSynced ackv1alpha1.ConditionType = "TagsSynced"
+
+// preparableStatuses are the agent statuses in which PrepareAgent is
+// accepted. In the others, e.g. CREATING, UPDATING or PREPARING, it fails
+// with a ConflictException.
+var preparableStatuses = []string{
+	string(svcsdktypes.AgentStatusNotPrepared),
+	string(svcsdktypes.AgentStatusPrepared),
+	string(svcsdktypes.AgentStatusFailed),
+}
+
+// prepareRequeueDelay is the delay before an agent that doesn't accept
+// PrepareAgent yet is reconciled again.
+const prepareRequeueDelay = 10 * time.Second
+
 type metricsRecorder interface {
 	RecordAPICall(opType string, opID string, err error)
 }
@@ -31,7 +59,9 @@ func prepareAgent(
 	var err error
 	rlog := ackrtlog.FromContext(ctx)
 	exit := rlog.Trace("hooks.prepareAgent")
+	ctx, span := tracing.Start(ctx, "Agent.PrepareAgent", attributeAgentID.String(agentId))
 	defer func() {
+		tracing.End(span, err)
 		exit(err)
 	}()
 
@@ -43,6 +73,59 @@ func prepareAgent(
 	return err
 }
 
+// syncAgentComponents reconciles the inline action groups and knowledge bases
+// of the agent, then calls PrepareAgent once if the agent or any of its
+// components changed, or if the agent isn't PREPARED yet. Changes start the
+// measure of the time to PREPARED. An agent whose agentStatus doesn't accept
+// PrepareAgent yet is requeued, and prepared once it settles.
+func (rm *resourceManager) syncAgentComponents(
+	ctx context.Context,
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+	agentUpdated bool,
+	agentStatus *string,
+) (err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("rm.syncAgentComponents")
+	defer func() {
+		exit(err)
+	}()
+
+	agentID := *desired.ko.Status.AgentID
+	specChanged := agentUpdated
+	if delta.DifferentAt("Spec.ActionGroups") {
+		modified, err := syncActionGroups(ctx, rm.tracedSDKAPI(), rm.metrics, agentID, desired.ko.Spec.ActionGroups, latest.ko.Spec.ActionGroups)
+		if err != nil {
+			return err
+		}
+		specChanged = specChanged || modified
+	}
+	if delta.DifferentAt("Spec.KnowledgeBases") {
+		modified, err := syncKnowledgeBases(ctx, rm.tracedSDKAPI(), rm.metrics, agentID, desired.ko.Spec.KnowledgeBases)
+		if err != nil {
+			return err
+		}
+		specChanged = specChanged || modified
+	}
+	if specChanged {
+		recordSpecChange(desired.ko)
+	}
+	if !specChanged && !delta.DifferentAt("Spec.AgentStatus") {
+		return nil
+	}
+	if status := aws.ToString(agentStatus); !ackutil.InStrings(status, preparableStatuses) {
+		return ackrequeue.NeededAfter(
+			fmt.Errorf("agent is %s, it is prepared once it settles", status),
+			prepareRequeueDelay,
+		)
+	}
+	err = prepareAgent(ctx, rm.tracedSDKAPI(), rm.metrics, agentID)
+	recordPrepareAttempt(desired.ko, err)
+	recordPrepareEvent(desired.ko, err)
+	return err
+}
+
 // compareAgentStatus checks if the latest AgentStatus is in the PREPARED state.
 // If not a virtual spec field Spec.AgentStatus is added to the delta.
 func compareAgentStatus(
@@ -73,50 +156,184 @@ func comparePropertyOverrideConfiguration(
 			}
 		}
 
-		var desiredNonDefaultPromptConfigs []*v1alpha1.PromptConfiguration
-		for _, promptConfig := range desired.ko.Spec.PromptOverrideConfiguration.PromptConfigurations {
-			if promptConfig != nil && *promptConfig.PromptCreationMode != "DEFAULT" {
-				desiredNonDefaultPromptConfigs = append(desiredNonDefaultPromptConfigs, promptConfig)
-			}
-		}
-
-		var latestNonDefaultPromptConfigs []*v1alpha1.PromptConfiguration
-		for _, promptConfig := range latest.ko.Spec.PromptOverrideConfiguration.PromptConfigurations {
-			if promptConfig != nil && *promptConfig.PromptCreationMode != "DEFAULT" {
-				latestNonDefaultPromptConfigs = append(latestNonDefaultPromptConfigs, promptConfig)
-			}
-		}
+		desiredNonDefaultPromptConfigs := nonDefaultPromptConfigurations(desired.ko.Spec.PromptOverrideConfiguration.PromptConfigurations)
+		latestNonDefaultPromptConfigs := nonDefaultPromptConfigurations(latest.ko.Spec.PromptOverrideConfiguration.PromptConfigurations)
 
 		if len(desiredNonDefaultPromptConfigs) != len(latestNonDefaultPromptConfigs) {
 			delta.Add("Spec.PromptOverrideConfiguration.PromptConfigurations", desired.ko.Spec.PromptOverrideConfiguration.PromptConfigurations, latest.ko.Spec.PromptOverrideConfiguration.PromptConfigurations)
 		} else if len(desiredNonDefaultPromptConfigs) > 0 {
-			if !reflect.DeepEqual(desiredNonDefaultPromptConfigs, latestNonDefaultPromptConfigs) {
+			if !equality.Semantic.DeepEqual(desiredNonDefaultPromptConfigs, latestNonDefaultPromptConfigs) {
 				delta.Add("Spec.PromptOverrideConfiguration.PromptConfigurations", desired.ko.Spec.PromptOverrideConfiguration.PromptConfigurations, latest.ko.Spec.PromptOverrideConfiguration.PromptConfigurations)
 			}
 		}
 	}
 }
 
+// nonDefaultPromptConfigurations returns the prompt configurations that
+// override the default prompt template. An unset PromptCreationMode uses the
+// default template, like DEFAULT.
+func nonDefaultPromptConfigurations(
+	promptConfigs []*v1alpha1.PromptConfiguration,
+) []*v1alpha1.PromptConfiguration {
+	var nonDefault []*v1alpha1.PromptConfiguration
+	for _, promptConfig := range promptConfigs {
+		if promptConfig == nil || promptConfig.PromptCreationMode == nil ||
+			*promptConfig.PromptCreationMode == string(svcsdktypes.CreationModeDefault) {
+			continue
+		}
+		nonDefault = append(nonDefault, promptConfig)
+	}
+	return nonDefault
+}
+
+// inheritTags returns the resource tags merged with the tags inherited from
+// the resource's namespace and from the cluster default tags, and records the
+// inherited keys in the resource status so that FilterSystemTags can ignore
+// them.
+func (rm *resourceManager) inheritTags(
+	ctx context.Context,
+	r *resource,
+	md acktypes.ServiceControllerMetadata,
+) (map[string]*string, error) {
+	propagator := tags.GetPropagator()
+	if propagator == nil {
+		r.ko.Status.InheritedTagKeys = nil
+		return r.ko.Spec.Tags, nil
+	}
+	inherited, err := propagator.InheritedTags(ctx, r.ko.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	merged, inheritedKeys := tags.MergeInheritedTags(r.ko.Spec.Tags, inherited, r.ko, md)
+	r.ko.Status.InheritedTagKeys = nil
+	if len(inheritedKeys) > 0 {
+		r.ko.Status.InheritedTagKeys = aws.StringSlice(inheritedKeys)
+	}
+	return merged, nil
+}
+
+// ignoreInheritedTags removes the tags the resource inherited from its
+// namespace or from the cluster default tags, so that they aren't persisted
+// to the resource Spec.
+func ignoreInheritedTags(t acktags.Tags, r *resource) {
+	for _, key := range r.ko.Status.InheritedTagKeys {
+		if key != nil {
+			delete(t, *key)
+		}
+	}
+}
+
+// controllerTags returns the resource tags with the tags the controller adds
+// besides its --resource-tags: the tags inherited from the namespace or the
+// cluster default tags, the owner cluster tag, see ownership.go, and the
+// retained tag, see retention.go. It is called by EnsureTags.
+func (rm *resourceManager) controllerTags(
+	ctx context.Context,
+	r *resource,
+	md acktypes.ServiceControllerMetadata,
+) (map[string]*string, error) {
+	existingTags, err := rm.inheritTags(ctx, r, md)
+	if err != nil {
+		return nil, err
+	}
+	retained, err := rm.isRetained(ctx, r.ko, md)
+	if err != nil {
+		return nil, err
+	}
+	return tags.WithRetainedTag(tags.WithOwnerTag(existingTags), retained), nil
+}
+
+// ignoreControllerTags removes the tags added by controllerTags, so that
+// FilterSystemTags doesn't persist them to the resource Spec.
+func ignoreControllerTags(t acktags.Tags, r *resource) {
+	ignoreInheritedTags(t, r)
+	delete(t, tags.OwnerClusterTagKey)
+	delete(t, tags.RetainedTagKey)
+}
+
+// ResourceARN implements tags.Adapter.
+func (rm *resourceManager) ResourceARN(r *resource) string {
+	return string(*r.ko.Status.ACKResourceMetadata.ARN)
+}
+
+// ResourceTags implements tags.Adapter.
+func (rm *resourceManager) ResourceTags(r *resource) map[string]*string {
+	return r.ko.Spec.Tags
+}
+
+// tagEngine returns the engine used to read and synchronise the agent's tags.
+func (rm *resourceManager) tagEngine() *tags.Engine[*resource] {
+	return tags.NewEngine[*resource](rm.tracedSDKAPI(), rm.metrics, rm)
+}
+
 // getTags retrieves the resource's associated tags.
 func (rm *resourceManager) getTags(
 	ctx context.Context,
-	resourceARN string,
+	r *resource,
 ) (map[string]*string, error) {
-	return tags.GetResourceTags(ctx, rm.sdkapi, rm.metrics, resourceARN)
+	return rm.tagEngine().Get(ctx, r)
 }
 
-// syncTags keeps the resource's tags in sync.
+// syncTags keeps the resource's tags in sync and reports the outcome in the
+// TagsSynced condition and in an Event. On failure the condition is set on latest, which is
+// the resource whose status is saved when the update fails.
 func (rm *resourceManager) syncTags(
 	ctx context.Context,
 	desired *resource,
 	latest *resource,
 ) (err error) {
-	return tags.SyncResourceTags(
-		ctx,
-		rm.sdkapi,
-		rm.metrics,
-		string(*latest.ko.Status.ACKResourceMetadata.ARN),
-		desired.ko.Spec.Tags,
-		latest.ko.Spec.Tags,
-	)
+	ctx, span := startAgentSpan(ctx, "Agent.SyncTags", latest.ko)
+	defer func() {
+		endAgentSpan(span, nil, err)
+	}()
+	err = rm.tagEngine().Sync(ctx, desired, latest)
+	recordTagsEvent(desired.ko, err)
+	if err != nil {
+		setTagsSyncedCondition(latest.ko, err)
+		return err
+	}
+	setTagsSyncedCondition(desired.ko, nil)
+	return nil
+}
+
+// setTagsSyncedCondition sets the TagsSynced condition to False with the error
+// message, which lists the tags that failed, or to True if err is nil.
+func setTagsSyncedCondition(ko *v1alpha1.Agent, err error) {
+	status := corev1.ConditionTrue
+	var message *string
+	if err != nil {
+		status = corev1.ConditionFalse
+		message = aws.String(err.Error())
+	}
+	setCondition(ko, ConditionTypeTagsSynced, status, message)
+}
+
+// setCondition sets the status and message of the agent condition of the
+// given type, adding the condition if needed. The transition time changes
+// with the status.
+func setCondition(
+	ko *v1alpha1.Agent,
+	conditionType ackv1alpha1.ConditionType,
+	status corev1.ConditionStatus,
+	message *string,
+) {
+	var condition *ackv1alpha1.Condition
+	for _, c := range ko.Status.Conditions {
+		if c.Type == conditionType {
+			condition = c
+			break
+		}
+	}
+	if condition == nil {
+		condition = &ackv1alpha1.Condition{
+			Type: conditionType,
+		}
+		ko.Status.Conditions = append(ko.Status.Conditions, condition)
+	}
+	if condition.Status != status {
+		now := metav1.Now()
+		condition.LastTransitionTime = &now
+	}
+	condition.Status = status
+	condition.Message = message
 }
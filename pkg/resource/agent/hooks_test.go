@@ -0,0 +1,144 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func TestSetTagsSyncedCondition(t *testing.T) {
+	ko := &svcapitypes.Agent{}
+
+	setTagsSyncedCondition(ko, &tags.SyncError{
+		FailedToTag: []string{"team"},
+		Err:         errors.New("API error"),
+	})
+	if len(ko.Status.Conditions) != 1 {
+		t.Fatalf("Expected 1 condition, got %d", len(ko.Status.Conditions))
+	}
+	condition := ko.Status.Conditions[0]
+	if condition.Type != ConditionTypeTagsSynced || condition.Status != corev1.ConditionFalse {
+		t.Errorf("Expected %s=False, got %s=%s", ConditionTypeTagsSynced, condition.Type, condition.Status)
+	}
+	if condition.Message == nil || *condition.Message != "failed to add or update tags [team]: API error" {
+		t.Errorf("Unexpected condition message %v", condition.Message)
+	}
+	failedAt := condition.LastTransitionTime
+
+	setTagsSyncedCondition(ko, nil)
+	if len(ko.Status.Conditions) != 1 {
+		t.Fatalf("Expected the condition to be updated in place, got %d conditions", len(ko.Status.Conditions))
+	}
+	if condition.Status != corev1.ConditionTrue || condition.Message != nil {
+		t.Errorf("Expected %s=True without message, got %s %v", ConditionTypeTagsSynced, condition.Status, condition.Message)
+	}
+	if condition.LastTransitionTime == failedAt {
+		t.Error("Expected LastTransitionTime to be updated on status change")
+	}
+}
+
+func TestSyncAgentComponents_WaitsForPreparableStatus(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	desired := dryRunAgent()
+	desired.ko.Annotations = nil
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	desired.ko.Status = res.(*resource).ko.Status
+	latest := waitForAgent(t, rm, desired)
+	delta := ackcompare.NewDelta()
+	delta.Add("Spec.AgentStatus", latest.ko.Status.AgentStatus, "PREPARED")
+
+	for _, status := range []string{"CREATING", "UPDATING", "PREPARING"} {
+		server.ResetCalls()
+		err := rm.syncAgentComponents(ctx, desired, latest, delta, true, aws.String(status))
+		var requeue *ackrequeue.RequeueNeededAfter
+		if !errors.As(err, &requeue) {
+			t.Errorf("syncAgentComponents() while %s error = %v, want a requeue", status, err)
+		}
+		if calls := server.Calls(); len(calls) != 0 {
+			t.Errorf("calls while %s = %v, want none", status, calls)
+		}
+	}
+
+	server.ResetCalls()
+	if err := rm.syncAgentComponents(ctx, desired, latest, delta, true, aws.String("NOT_PREPARED")); err != nil {
+		t.Fatalf("syncAgentComponents() error = %v", err)
+	}
+	if calls := server.Calls(); !reflect.DeepEqual(calls, []string{"PrepareAgent"}) {
+		t.Errorf("calls = %v, want PrepareAgent", calls)
+	}
+}
+
+func TestEnsureTags_RecordsInheritedKeysInStatus(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ml",
+			Annotations: map[string]string{
+				tags.NamespaceTagAnnotationPrefix + "team":        "ml",
+				tags.NamespaceTagAnnotationPrefix + "cost-center": "1234",
+			},
+		},
+	}
+	tags.SetPropagator(tags.NewPropagator(
+		fake.NewClientBuilder().WithObjects(namespace).Build(),
+		types.NamespacedName{},
+	))
+	t.Cleanup(func() { tags.SetPropagator(nil) })
+
+	rm := newFakeResourceManager(t, fakebedrockagent.NewServer(t))
+	desired := &resource{ko: &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ml", Name: "my-agent"},
+		Spec: svcapitypes.AgentSpec{
+			Tags: map[string]*string{"team": aws.String("search")},
+		},
+	}}
+	if err := rm.EnsureTags(context.Background(), desired, acktypes.ServiceControllerMetadata{}); err != nil {
+		t.Fatalf("EnsureTags() error = %v", err)
+	}
+	if got := aws.ToStringSlice(desired.ko.Status.InheritedTagKeys); !reflect.DeepEqual(got, []string{"cost-center"}) {
+		t.Errorf("InheritedTagKeys = %v, want [cost-center]", got)
+	}
+	if len(desired.ko.GetAnnotations()) != 0 {
+		t.Errorf("Expected no annotations, got %v", desired.ko.GetAnnotations())
+	}
+
+	rm.FilterSystemTags(desired, nil)
+	if _, ok := desired.ko.Spec.Tags["cost-center"]; ok {
+		t.Errorf("Expected the inherited tag to be filtered, got %v", desired.ko.Spec.Tags)
+	}
+	if aws.ToString(desired.ko.Spec.Tags["team"]) != "search" {
+		t.Errorf("Expected the resource tag to be kept, got %v", desired.ko.Spec.Tags)
+	}
+}
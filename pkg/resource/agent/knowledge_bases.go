@@ -0,0 +1,228 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"sort"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+type knowledgeBasesClient interface {
+	ListAgentKnowledgeBases(context.Context, *svcsdk.ListAgentKnowledgeBasesInput, ...func(*svcsdk.Options)) (*svcsdk.ListAgentKnowledgeBasesOutput, error)
+	AssociateAgentKnowledgeBase(context.Context, *svcsdk.AssociateAgentKnowledgeBaseInput, ...func(*svcsdk.Options)) (*svcsdk.AssociateAgentKnowledgeBaseOutput, error)
+	UpdateAgentKnowledgeBase(context.Context, *svcsdk.UpdateAgentKnowledgeBaseInput, ...func(*svcsdk.Options)) (*svcsdk.UpdateAgentKnowledgeBaseOutput, error)
+	DisassociateAgentKnowledgeBase(context.Context, *svcsdk.DisassociateAgentKnowledgeBaseInput, ...func(*svcsdk.Options)) (*svcsdk.DisassociateAgentKnowledgeBaseOutput, error)
+}
+
+// getKnowledgeBases returns the knowledge bases associated with the agent's
+// DRAFT version, sorted by ID.
+func getKnowledgeBases(
+	ctx context.Context,
+	client knowledgeBasesClient,
+	metrics metricsRecorder,
+	agentID string,
+) (knowledgeBases []*svcapitypes.InlineKnowledgeBase, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("hooks.getKnowledgeBases")
+	defer func() {
+		exit(err)
+	}()
+
+	knowledgeBases = []*svcapitypes.InlineKnowledgeBase{}
+	input := &svcsdk.ListAgentKnowledgeBasesInput{
+		AgentId:      aws.String(agentID),
+		AgentVersion: aws.String(draftAgentVersion),
+	}
+	for {
+		var resp *svcsdk.ListAgentKnowledgeBasesOutput
+		resp, err = client.ListAgentKnowledgeBases(ctx, input)
+		metrics.RecordAPICall("READ_MANY", "ListAgentKnowledgeBases", err)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range resp.AgentKnowledgeBaseSummaries {
+			knowledgeBase := &svcapitypes.InlineKnowledgeBase{
+				Description:     summary.Description,
+				KnowledgeBaseID: summary.KnowledgeBaseId,
+			}
+			if summary.KnowledgeBaseState != "" {
+				knowledgeBase.KnowledgeBaseState = aws.String(string(summary.KnowledgeBaseState))
+			}
+			knowledgeBases = append(knowledgeBases, knowledgeBase)
+		}
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+	sort.Slice(knowledgeBases, func(i, j int) bool {
+		return aws.ToString(knowledgeBases[i].KnowledgeBaseID) < aws.ToString(knowledgeBases[j].KnowledgeBaseID)
+	})
+	return knowledgeBases, nil
+}
+
+// syncKnowledgeBases associates, updates and disassociates knowledge bases on
+// the agent's DRAFT version so that they match the desired list. Knowledge
+// bases are matched by ID against the associations listed from AWS, like
+// syncActionGroups does. It returns whether any association was modified.
+func syncKnowledgeBases(
+	ctx context.Context,
+	client knowledgeBasesClient,
+	metrics metricsRecorder,
+	agentID string,
+	desired []*svcapitypes.InlineKnowledgeBase,
+) (modified bool, err error) {
+	rlog := ackrtlog.FromContext(ctx)
+	exit := rlog.Trace("hooks.syncKnowledgeBases")
+	defer func() {
+		exit(err)
+	}()
+
+	latest, err := getKnowledgeBases(ctx, client, metrics, agentID)
+	if err != nil {
+		return false, err
+	}
+	latestByID := make(map[string]*svcapitypes.InlineKnowledgeBase, len(latest))
+	for _, knowledgeBase := range latest {
+		if knowledgeBase != nil {
+			latestByID[aws.ToString(knowledgeBase.KnowledgeBaseID)] = knowledgeBase
+		}
+	}
+	desiredByID := make(map[string]*svcapitypes.InlineKnowledgeBase, len(desired))
+	for _, knowledgeBase := range desired {
+		if knowledgeBase != nil {
+			desiredByID[aws.ToString(knowledgeBase.KnowledgeBaseID)] = knowledgeBase
+		}
+	}
+
+	for id := range latestByID {
+		if _, ok := desiredByID[id]; ok {
+			continue
+		}
+		_, err = client.DisassociateAgentKnowledgeBase(ctx, &svcsdk.DisassociateAgentKnowledgeBaseInput{
+			AgentId:         aws.String(agentID),
+			AgentVersion:    aws.String(draftAgentVersion),
+			KnowledgeBaseId: aws.String(id),
+		})
+		metrics.RecordAPICall("DELETE", "DisassociateAgentKnowledgeBase", err)
+		if err != nil {
+			return modified, err
+		}
+		modified = true
+	}
+
+	for _, knowledgeBase := range desired {
+		if knowledgeBase == nil {
+			continue
+		}
+		latestKnowledgeBase, exists := latestByID[aws.ToString(knowledgeBase.KnowledgeBaseID)]
+		if !exists {
+			input := &svcsdk.AssociateAgentKnowledgeBaseInput{
+				AgentId:         aws.String(agentID),
+				AgentVersion:    aws.String(draftAgentVersion),
+				Description:     knowledgeBase.Description,
+				KnowledgeBaseId: knowledgeBase.KnowledgeBaseID,
+			}
+			if knowledgeBase.KnowledgeBaseState != nil {
+				input.KnowledgeBaseState = svcsdktypes.KnowledgeBaseState(*knowledgeBase.KnowledgeBaseState)
+			}
+			_, err = client.AssociateAgentKnowledgeBase(ctx, input)
+			metrics.RecordAPICall("CREATE", "AssociateAgentKnowledgeBase", err)
+			if err != nil {
+				return modified, err
+			}
+			modified = true
+			continue
+		}
+		if equalKnowledgeBases(knowledgeBase, latestKnowledgeBase) {
+			continue
+		}
+		input := &svcsdk.UpdateAgentKnowledgeBaseInput{
+			AgentId:         aws.String(agentID),
+			AgentVersion:    aws.String(draftAgentVersion),
+			Description:     knowledgeBase.Description,
+			KnowledgeBaseId: knowledgeBase.KnowledgeBaseID,
+		}
+		if knowledgeBase.KnowledgeBaseState != nil {
+			input.KnowledgeBaseState = svcsdktypes.KnowledgeBaseState(*knowledgeBase.KnowledgeBaseState)
+		}
+		_, err = client.UpdateAgentKnowledgeBase(ctx, input)
+		metrics.RecordAPICall("UPDATE", "UpdateAgentKnowledgeBase", err)
+		if err != nil {
+			return modified, err
+		}
+		modified = true
+	}
+	return modified, nil
+}
+
+// compareKnowledgeBases adds Spec.KnowledgeBases to the delta when the desired
+// knowledge bases differ from the latest ones. Knowledge bases are only
+// compared when the desired resource declares them.
+func compareKnowledgeBases(
+	delta *ackcompare.Delta,
+	desired *resource,
+	latest *resource,
+) {
+	if desired.ko.Spec.KnowledgeBases == nil {
+		return
+	}
+	latestByID := map[string]*svcapitypes.InlineKnowledgeBase{}
+	for _, knowledgeBase := range latest.ko.Spec.KnowledgeBases {
+		if knowledgeBase != nil {
+			latestByID[aws.ToString(knowledgeBase.KnowledgeBaseID)] = knowledgeBase
+		}
+	}
+	desiredCount := 0
+	for _, knowledgeBase := range desired.ko.Spec.KnowledgeBases {
+		if knowledgeBase == nil {
+			continue
+		}
+		desiredCount++
+		latestKnowledgeBase, ok := latestByID[aws.ToString(knowledgeBase.KnowledgeBaseID)]
+		if !ok || !equalKnowledgeBases(knowledgeBase, latestKnowledgeBase) {
+			delta.Add("Spec.KnowledgeBases", desired.ko.Spec.KnowledgeBases, latest.ko.Spec.KnowledgeBases)
+			return
+		}
+	}
+	if desiredCount != len(latestByID) {
+		delta.Add("Spec.KnowledgeBases", desired.ko.Spec.KnowledgeBases, latest.ko.Spec.KnowledgeBases)
+	}
+}
+
+// equalKnowledgeBases compares two knowledge base associations, treating an
+// unset state as ENABLED.
+func equalKnowledgeBases(
+	a *svcapitypes.InlineKnowledgeBase,
+	b *svcapitypes.InlineKnowledgeBase,
+) bool {
+	enabled := string(svcsdktypes.KnowledgeBaseStateEnabled)
+	stateA, stateB := enabled, enabled
+	if a.KnowledgeBaseState != nil {
+		stateA = *a.KnowledgeBaseState
+	}
+	if b.KnowledgeBaseState != nil {
+		stateB = *b.KnowledgeBaseState
+	}
+	return stateA == stateB &&
+		aws.ToString(a.Description) == aws.ToString(b.Description)
+}
@@ -0,0 +1,227 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+// mockKnowledgeBasesClient is a mock implementation of the
+// knowledgeBasesClient interface. Mutating calls are recorded in calls as
+// "<Operation>:<knowledge base ID>".
+type mockKnowledgeBasesClient struct {
+	summaries    []svcsdktypes.AgentKnowledgeBaseSummary
+	associateErr error
+	calls        []string
+}
+
+func (m *mockKnowledgeBasesClient) ListAgentKnowledgeBases(ctx context.Context, input *svcsdk.ListAgentKnowledgeBasesInput, opts ...func(*svcsdk.Options)) (*svcsdk.ListAgentKnowledgeBasesOutput, error) {
+	if input.NextToken == nil && len(m.summaries) > 1 {
+		return &svcsdk.ListAgentKnowledgeBasesOutput{
+			AgentKnowledgeBaseSummaries: m.summaries[:1],
+			NextToken:                   aws.String("page-2"),
+		}, nil
+	}
+	if input.NextToken != nil {
+		return &svcsdk.ListAgentKnowledgeBasesOutput{AgentKnowledgeBaseSummaries: m.summaries[1:]}, nil
+	}
+	return &svcsdk.ListAgentKnowledgeBasesOutput{AgentKnowledgeBaseSummaries: m.summaries}, nil
+}
+
+func (m *mockKnowledgeBasesClient) AssociateAgentKnowledgeBase(ctx context.Context, input *svcsdk.AssociateAgentKnowledgeBaseInput, opts ...func(*svcsdk.Options)) (*svcsdk.AssociateAgentKnowledgeBaseOutput, error) {
+	m.calls = append(m.calls, "AssociateAgentKnowledgeBase:"+aws.ToString(input.KnowledgeBaseId))
+	return &svcsdk.AssociateAgentKnowledgeBaseOutput{}, m.associateErr
+}
+
+func (m *mockKnowledgeBasesClient) UpdateAgentKnowledgeBase(ctx context.Context, input *svcsdk.UpdateAgentKnowledgeBaseInput, opts ...func(*svcsdk.Options)) (*svcsdk.UpdateAgentKnowledgeBaseOutput, error) {
+	m.calls = append(m.calls, "UpdateAgentKnowledgeBase:"+aws.ToString(input.KnowledgeBaseId))
+	return &svcsdk.UpdateAgentKnowledgeBaseOutput{}, nil
+}
+
+func (m *mockKnowledgeBasesClient) DisassociateAgentKnowledgeBase(ctx context.Context, input *svcsdk.DisassociateAgentKnowledgeBaseInput, opts ...func(*svcsdk.Options)) (*svcsdk.DisassociateAgentKnowledgeBaseOutput, error) {
+	m.calls = append(m.calls, "DisassociateAgentKnowledgeBase:"+aws.ToString(input.KnowledgeBaseId))
+	return &svcsdk.DisassociateAgentKnowledgeBaseOutput{}, nil
+}
+
+func knowledgeBase(id string, description string) *svcapitypes.InlineKnowledgeBase {
+	return &svcapitypes.InlineKnowledgeBase{
+		KnowledgeBaseID: aws.String(id),
+		Description:     aws.String(description),
+	}
+}
+
+func TestGetKnowledgeBases(t *testing.T) {
+	client := &mockKnowledgeBasesClient{
+		summaries: []svcsdktypes.AgentKnowledgeBaseSummary{
+			{
+				KnowledgeBaseId:    aws.String("KB00000002"),
+				Description:        aws.String("Support tickets"),
+				KnowledgeBaseState: svcsdktypes.KnowledgeBaseStateDisabled,
+			},
+			{
+				KnowledgeBaseId:    aws.String("KB00000001"),
+				Description:        aws.String("Product documentation"),
+				KnowledgeBaseState: svcsdktypes.KnowledgeBaseStateEnabled,
+			},
+		},
+	}
+
+	got, err := getKnowledgeBases(context.TODO(), client, &mockMetricsRecorder{}, "AGENT12345")
+	if err != nil {
+		t.Fatalf("getKnowledgeBases() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("getKnowledgeBases() returned %d knowledge bases, want 2", len(got))
+	}
+	if aws.ToString(got[0].KnowledgeBaseID) != "KB00000001" || aws.ToString(got[0].KnowledgeBaseState) != "ENABLED" {
+		t.Errorf("getKnowledgeBases()[0] = %+v, want KB00000001 ENABLED", got[0])
+	}
+	if aws.ToString(got[1].KnowledgeBaseID) != "KB00000002" || aws.ToString(got[1].KnowledgeBaseState) != "DISABLED" {
+		t.Errorf("getKnowledgeBases()[1] = %+v, want KB00000002 DISABLED", got[1])
+	}
+}
+
+func TestSyncKnowledgeBases(t *testing.T) {
+	tests := []struct {
+		name         string
+		desired      []*svcapitypes.InlineKnowledgeBase
+		latest       []*svcapitypes.InlineKnowledgeBase
+		associateErr error
+		wantCalls    []string
+		wantModified bool
+		wantErr      bool
+	}{
+		{
+			name: "associate, update and disassociate",
+			desired: []*svcapitypes.InlineKnowledgeBase{
+				knowledgeBase("KB00000001", "Product documentation"),
+				knowledgeBase("KB00000002", "Updated description"),
+				knowledgeBase("KB00000004", "New knowledge base"),
+			},
+			latest: []*svcapitypes.InlineKnowledgeBase{
+				knowledgeBase("KB00000001", "Product documentation"),
+				knowledgeBase("KB00000002", "Support tickets"),
+				knowledgeBase("KB00000003", "Retired knowledge base"),
+			},
+			wantCalls: []string{
+				"AssociateAgentKnowledgeBase:KB00000004",
+				"DisassociateAgentKnowledgeBase:KB00000003",
+				"UpdateAgentKnowledgeBase:KB00000002",
+			},
+			wantModified: true,
+		},
+		{
+			name:      "nothing to do",
+			desired:   []*svcapitypes.InlineKnowledgeBase{knowledgeBase("KB00000001", "Product documentation")},
+			latest:    []*svcapitypes.InlineKnowledgeBase{knowledgeBase("KB00000001", "Product documentation")},
+			wantCalls: []string{},
+		},
+		{
+			name:         "associate error",
+			desired:      []*svcapitypes.InlineKnowledgeBase{knowledgeBase("KB00000001", "Product documentation")},
+			associateErr: errors.New("API error"),
+			wantCalls:    []string{"AssociateAgentKnowledgeBase:KB00000001"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockKnowledgeBasesClient{
+				associateErr: tt.associateErr,
+				calls:        []string{},
+			}
+			for _, knowledgeBase := range tt.latest {
+				client.summaries = append(client.summaries, svcsdktypes.AgentKnowledgeBaseSummary{
+					Description:     knowledgeBase.Description,
+					KnowledgeBaseId: knowledgeBase.KnowledgeBaseID,
+				})
+			}
+			modified, err := syncKnowledgeBases(context.TODO(), client, &mockMetricsRecorder{}, "AGENT12345", tt.desired)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("syncKnowledgeBases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if modified != tt.wantModified {
+				t.Errorf("syncKnowledgeBases() modified = %v, want %v", modified, tt.wantModified)
+			}
+			sort.Strings(client.calls)
+			if strings.Join(client.calls, ",") != strings.Join(tt.wantCalls, ",") {
+				t.Errorf("syncKnowledgeBases() calls = %v, want %v", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCompareKnowledgeBases(t *testing.T) {
+	enabled := knowledgeBase("KB00000001", "Product documentation")
+	enabled.KnowledgeBaseState = aws.String("ENABLED")
+	disabled := knowledgeBase("KB00000001", "Product documentation")
+	disabled.KnowledgeBaseState = aws.String("DISABLED")
+
+	tests := []struct {
+		name      string
+		desired   []*svcapitypes.InlineKnowledgeBase
+		latest    []*svcapitypes.InlineKnowledgeBase
+		wantDelta bool
+	}{
+		{
+			name:   "unmanaged",
+			latest: []*svcapitypes.InlineKnowledgeBase{enabled},
+		},
+		{
+			name:    "unset state defaults to enabled",
+			desired: []*svcapitypes.InlineKnowledgeBase{knowledgeBase("KB00000001", "Product documentation")},
+			latest:  []*svcapitypes.InlineKnowledgeBase{enabled},
+		},
+		{
+			name:      "changed state",
+			desired:   []*svcapitypes.InlineKnowledgeBase{disabled},
+			latest:    []*svcapitypes.InlineKnowledgeBase{enabled},
+			wantDelta: true,
+		},
+		{
+			name:      "changed description",
+			desired:   []*svcapitypes.InlineKnowledgeBase{knowledgeBase("KB00000001", "Support tickets")},
+			latest:    []*svcapitypes.InlineKnowledgeBase{enabled},
+			wantDelta: true,
+		},
+		{
+			name:      "extra association",
+			desired:   []*svcapitypes.InlineKnowledgeBase{},
+			latest:    []*svcapitypes.InlineKnowledgeBase{enabled},
+			wantDelta: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := &resource{ko: &svcapitypes.Agent{Spec: svcapitypes.AgentSpec{KnowledgeBases: tt.desired}}}
+			latest := &resource{ko: &svcapitypes.Agent{Spec: svcapitypes.AgentSpec{KnowledgeBases: tt.latest}}}
+			delta := ackcompare.NewDelta()
+			compareKnowledgeBases(delta, desired, latest)
+			if got := delta.DifferentAt("Spec.KnowledgeBases"); got != tt.wantDelta {
+				t.Errorf("compareKnowledgeBases() delta = %v, want %v", got, tt.wantDelta)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcfg "github.com/aws-controllers-k8s/runtime/pkg/config"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackmetrics "github.com/aws-controllers-k8s/runtime/pkg/metrics"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+// newFakeResourceManager returns a resourceManager whose client sends its
+// requests to the fake Bedrock Agent API server.
+func newFakeResourceManager(t *testing.T, server *fakebedrockagent.Server) *resourceManager {
+	rm, err := newResourceManager(
+		ackcfg.Config{},
+		server.Config(),
+		logr.Discard(),
+		ackmetrics.NewMetrics("bedrockagent"),
+		nil,
+		ackv1alpha1.AWSAccountID(fakebedrockagent.AccountID),
+		ackv1alpha1.AWSRegion(fakebedrockagent.Region),
+	)
+	if err != nil {
+		t.Fatalf("newResourceManager() error = %v", err)
+	}
+	return rm
+}
+
+// syncAgent runs one reconciliation step against the fake server: it reads
+// the agent and calls Update if the desired spec differs from the observed
+// one. It returns the observed agent and the delta.
+func syncAgent(
+	t *testing.T,
+	rm *resourceManager,
+	desired *resource,
+) (*resource, *ackcompare.Delta) {
+	t.Helper()
+	ctx := context.Background()
+	res, err := rm.ReadOne(ctx, desired)
+	if err != nil {
+		t.Fatalf("ReadOne() error = %v", err)
+	}
+	latest := res.(*resource)
+	delta := newResourceDelta(desired, latest)
+	if len(delta.Differences) > 0 {
+		if _, err := rm.Update(ctx, desired, latest, delta); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	return latest, delta
+}
+
+// deltaPaths returns which of the given paths differ in the delta, and
+// whether the delta has differences at other paths.
+func deltaPaths(delta *ackcompare.Delta, paths ...string) ([]string, bool) {
+	var different []string
+	for _, path := range paths {
+		if delta.DifferentAt(path) {
+			different = append(different, path)
+		}
+	}
+	return different, delta.DifferentExcept(paths...)
+}
+
+// waitForAgent reads the agent until it is no longer in an in-progress
+// status, as the controller does when it requeues the resource.
+func waitForAgent(t *testing.T, rm *resourceManager, desired *resource) *resource {
+	t.Helper()
+	for i := 0; i < 5; i++ {
+		res, err := rm.ReadOne(context.Background(), desired)
+		if err != nil {
+			t.Fatalf("ReadOne() error = %v", err)
+		}
+		latest := res.(*resource)
+		switch aws.ToString(latest.ko.Status.AgentStatus) {
+		case fakebedrockagent.StatusCreating, fakebedrockagent.StatusPreparing:
+			continue
+		}
+		return latest
+	}
+	t.Fatalf("agent %s is still in progress", aws.ToString(desired.ko.Status.AgentID))
+	return nil
+}
+
+func TestAgentLifecycle_FakeServer(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	desired := &resource{ko: &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "support", Namespace: "default"},
+		Spec: svcapitypes.AgentSpec{
+			AgentName:               aws.String("support"),
+			AgentResourceRoleARN:    aws.String("arn:aws:iam::123456789012:role/agent-role"),
+			FoundationModel:         aws.String("anthropic.claude-3-haiku-20240307-v1:0"),
+			Instruction:             aws.String(strings.Repeat("You are a helpful support agent. ", 3)),
+			IdleSessionTTLInSeconds: aws.Int64(600),
+			AgentCollaboration:      aws.String("DISABLED"),
+			OrchestrationType:       aws.String("DEFAULT"),
+			Tags:                    map[string]*string{"team": aws.String("support")},
+		},
+	}}
+
+	// Create
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	created := res.(*resource)
+	if created.ko.Status.AgentID == nil {
+		t.Fatal("Create() didn't set Status.AgentID")
+	}
+	if status := aws.ToString(created.ko.Status.AgentStatus); status != fakebedrockagent.StatusCreating {
+		t.Errorf("status after Create() = %s, want CREATING", status)
+	}
+	agentID := *created.ko.Status.AgentID
+	agentARN := string(*created.ko.Status.ACKResourceMetadata.ARN)
+	if tags := server.Tags(agentARN); !reflect.DeepEqual(tags, map[string]string{"team": "support"}) {
+		t.Errorf("tags after Create() = %v, want team=support", tags)
+	}
+	desired = created
+
+	// NOT_PREPARED: the default prompt override configuration is late
+	// initialized and the agent is prepared.
+	latest := waitForAgent(t, rm, desired)
+	if status := aws.ToString(latest.ko.Status.AgentStatus); status != fakebedrockagent.StatusNotPrepared {
+		t.Errorf("status after creation = %s, want NOT_PREPARED", status)
+	}
+	res, err = rm.LateInitialize(ctx, desired)
+	if err != nil {
+		t.Fatalf("LateInitialize() error = %v", err)
+	}
+	desired = res.(*resource)
+	if desired.ko.Spec.PromptOverrideConfiguration == nil {
+		t.Error("LateInitialize() didn't set Spec.PromptOverrideConfiguration")
+	}
+	server.ResetCalls()
+	_, delta := syncAgent(t, rm, desired)
+	if paths, other := deltaPaths(delta, "Spec.AgentStatus"); len(paths) != 1 || other {
+		t.Errorf("delta after creation = %v (other differences: %t), want only Spec.AgentStatus", paths, other)
+	}
+	if want := []string{"GetAgent", "ListTagsForResource", "PrepareAgent"}; !reflect.DeepEqual(server.Calls(), want) {
+		t.Errorf("calls = %v, want %v", server.Calls(), want)
+	}
+
+	// PREPARED: nothing left to do.
+	waitForAgent(t, rm, desired)
+	latest, delta = syncAgent(t, rm, desired)
+	if status := aws.ToString(latest.ko.Status.AgentStatus); status != fakebedrockagent.StatusPrepared || len(delta.Differences) > 0 {
+		t.Errorf("status = %s with %d differences, want PREPARED and no delta", status, len(delta.Differences))
+	}
+
+	// Update the instruction and the tags: UpdateAgent is followed by a
+	// single PrepareAgent, and tags are added before they are removed.
+	desired = latest
+	desired.ko.Spec.Instruction = aws.String(strings.Repeat("You are a helpful billing agent. ", 3))
+	desired.ko.Spec.Tags = map[string]*string{"cost-center": aws.String("1234")}
+	server.ResetCalls()
+	_, delta = syncAgent(t, rm, desired)
+	if paths, other := deltaPaths(delta, "Spec.Instruction", "Spec.Tags"); len(paths) != 2 || other {
+		t.Errorf("delta after update = %v (other differences: %t), want Spec.Instruction and Spec.Tags", paths, other)
+	}
+	wantCalls := []string{"GetAgent", "ListTagsForResource", "TagResource", "UntagResource", "UpdateAgent", "PrepareAgent"}
+	if !reflect.DeepEqual(server.Calls(), wantCalls) {
+		t.Errorf("calls = %v, want %v", server.Calls(), wantCalls)
+	}
+	agent, _ := server.Agent(agentID)
+	if agent["instruction"] != *desired.ko.Spec.Instruction {
+		t.Errorf("instruction = %v, want %s", agent["instruction"], *desired.ko.Spec.Instruction)
+	}
+	if tags := server.Tags(agentARN); !reflect.DeepEqual(tags, map[string]string{"cost-center": "1234"}) {
+		t.Errorf("tags after update = %v, want cost-center=1234", tags)
+	}
+	waitForAgent(t, rm, desired)
+	latest, delta = syncAgent(t, rm, desired)
+	if status := aws.ToString(latest.ko.Status.AgentStatus); status != fakebedrockagent.StatusPrepared || len(delta.Differences) > 0 {
+		t.Errorf("status = %s with %d differences, want PREPARED and no delta", status, len(delta.Differences))
+	}
+
+	// Delete
+	if _, err := rm.Delete(ctx, latest); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	res, err = rm.ReadOne(ctx, latest)
+	if err != nil {
+		t.Fatalf("ReadOne() while DELETING error = %v", err)
+	}
+	if status := aws.ToString(res.(*resource).ko.Status.AgentStatus); status != fakebedrockagent.StatusDeleting {
+		t.Errorf("status after Delete() = %s, want DELETING", status)
+	}
+	if _, err := rm.ReadOne(ctx, latest); !errors.Is(err, ackerr.NotFound) {
+		t.Errorf("ReadOne() after deletion error = %v, want NotFound", err)
+	}
+}
+
+func TestAgentCreate_FakeServerConflict(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	desired := &resource{ko: &svcapitypes.Agent{
+		Spec: svcapitypes.AgentSpec{
+			AgentName:            aws.String("support"),
+			AgentResourceRoleARN: aws.String("arn:aws:iam::123456789012:role/agent-role"),
+		},
+	}}
+	if _, err := rm.Create(ctx, desired); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	res, err := rm.Create(ctx, desired)
+	if err == nil {
+		t.Fatal("second Create() error = nil, want a conflict")
+	}
+	var recoverable bool
+	for _, condition := range res.(*resource).ko.Status.Conditions {
+		if condition.Type == ackv1alpha1.ConditionTypeRecoverable && strings.Contains(aws.ToString(condition.Message), "ConflictException") {
+			recoverable = true
+		}
+	}
+	if !recoverable {
+		t.Errorf("expected a Recoverable condition reporting the conflict, got %v", res.(*resource).ko.Status.Conditions)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/maintenance"
+)
+
+// ConditionTypeChangesPending reports the changes to the agent deferred until
+// its maintenance window opens.
+const ConditionTypeChangesPending ackv1alpha1.ConditionType = "ChangesPending"
+
+var maintenanceNow = time.Now
+
+// parseMaintenanceWindow returns the maintenance window of the agent spec, or
+// nil when it has none.
+func parseMaintenanceWindow(w *svcapitypes.MaintenanceWindow) (*maintenance.Window, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return maintenance.Parse(aws.ToString(w.Schedule), aws.ToString(w.Duration), aws.ToString(w.TimeZone))
+}
+
+// deferChanges returns the desired agent, with the status of the latest one
+// and the ChangesPending condition, when its changes wait for the maintenance
+// window to open. Tag changes, made before, and the changes to an agent that
+// isn't PREPARED, which they wouldn't disrupt, are never deferred, nor are
+// the changes to an agent carrying the maintenance.OverrideAnnotation.
+func deferChanges(
+	desired *resource,
+	latest *resource,
+	delta *ackcompare.Delta,
+) (*resource, bool, error) {
+	if maintenance.IsOverridden(desired.ko) || delta.DifferentAt("Spec.AgentStatus") {
+		return nil, false, nil
+	}
+	window, err := parseMaintenanceWindow(desired.ko.Spec.MaintenanceWindow)
+	if err != nil {
+		return nil, false, ackerr.NewTerminalError(fmt.Errorf("maintenanceWindow: %w", err))
+	}
+	if window == nil {
+		return nil, false, nil
+	}
+	open, next := window.Open(maintenanceNow())
+	if open {
+		return nil, false, nil
+	}
+	var fields []string
+	for _, difference := range delta.Differences {
+		if path := differencePath(difference); path != "Spec.Tags" {
+			fields = append(fields, path)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, false, nil
+	}
+
+	opens := "never opens"
+	if !next.IsZero() {
+		opens = "opens at " + next.Format(time.RFC3339)
+	}
+	message := fmt.Sprintf(
+		"Changes to %s are deferred: the maintenance window %s. Set the %s: \"true\" annotation to apply them now",
+		strings.Join(fields, ", "), opens, maintenance.OverrideAnnotation,
+	)
+	deferred := &resource{ko: desired.ko.DeepCopy()}
+	latest.ko.Status.DeepCopyInto(&deferred.ko.Status)
+	setCondition(deferred.ko, ConditionTypeChangesPending, corev1.ConditionTrue, aws.String(message))
+	// The agent isn't synced, so that it is requeued and the window opening
+	// is noticed.
+	ackcondition.SetSynced(deferred, corev1.ConditionFalse, aws.String("Changes await the maintenance window"), nil)
+	return deferred, true, nil
+}
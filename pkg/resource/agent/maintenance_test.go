@@ -0,0 +1,114 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/maintenance"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func TestMaintenanceWindow_Update(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	desired := dryRunAgent()
+	desired.ko.Annotations = nil
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	desired.ko.Status = res.(*resource).ko.Status
+	waitForAgent(t, rm, desired)
+	syncAgent(t, rm, desired)
+	waitForAgent(t, rm, desired)
+
+	// Saturdays from 02:00 to 04:00 UTC, and it is Friday.
+	desired.ko.Spec.MaintenanceWindow = &svcapitypes.MaintenanceWindow{
+		Schedule: aws.String("0 2 * * SAT"),
+		Duration: aws.String("2h"),
+	}
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	maintenanceNow = func() time.Time { return now }
+	t.Cleanup(func() { maintenanceNow = time.Now })
+
+	desired.ko.Spec.Instruction = aws.String(strings.Repeat("You are a concise support agent. ", 5))
+	desired.ko.Spec.Tags = map[string]*string{"team": aws.String("support")}
+	server.ResetCalls()
+	update := func() *resource {
+		t.Helper()
+		latest := waitForAgent(t, rm, desired)
+		res, err := rm.Update(ctx, desired, latest, newResourceDelta(desired, latest))
+		if err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+		return res.(*resource)
+	}
+	updated := func() bool {
+		for _, call := range server.Calls() {
+			if call == "UpdateAgent" {
+				return true
+			}
+		}
+		return false
+	}
+
+	deferred := update()
+	c := agentCondition(deferred.ko, ConditionTypeChangesPending)
+	if c == nil || c.Status != corev1.ConditionTrue {
+		t.Fatalf("ChangesPending condition = %v, want True outside of the window", c)
+	}
+	if message := aws.ToString(c.Message); !strings.Contains(message, "Spec.Instruction") ||
+		strings.Contains(message, "Spec.Tags") || !strings.Contains(message, "2026-10-17T02:00:00Z") {
+		t.Errorf("ChangesPending message = %q, want the instruction deferred until the window opens", message)
+	}
+	if c := ackcondition.Synced(deferred); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("Synced condition = %v, want False while changes are deferred", c)
+	}
+	if updated() {
+		t.Errorf("UpdateAgent called outside of the window")
+	}
+	agent, _ := server.Agent(aws.ToString(desired.ko.Status.AgentID))
+	if tags := server.Tags(agent["agentArn"].(string)); tags["team"] != "support" {
+		t.Errorf("tags in AWS = %v, want the tags changed right away", tags)
+	}
+
+	// The override annotation applies the changes right away.
+	desired.ko.Annotations = map[string]string{maintenance.OverrideAnnotation: "true"}
+	server.ResetCalls()
+	update()
+	if !updated() {
+		t.Errorf("calls = %v, want UpdateAgent with the override annotation", server.Calls())
+	}
+
+	// Without it, the changes wait for the window to open.
+	desired.ko.Annotations = nil
+	desired.ko.Spec.Instruction = aws.String(strings.Repeat("You are a curt support agent. ", 5))
+	now = time.Date(2026, time.October, 17, 3, 0, 0, 0, time.UTC)
+	server.ResetCalls()
+	update()
+	if !updated() {
+		t.Errorf("calls = %v, want UpdateAgent in the window", server.Calls())
+	}
+}
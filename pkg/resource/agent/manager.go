@@ -308,9 +308,17 @@ func (rm *resourceManager) EnsureTags(
 		// Should never happen... if it does, it's buggy code.
 		panic("resource manager's EnsureTags method received resource with nil CR object")
 	}
+	// The tags of an observed agent are mirrored, not ensured, see
+	// observe.go.
+	if isObserveOnly(r) {
+		return nil
+	}
 	defaultTags := ackrt.GetDefaultTags(&rm.cfg, r.ko, md)
-	var existingTags map[string]*string
-	existingTags = r.ko.Spec.Tags
+	// The inherited, owner cluster and retained tags, see hooks.go.
+	existingTags, err := rm.controllerTags(ctx, r, md)
+	if err != nil {
+		return err
+	}
 	resourceTags, keyOrder := convertToOrderedACKTags(existingTags)
 	tags := acktags.Merge(resourceTags, defaultTags)
 	r.ko.Spec.Tags = fromACKTags(tags, keyOrder)
@@ -345,6 +353,8 @@ func (rm *resourceManager) FilterSystemTags(res acktypes.AWSResource, systemTags
 	existingTags = r.ko.Spec.Tags
 	resourceTags, tagKeyOrder := convertToOrderedACKTags(existingTags)
 	ignoreSystemTags(resourceTags, systemTags)
+	// The tags added by EnsureTags besides the --resource-tags, see hooks.go.
+	ignoreControllerTags(resourceTags, r)
 	r.ko.Spec.Tags = fromACKTags(resourceTags, tagKeyOrder)
 }
 
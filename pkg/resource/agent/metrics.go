@@ -0,0 +1,151 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"sync"
+	"time"
+
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlrtmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+// The Agent metrics are labelled by the namespace and name of the Agent and
+// served by the controller's metrics server, with the controller-runtime and
+// ACK runtime metrics.
+var (
+	agentStatusGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ack_bedrockagent_agent_status",
+			Help: "Status of the agent: 1 for the current AgentStatus, 0 for the others.",
+		},
+		[]string{"namespace", "name", "status"},
+	)
+	agentTimeToPreparedSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "ack_bedrockagent_agent_time_to_prepared_seconds",
+			Help:    "Time from a change of the Agent spec to the agent being PREPARED.",
+			Buckets: []float64{5, 10, 30, 60, 120, 300, 600, 1800},
+		},
+		[]string{"namespace", "name"},
+	)
+	agentPrepareAttemptsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ack_bedrockagent_agent_prepare_attempts_total",
+			Help: "Total number of PrepareAgent calls made for the agent.",
+		},
+		[]string{"namespace", "name"},
+	)
+	agentPrepareFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ack_bedrockagent_agent_prepare_failures_total",
+			Help: "Total number of PrepareAgent calls that failed, or after which the agent was FAILED.",
+		},
+		[]string{"namespace", "name"},
+	)
+)
+
+func init() {
+	ctrlrtmetrics.Registry.MustRegister(
+		agentStatusGauge,
+		agentTimeToPreparedSeconds,
+		agentPrepareAttemptsTotal,
+		agentPrepareFailuresTotal,
+	)
+}
+
+// pendingPrepares records, for each Agent, when its spec changed, until the
+// agent is PREPARED or FAILED.
+var pendingPrepares = struct {
+	sync.Mutex
+	since map[types.NamespacedName]time.Time
+}{
+	since: map[types.NamespacedName]time.Time{},
+}
+
+// metricsNow returns the current time. It is replaced in tests.
+var metricsNow = time.Now
+
+func agentKey(ko *svcapitypes.Agent) types.NamespacedName {
+	return types.NamespacedName{Namespace: ko.Namespace, Name: ko.Name}
+}
+
+// recordSpecChange starts measuring the time to PREPARED, unless an earlier
+// change is still being prepared.
+func recordSpecChange(ko *svcapitypes.Agent) {
+	pendingPrepares.Lock()
+	defer pendingPrepares.Unlock()
+	key := agentKey(ko)
+	if _, ok := pendingPrepares.since[key]; !ok {
+		pendingPrepares.since[key] = metricsNow()
+	}
+}
+
+// recordPrepareAttempt counts a PrepareAgent call and its failure.
+func recordPrepareAttempt(ko *svcapitypes.Agent, err error) {
+	agentPrepareAttemptsTotal.WithLabelValues(ko.Namespace, ko.Name).Inc()
+	if err != nil {
+		agentPrepareFailuresTotal.WithLabelValues(ko.Namespace, ko.Name).Inc()
+	}
+}
+
+// recordAgentStatus sets the status gauge of the agent. Once the agent is
+// PREPARED, the time since the pending spec change is observed. A FAILED
+// agent counts as a failed prepare.
+func recordAgentStatus(ko *svcapitypes.Agent) {
+	if ko.Status.AgentStatus == nil {
+		return
+	}
+	status := *ko.Status.AgentStatus
+	for _, value := range svcsdktypes.AgentStatus("").Values() {
+		current := 0.0
+		if string(value) == status {
+			current = 1
+		}
+		agentStatusGauge.WithLabelValues(ko.Namespace, ko.Name, string(value)).Set(current)
+	}
+
+	pendingPrepares.Lock()
+	defer pendingPrepares.Unlock()
+	key := agentKey(ko)
+	since, ok := pendingPrepares.since[key]
+	if !ok {
+		return
+	}
+	switch svcsdktypes.AgentStatus(status) {
+	case svcsdktypes.AgentStatusPrepared:
+		agentTimeToPreparedSeconds.WithLabelValues(ko.Namespace, ko.Name).Observe(metricsNow().Sub(since).Seconds())
+		delete(pendingPrepares.since, key)
+	case svcsdktypes.AgentStatusFailed:
+		agentPrepareFailuresTotal.WithLabelValues(ko.Namespace, ko.Name).Inc()
+		delete(pendingPrepares.since, key)
+	}
+}
+
+// forgetAgentMetrics removes the series of a deleted agent.
+func forgetAgentMetrics(ko *svcapitypes.Agent) {
+	labels := prometheus.Labels{"namespace": ko.Namespace, "name": ko.Name}
+	agentStatusGauge.DeletePartialMatch(labels)
+	agentTimeToPreparedSeconds.DeletePartialMatch(labels)
+	agentPrepareAttemptsTotal.DeletePartialMatch(labels)
+	agentPrepareFailuresTotal.DeletePartialMatch(labels)
+
+	pendingPrepares.Lock()
+	defer pendingPrepares.Unlock()
+	delete(pendingPrepares.since, agentKey(ko))
+}
@@ -0,0 +1,173 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+// withMetricsClock replaces the clock of the Agent metrics until the end of
+// the test, and returns a function advancing it.
+func withMetricsClock(t *testing.T) func(time.Duration) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	metricsNow = func() time.Time { return now }
+	t.Cleanup(func() { metricsNow = time.Now })
+	return func(d time.Duration) { now = now.Add(d) }
+}
+
+// agentSeries returns the series of the collector labelled with the agent.
+func agentSeries(t *testing.T, c prometheus.Collector, ko *svcapitypes.Agent) []*dto.Metric {
+	t.Helper()
+	ch := make(chan prometheus.Metric)
+	go func() {
+		c.Collect(ch)
+		close(ch)
+	}()
+	var series []*dto.Metric
+	for m := range ch {
+		var pb dto.Metric
+		if err := m.Write(&pb); err != nil {
+			t.Fatal(err)
+		}
+		labels := map[string]string{}
+		for _, l := range pb.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		if labels["namespace"] == ko.Namespace && labels["name"] == ko.Name {
+			series = append(series, &pb)
+		}
+	}
+	return series
+}
+
+func metricsAgent(name string, status string) *svcapitypes.Agent {
+	return &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "metrics", Name: name},
+		Status:     svcapitypes.AgentStatus{AgentStatus: aws.String(status)},
+	}
+}
+
+func TestRecordAgentStatus(t *testing.T) {
+	ko := metricsAgent("status", "NOT_PREPARED")
+	t.Cleanup(func() { forgetAgentMetrics(ko) })
+
+	recordAgentStatus(ko)
+	ko.Status.AgentStatus = aws.String("PREPARING")
+	recordAgentStatus(ko)
+
+	for status, want := range map[string]float64{
+		"NOT_PREPARED": 0,
+		"PREPARING":    1,
+		"PREPARED":     0,
+		"FAILED":       0,
+	} {
+		if got := testutil.ToFloat64(agentStatusGauge.WithLabelValues("metrics", "status", status)); got != want {
+			t.Errorf("agent status %s = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestRecordTimeToPrepared(t *testing.T) {
+	advance := withMetricsClock(t)
+	ko := metricsAgent("prepared", "CREATING")
+	t.Cleanup(func() { forgetAgentMetrics(ko) })
+
+	recordSpecChange(ko)
+	advance(20 * time.Second)
+	// A later change while the first one is prepared doesn't restart the
+	// measure.
+	recordSpecChange(ko)
+	ko.Status.AgentStatus = aws.String("PREPARING")
+	recordAgentStatus(ko)
+	advance(25 * time.Second)
+	ko.Status.AgentStatus = aws.String("PREPARED")
+	recordAgentStatus(ko)
+	// Reading the PREPARED agent again isn't a new observation.
+	recordAgentStatus(ko)
+
+	series := agentSeries(t, agentTimeToPreparedSeconds, ko)
+	if len(series) != 1 {
+		t.Fatalf("time to prepared series = %d, want 1", len(series))
+	}
+	histogram := series[0].GetHistogram()
+	if got := histogram.GetSampleCount(); got != 1 {
+		t.Errorf("time to prepared observations = %d, want 1", got)
+	}
+	if got := histogram.GetSampleSum(); got != 45 {
+		t.Errorf("time to prepared = %vs, want 45s", got)
+	}
+}
+
+func TestRecordPrepareFailures(t *testing.T) {
+	ko := metricsAgent("failures", "NOT_PREPARED")
+	t.Cleanup(func() { forgetAgentMetrics(ko) })
+
+	recordSpecChange(ko)
+	recordPrepareAttempt(ko, errors.New("ConflictException"))
+	recordPrepareAttempt(ko, nil)
+	ko.Status.AgentStatus = aws.String("FAILED")
+	recordAgentStatus(ko)
+	// The agent stays FAILED until the next change.
+	recordAgentStatus(ko)
+
+	if got := testutil.ToFloat64(agentPrepareAttemptsTotal.WithLabelValues("metrics", "failures")); got != 2 {
+		t.Errorf("prepare attempts = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(agentPrepareFailuresTotal.WithLabelValues("metrics", "failures")); got != 2 {
+		t.Errorf("prepare failures = %v, want 2", got)
+	}
+}
+
+func TestForgetAgentMetrics(t *testing.T) {
+	ko := metricsAgent("deleted", "PREPARED")
+	other := metricsAgent("kept", "PREPARED")
+	t.Cleanup(func() { forgetAgentMetrics(other) })
+
+	recordAgentStatus(ko)
+	recordAgentStatus(other)
+	recordPrepareAttempt(ko, nil)
+	recordSpecChange(ko)
+	forgetAgentMetrics(ko)
+
+	for _, tc := range []struct {
+		name      string
+		collector prometheus.Collector
+		ko        *svcapitypes.Agent
+		want      int
+	}{
+		{"status of the deleted agent", agentStatusGauge, ko, 0},
+		{"prepare attempts of the deleted agent", agentPrepareAttemptsTotal, ko, 0},
+		{"status of the other agent", agentStatusGauge, other, len(svcsdktypes.AgentStatus("").Values())},
+	} {
+		if got := len(agentSeries(t, tc.collector, tc.ko)); got != tc.want {
+			t.Errorf("%s: %d series, want %d", tc.name, got, tc.want)
+		}
+	}
+	pendingPrepares.Lock()
+	defer pendingPrepares.Unlock()
+	if _, ok := pendingPrepares.since[agentKey(ko)]; ok {
+		t.Error("the pending spec change of the deleted agent wasn't forgotten")
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/observe"
+)
+
+// ConditionTypeObserveOnly reports that the agent is managed with the
+// observe.PolicyObserve policy: it is mirrored from AWS, on each resync, but
+// never modified.
+const ConditionTypeObserveOnly ackv1alpha1.ConditionType = "ObserveOnly"
+
+// errObserveOnly is returned, with a requeue, instead of creating an observed
+// agent.
+var errObserveOnly = errors.New("observe only: the agent is adopted once it exists in AWS, but never created")
+
+type agentsClient interface {
+	ListAgents(context.Context, *svcsdk.ListAgentsInput, ...func(*svcsdk.Options)) (*svcsdk.ListAgentsOutput, error)
+}
+
+// findAgentID returns the ID of the agent with the name, or nil when there is
+// none.
+func findAgentID(
+	ctx context.Context,
+	client agentsClient,
+	metrics metricsRecorder,
+	name string,
+) (*string, error) {
+	input := &svcsdk.ListAgentsInput{}
+	for {
+		resp, err := client.ListAgents(ctx, input)
+		metrics.RecordAPICall("READ_MANY", "ListAgents", err)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range resp.AgentSummaries {
+			if aws.ToString(summary.AgentName) == name {
+				return summary.AgentId, nil
+			}
+		}
+		if resp.NextToken == nil {
+			return nil, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
+
+// isObserveOnly returns whether the agent is managed with the
+// observe.PolicyObserve policy.
+func isObserveOnly(r *resource) bool {
+	return observe.IsObserveOnly(r.ko)
+}
+
+// adoptObservedAgent returns a copy of the observed agent with the AgentID of
+// the agent it adopts: the one named by its observe.IDAnnotation or, when
+// unset, the one of the agent with its name. The agent itself is returned
+// when it has adopted its agent already, or when no agent has its name yet.
+func (rm *resourceManager) adoptObservedAgent(
+	ctx context.Context,
+	r *resource,
+) (*resource, error) {
+	id, ok := observe.ID(r.ko)
+	if !ok {
+		if r.ko.Status.AgentID != nil {
+			return r, nil
+		}
+		found, err := findAgentID(ctx, rm.tracedSDKAPI(), rm.metrics, aws.ToString(r.ko.Spec.AgentName))
+		if err != nil || found == nil {
+			return r, err
+		}
+		id = *found
+	}
+	if aws.ToString(r.ko.Status.AgentID) == id {
+		return r, nil
+	}
+	adopted := &resource{ko: r.ko.DeepCopy()}
+	adopted.ko.Status.AgentID = aws.String(id)
+	return adopted, nil
+}
+
+// setObserveOnlyStatus writes the ObserveOnly condition to the observed
+// agent.
+func setObserveOnlyStatus(observed *resource) {
+	setCondition(observed.ko, ConditionTypeObserveOnly, corev1.ConditionTrue, aws.String(fmt.Sprintf(
+		"Agent %s is mirrored from AWS but never modified, as set by the %s annotation",
+		aws.ToString(observed.ko.Status.AgentID), observe.PolicyAnnotation,
+	)))
+}
+
+// observedCreate returns the agent with the ObserveOnly condition instead of
+// creating it. The requeue looks the agent up again until it exists.
+func observedCreate(r *resource) (*resource, error) {
+	observed := &resource{ko: r.ko.DeepCopy()}
+	target := "named " + aws.ToString(r.ko.Spec.AgentName)
+	if id, ok := observe.ID(r.ko); ok {
+		target = id
+	}
+	setCondition(observed.ko, ConditionTypeObserveOnly, corev1.ConditionFalse, aws.String(fmt.Sprintf(
+		"No agent %s to observe in AWS; it is adopted once it exists", target,
+	)))
+	return observed, ackrequeue.NeededAfter(errObserveOnly, ackrequeue.DefaultRequeueAfterDuration)
+}
+
+// observedUpdate returns the latest agent, whose Spec is then saved to the
+// resource, instead of updating it.
+func observedUpdate(latest *resource) *resource {
+	return &resource{ko: latest.ko.DeepCopy()}
+}
+
+// observedDelete returns the agent instead of deleting it, so that only its
+// finalizer is removed and the agent is left to its owner.
+func observedDelete(r *resource) *resource {
+	forgetAgentMetrics(r.ko)
+	return r
+}
@@ -0,0 +1,125 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/observe"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func observedAgent(name string, annotations map[string]string) *resource {
+	annotations[observe.PolicyAnnotation] = observe.PolicyObserve
+	return &resource{ko: &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations},
+		Spec:       svcapitypes.AgentSpec{AgentName: aws.String(name)},
+	}}
+}
+
+func TestObserveOnly(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	// The agent is owned by another system.
+	owned := dryRunAgent()
+	owned.ko.Annotations = nil
+	res, err := rm.Create(ctx, owned)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	owned.ko.Status = res.(*resource).ko.Status
+	waitForAgent(t, rm, owned)
+	syncAgent(t, rm, owned)
+	agentID := aws.ToString(owned.ko.Status.AgentID)
+	server.ResetCalls()
+
+	missing := observedAgent("missing", map[string]string{})
+	if _, err := rm.ReadOne(ctx, missing); err == nil {
+		t.Errorf("ReadOne() of a missing agent error = nil, want NotFound")
+	}
+	res, err = rm.Create(ctx, missing)
+	var requeue *ackrequeue.RequeueNeededAfter
+	if !errors.As(err, &requeue) || !errors.Is(requeue.Unwrap(), errObserveOnly) {
+		t.Errorf("Create() error = %v, want an observe-only requeue", err)
+	}
+	if c := agentCondition(res.(*resource).ko, ConditionTypeObserveOnly); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("ObserveOnly condition = %v, want False for a missing agent", c)
+	}
+
+	// The default tags of the controller aren't added to observed agents.
+	rm.cfg.ResourceTags = []string{"team=ml"}
+	for name, desired := range map[string]*resource{
+		"by name": observedAgent("planned", map[string]string{}),
+		"by ID":   observedAgent("renamed", map[string]string{observe.IDAnnotation: agentID}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := rm.ReadOne(ctx, desired)
+			if err != nil {
+				t.Fatalf("ReadOne() error = %v", err)
+			}
+			latest := res.(*resource)
+			if got := aws.ToString(latest.ko.Status.AgentID); got != agentID {
+				t.Errorf("AgentID = %q, want the adopted %q", got, agentID)
+			}
+			if got := aws.ToString(latest.ko.Spec.Instruction); got != aws.ToString(owned.ko.Spec.Instruction) {
+				t.Errorf("instruction = %q, want it mirrored from AWS", got)
+			}
+			if c := agentCondition(latest.ko, ConditionTypeObserveOnly); c == nil || c.Status != corev1.ConditionTrue {
+				t.Errorf("ObserveOnly condition = %v, want True", c)
+			}
+
+			desired.ko.Status = latest.ko.Status
+			tags := desired.ko.Spec.Tags
+			if err := rm.EnsureTags(ctx, desired, acktypes.ServiceControllerMetadata{}); err != nil {
+				t.Fatalf("EnsureTags() error = %v", err)
+			}
+			if !reflect.DeepEqual(desired.ko.Spec.Tags, tags) {
+				t.Errorf("tags = %v, want the tags of an observed agent left at %v", desired.ko.Spec.Tags, tags)
+			}
+			res, err = rm.Update(ctx, desired, latest, newResourceDelta(desired, latest))
+			if err != nil {
+				t.Fatalf("Update() error = %v", err)
+			}
+			if got := res.(*resource).ko.Spec; got.FoundationModel == nil || aws.ToString(got.AgentName) != "planned" {
+				t.Errorf("updated spec = %+v, want the spec mirrored from AWS", got)
+			}
+			if _, err := rm.Delete(ctx, desired); err != nil {
+				t.Errorf("Delete() error = %v", err)
+			}
+		})
+	}
+
+	for _, call := range server.Calls() {
+		switch call {
+		case "GetAgent", "ListAgents", "ListTagsForResource", "ListAgentActionGroups", "ListAgentKnowledgeBases":
+		default:
+			t.Errorf("%s called for an observed agent", call)
+		}
+	}
+	if _, ok := server.Agent(agentID); !ok {
+		t.Errorf("agent %s deleted, want it left to its owner", agentID)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"fmt"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+)
+
+// ConditionTypeOwnedByOtherCluster reports that the agent isn't updated
+// because its tags.OwnerClusterTagKey tag names another cluster than the one
+// of the controller.
+const ConditionTypeOwnedByOtherCluster ackv1alpha1.ConditionType = "OwnedByOtherCluster"
+
+// setOwnerStatus records in the status of the observed agent the cluster
+// owning it.
+func setOwnerStatus(observed *resource) {
+	observed.ko.Status.OwnerCluster = nil
+	if owner := tags.OwnerCluster(observed.ko.Spec.Tags); owner != "" {
+		observed.ko.Status.OwnerCluster = aws.String(owner)
+	}
+}
+
+// mirrorOwnerTag copies the owner tag of the observed agent to the desired
+// one when the controller has no cluster ID, so that the controller doesn't
+// remove the tag written by the controller of another cluster.
+func mirrorOwnerTag(desired *resource, observed *resource) {
+	if tags.ClusterID() != "" || desired == nil || observed == nil {
+		return
+	}
+	owner, ok := observed.ko.Spec.Tags[tags.OwnerClusterTagKey]
+	if !ok {
+		return
+	}
+	if desired.ko.Spec.Tags == nil {
+		desired.ko.Spec.Tags = map[string]*string{}
+	}
+	desired.ko.Spec.Tags[tags.OwnerClusterTagKey] = owner
+}
+
+// ownedByOtherCluster returns whether the latest agent is owned by another
+// cluster and isn't taken over with the tags.TakeoverAnnotation of the
+// desired agent. A takeover is carried out by the update of the tags, which
+// EnsureTags gave the owner tag of the cluster.
+func ownedByOtherCluster(desired *resource, latest *resource) bool {
+	owner := aws.ToString(latest.ko.Status.OwnerCluster)
+	if !tags.IsOwnedElsewhere(owner) {
+		return false
+	}
+	if tags.IsTakeover(desired.ko) {
+		recordTakeoverEvent(desired.ko, owner)
+		return false
+	}
+	return true
+}
+
+// ownershipConflict returns the desired agent, with the status of the latest
+// one and the OwnedByOtherCluster condition, instead of updating an agent
+// owned by another cluster.
+func ownershipConflict(desired *resource, latest *resource) (*resource, bool) {
+	if !ownedByOtherCluster(desired, latest) {
+		return nil, false
+	}
+	conflict := &resource{ko: desired.ko.DeepCopy()}
+	latest.ko.Status.DeepCopyInto(&conflict.ko.Status)
+	message := fmt.Sprintf(
+		"Agent is owned by cluster %s, not %s. Set the %s: \"true\" annotation to take it over",
+		aws.ToString(latest.ko.Status.OwnerCluster), tags.ClusterID(), tags.TakeoverAnnotation,
+	)
+	setCondition(conflict.ko, ConditionTypeOwnedByOtherCluster, corev1.ConditionTrue, aws.String(message))
+	// The agent isn't synced, so that it is requeued and the takeover
+	// annotation, which doesn't change the generation, is noticed.
+	ackcondition.SetSynced(conflict, corev1.ConditionFalse, aws.String("Agent is owned by another cluster"), nil)
+	recordOwnershipEvent(conflict.ko, "updated")
+	return conflict, true
+}
+
+// foreignDelete returns whether the deletion of the agent leaves it to the
+// cluster owning it: the finalizer is removed, but the agent isn't deleted.
+func foreignDelete(r *resource) bool {
+	if !ownedByOtherCluster(r, r) {
+		return false
+	}
+	recordOwnershipEvent(r.ko, "deleted")
+	forgetAgentMetrics(r.ko)
+	return true
+}
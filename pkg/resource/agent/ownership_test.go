@@ -0,0 +1,102 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func TestClusterOwnership(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+	if err := tags.SetClusterID("green"); err != nil {
+		t.Fatalf("SetClusterID() error = %v", err)
+	}
+	t.Cleanup(func() { _ = tags.SetClusterID("") })
+
+	desired := dryRunAgent()
+	desired.ko.Annotations = nil
+	if err := rm.EnsureTags(ctx, desired, acktypes.ServiceControllerMetadata{}); err != nil {
+		t.Fatalf("EnsureTags() error = %v", err)
+	}
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	desired.ko.Status = res.(*resource).ko.Status
+	waitForAgent(t, rm, desired)
+	syncAgent(t, rm, desired)
+	latest := waitForAgent(t, rm, desired)
+	if got := aws.ToString(latest.ko.Status.OwnerCluster); got != "green" {
+		t.Errorf("Status.OwnerCluster = %q, want green", got)
+	}
+	rm.FilterSystemTags(latest, nil)
+	if _, ok := latest.ko.Spec.Tags[tags.OwnerClusterTagKey]; ok {
+		t.Errorf("FilterSystemTags() kept the owner tag in the spec")
+	}
+
+	// The controller of the blue cluster takes the agent over.
+	arn := string(*desired.ko.Status.ACKResourceMetadata.ARN)
+	_, err = svcsdk.NewFromConfig(server.Config()).TagResource(ctx, &svcsdk.TagResourceInput{
+		ResourceArn: aws.String(arn),
+		Tags:        map[string]string{tags.OwnerClusterTagKey: "blue"},
+	})
+	if err != nil {
+		t.Fatalf("TagResource() error = %v", err)
+	}
+	server.ResetCalls()
+	res, err = rm.ReadOne(ctx, desired)
+	if err != nil {
+		t.Fatalf("ReadOne() error = %v", err)
+	}
+	latest = res.(*resource)
+	if got := aws.ToString(latest.ko.Status.OwnerCluster); got != "blue" {
+		t.Errorf("Status.OwnerCluster = %q, want blue", got)
+	}
+	delta := newResourceDelta(desired, latest)
+	res, err = rm.Update(ctx, desired, latest, delta)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if c := agentCondition(res.(*resource).ko, ConditionTypeOwnedByOtherCluster); c == nil || c.Status != corev1.ConditionTrue {
+		t.Errorf("OwnedByOtherCluster condition = %v, want True", c)
+	}
+	if _, err := rm.Delete(ctx, latest); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	for _, call := range server.Calls() {
+		if slices.Contains([]string{"UpdateAgent", "TagResource", "UntagResource", "PrepareAgent", "DeleteAgent"}, call) {
+			t.Errorf("agent owned by another cluster got a %s call", call)
+		}
+	}
+
+	desired.ko.Annotations = map[string]string{tags.TakeoverAnnotation: "true"}
+	if _, err := rm.Update(ctx, desired, latest, delta); err != nil {
+		t.Fatalf("Update() with the takeover annotation error = %v", err)
+	}
+	if got := server.Tags(arn)[tags.OwnerClusterTagKey]; got != "green" {
+		t.Errorf("owner tag after the takeover = %q, want green", got)
+	}
+}
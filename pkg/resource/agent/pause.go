@@ -0,0 +1,120 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/pause"
+)
+
+// ConditionTypePaused reports that the reconciliation of the agent is paused
+// by the pause.Annotation, with the drift of the agent from its spec. Once
+// the reconciliation resumes, it reports the drift accumulated in the pause.
+const ConditionTypePaused ackv1alpha1.ConditionType = "Paused"
+
+// errPaused is returned, with a requeue, instead of creating or deleting a
+// paused agent.
+var errPaused = errors.New("paused: the agent isn't modified until the " + pause.Annotation + " annotation is removed")
+
+// setPauseStatus writes to observed the Paused condition of a paused agent,
+// and the time of the pause. When the reconciliation resumes, it reports the
+// drift accumulated in the pause on both desired and observed, so that the
+// report outlives the update of the agent.
+func setPauseStatus(desired *resource, observed *resource) {
+	drift := driftPaths(desired, observed)
+	if pause.IsPaused(desired.ko) {
+		if observed.ko.Status.PausedAt == nil {
+			now := metav1.Now()
+			observed.ko.Status.PausedAt = &now
+			recordPauseEvent(observed.ko)
+		}
+		message := fmt.Sprintf(
+			"Reconciliation paused since %s; remove the %s annotation to resume it. %s",
+			observed.ko.Status.PausedAt.Format(time.RFC3339), pause.Annotation, driftSummary(drift),
+		)
+		setCondition(observed.ko, ConditionTypePaused, corev1.ConditionTrue, aws.String(message))
+		// The agent isn't synced, so that it is requeued and the removal of
+		// the annotation, which doesn't change the generation, is noticed.
+		ackcondition.SetSynced(observed, corev1.ConditionFalse, aws.String("Reconciliation is paused"), nil)
+		return
+	}
+	pausedAt := observed.ko.Status.PausedAt
+	if pausedAt == nil {
+		return
+	}
+	message := fmt.Sprintf(
+		"Reconciliation resumed after a pause since %s. %s",
+		pausedAt.Format(time.RFC3339), driftSummary(drift),
+	)
+	for _, ko := range []*svcapitypes.Agent{desired.ko, observed.ko} {
+		ko.Status.PausedAt = nil
+		setCondition(ko, ConditionTypePaused, corev1.ConditionFalse, aws.String(message))
+	}
+	recordResumeEvent(observed.ko, message, len(drift) > 0)
+}
+
+// driftPaths returns the paths of the fields of the observed agent differing
+// from the desired spec.
+func driftPaths(desired *resource, observed *resource) []string {
+	var paths []string
+	for _, difference := range newResourceDelta(desired, observed).Differences {
+		paths = append(paths, differencePath(difference))
+	}
+	return paths
+}
+
+func driftSummary(drift []string) string {
+	if len(drift) == 0 {
+		return "The agent doesn't drift from its spec"
+	}
+	return "The agent drifts from its spec at " + strings.Join(drift, ", ")
+}
+
+// isPaused returns whether the reconciliation of the agent is paused with
+// the pause.Annotation.
+func isPaused(r *resource) bool {
+	return pause.IsPaused(r.ko)
+}
+
+// pausedUpdate returns the desired agent with the status of the latest one,
+// which holds the Paused condition written by ReadOne, instead of updating
+// it.
+func pausedUpdate(desired *resource, latest *resource) *resource {
+	paused := &resource{ko: desired.ko.DeepCopy()}
+	latest.ko.Status.DeepCopyInto(&paused.ko.Status)
+	return paused
+}
+
+// pausedWrite returns the agent with the Paused condition instead of
+// creating or deleting it. The requeue retries the call, and keeps the
+// finalizer of a deleted agent, until the reconciliation resumes.
+func pausedWrite(r *resource, action string) (*resource, error) {
+	paused := &resource{ko: r.ko.DeepCopy()}
+	setCondition(paused.ko, ConditionTypePaused, corev1.ConditionTrue, aws.String(fmt.Sprintf(
+		"Reconciliation paused; the agent is %s once the %s annotation is removed", action, pause.Annotation,
+	)))
+	return paused, ackrequeue.NeededAfter(errPaused, ackrequeue.DefaultRequeueAfterDuration)
+}
@@ -0,0 +1,119 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/pause"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func TestPause(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	desired := dryRunAgent()
+	desired.ko.Annotations = map[string]string{pause.Annotation: "true"}
+	_, err := rm.Create(ctx, desired)
+	var requeue *ackrequeue.RequeueNeededAfter
+	if !errors.As(err, &requeue) || !errors.Is(requeue.Unwrap(), errPaused) {
+		t.Fatalf("Create() error = %v, want a pause requeue", err)
+	}
+	if calls := server.Calls(); len(calls) != 0 {
+		t.Fatalf("calls = %v, want none while paused", calls)
+	}
+
+	desired.ko.Annotations = nil
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	desired.ko.Status = res.(*resource).ko.Status
+	waitForAgent(t, rm, desired)
+	syncAgent(t, rm, desired)
+	desired.ko.Status = waitForAgent(t, rm, desired).ko.Status
+
+	// The agent is paused, then edited by hand in AWS and in its spec. The
+	// immutable AgentName differing from AWS doesn't fail the update either.
+	desired.ko.Annotations = map[string]string{pause.Annotation: "true"}
+	agentID := aws.ToString(desired.ko.Status.AgentID)
+	server.SetAgentStatus(agentID, fakebedrockagent.StatusNotPrepared)
+	desired.ko.Spec.Instruction = aws.String(strings.Repeat("You are a concise support agent. ", 5))
+	agentName := desired.ko.Spec.AgentName
+	desired.ko.Spec.AgentName = aws.String("renamed-in-console")
+	server.ResetCalls()
+
+	latest, _ := syncAgent(t, rm, desired)
+	c := agentCondition(latest.ko, ConditionTypePaused)
+	if c == nil || c.Status != corev1.ConditionTrue {
+		t.Fatalf("Paused condition = %v, want True", c)
+	}
+	if message := aws.ToString(c.Message); !strings.Contains(message, "Spec.Instruction") ||
+		!strings.Contains(message, "Spec.AgentStatus") || !strings.Contains(message, "Spec.AgentName") {
+		t.Errorf("Paused message = %q, want the drift of the agent", message)
+	}
+	if latest.ko.Status.PausedAt == nil {
+		t.Errorf("PausedAt = nil, want the time of the pause")
+	}
+	if c := ackcondition.Synced(latest); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("Synced condition = %v, want False while paused", c)
+	}
+	desired.ko.Status = latest.ko.Status
+	if _, err := rm.Delete(ctx, desired); !errors.As(err, &requeue) {
+		t.Errorf("Delete() error = %v, want a pause requeue", err)
+	}
+	for _, call := range server.Calls() {
+		switch call {
+		case "GetAgent", "ListTagsForResource", "ListAgentActionGroups", "ListAgentKnowledgeBases":
+		default:
+			t.Errorf("%s called while paused", call)
+		}
+	}
+
+	// Once resumed, the drift is reported and the spec applied.
+	desired.ko.Annotations = nil
+	desired.ko.Spec.AgentName = agentName
+	server.ResetCalls()
+	res, err = rm.ReadOne(ctx, desired)
+	if err != nil {
+		t.Fatalf("ReadOne() error = %v", err)
+	}
+	latest = res.(*resource)
+	res, err = rm.Update(ctx, desired, latest, newResourceDelta(desired, latest))
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	resumed := res.(*resource)
+	c = agentCondition(resumed.ko, ConditionTypePaused)
+	if c == nil || c.Status != corev1.ConditionFalse || !strings.Contains(aws.ToString(c.Message), "Spec.Instruction") {
+		t.Errorf("Paused condition = %v, want False with the drift accumulated in the pause", c)
+	}
+	if resumed.ko.Status.PausedAt != nil {
+		t.Errorf("PausedAt = %v, want nil once resumed", resumed.ko.Status.PausedAt)
+	}
+	agent, _ := server.Agent(agentID)
+	if got := agent["instruction"]; got != aws.ToString(desired.ko.Spec.Instruction) {
+		t.Errorf("instruction in AWS = %q, want the spec applied once resumed", got)
+	}
+}
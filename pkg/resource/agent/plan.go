@@ -0,0 +1,231 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/plan"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+)
+
+// ConditionTypePlanReady reports that the changes the controller would make
+// to an agent in dry-run mode are written to Status.Plan.
+const ConditionTypePlanReady ackv1alpha1.ConditionType = "PlanReady"
+
+// errDryRun is returned, with a requeue, instead of creating or deleting an
+// agent in dry-run mode.
+var errDryRun = errors.New("dry run: the changes are planned but not applied, see status.plan")
+
+// setDryRunPlan writes to observed the plan bringing it to desired when the
+// agent is in dry-run mode, and removes the plan otherwise.
+func setDryRunPlan(desired *resource, observed *resource) {
+	if !plan.IsDryRun(desired.ko) {
+		observed.ko.Status.Plan = nil
+		return
+	}
+	setAgentPlan(observed.ko, newUpdatePlan(desired, observed, newResourceDelta(desired, observed)))
+}
+
+// isDryRun returns whether the agent, or the controller, is in dry-run mode.
+func isDryRun(r *resource) bool {
+	return plan.IsDryRun(r.ko)
+}
+
+// planCreate returns the desired agent with the plan of its creation, instead
+// of creating it.
+func planCreate(desired *resource) (*resource, error) {
+	planned := &resource{ko: desired.ko.DeepCopy()}
+	setAgentPlan(planned.ko, newCreatePlan(desired))
+	return planned, ackrequeue.NeededAfter(errDryRun, ackrequeue.DefaultRequeueAfterDuration)
+}
+
+// planUpdate returns the desired agent with the status of the latest one,
+// which holds the plan written by ReadOne, instead of updating it. The
+// desired Spec is returned so that the latest one isn't saved to the resource.
+func planUpdate(desired *resource, latest *resource) (*resource, error) {
+	planned := &resource{ko: desired.ko.DeepCopy()}
+	latest.ko.Status.DeepCopyInto(&planned.ko.Status)
+	return planned, nil
+}
+
+// planDelete returns the agent with the plan of its deletion, instead of
+// deleting it. The requeue keeps the finalizer, and the agent, until the
+// dry-run mode is lifted.
+func planDelete(r *resource) (*resource, error) {
+	planned := &resource{ko: r.ko.DeepCopy()}
+	setAgentPlan(planned.ko, &plan.Plan{
+		Action:   "Delete",
+		Resource: agentPlanResource(r),
+		Calls:    []string{"DeleteAgent"},
+	})
+	return planned, ackrequeue.NeededAfter(errDryRun, ackrequeue.DefaultRequeueAfterDuration)
+}
+
+// setAgentPlan writes the plan to the agent status and the PlanReady
+// condition. An agent with pending changes isn't synced.
+func setAgentPlan(ko *svcapitypes.Agent, p *plan.Plan) {
+	ko.Status.Plan = aws.String(p.String())
+	setCondition(ko, ConditionTypePlanReady, corev1.ConditionTrue, aws.String(p.Summary()))
+	if !p.Empty() {
+		ackcondition.SetSynced(&resource{ko}, corev1.ConditionFalse, aws.String(p.Summary()), aws.String(errDryRun.Error()))
+	}
+}
+
+func agentPlanResource(r *resource) string {
+	if r.ko.Status.AgentID == nil {
+		return "agent " + aws.ToString(r.ko.Spec.AgentName)
+	}
+	return "agent " + *r.ko.Status.AgentID
+}
+
+// newCreatePlan returns the plan creating the desired agent: every field it
+// sets is a change.
+func newCreatePlan(desired *resource) *plan.Plan {
+	empty := &resource{ko: &svcapitypes.Agent{}}
+	p := &plan.Plan{Action: "Create", Resource: agentPlanResource(desired)}
+	for _, difference := range newResourceDelta(desired, empty).Differences {
+		p.Changes = append(p.Changes, plan.Change{Path: differencePath(difference), New: difference.A})
+	}
+	p.AddCall("CreateAgent")
+	planActionGroupCalls(p, desired.ko.Spec.ActionGroups, nil)
+	planKnowledgeBaseCalls(p, desired.ko.Spec.KnowledgeBases, nil)
+	p.AddCall("PrepareAgent")
+	return p
+}
+
+// newUpdatePlan returns the plan bringing the latest agent to the desired
+// one, following the calls sdkUpdate makes for each difference.
+func newUpdatePlan(desired *resource, latest *resource, delta *ackcompare.Delta) *plan.Plan {
+	p := &plan.Plan{Action: "Update", Resource: agentPlanResource(latest)}
+	prepare := delta.DifferentAt("Spec.AgentStatus")
+	for _, difference := range delta.Differences {
+		path := differencePath(difference)
+		if path == "Spec.AgentStatus" {
+			continue
+		}
+		p.Changes = append(p.Changes, plan.Change{Path: path, Old: difference.B, New: difference.A})
+		if path != "Spec.Tags" {
+			prepare = true
+		}
+	}
+	if delta.DifferentExcept("Spec.AgentStatus", "Spec.Tags", "Spec.ActionGroups", "Spec.KnowledgeBases") {
+		p.AddCall("UpdateAgent")
+	}
+	if delta.DifferentAt("Spec.ActionGroups") {
+		planActionGroupCalls(p, desired.ko.Spec.ActionGroups, latest.ko.Spec.ActionGroups)
+	}
+	if delta.DifferentAt("Spec.KnowledgeBases") {
+		planKnowledgeBaseCalls(p, desired.ko.Spec.KnowledgeBases, latest.ko.Spec.KnowledgeBases)
+	}
+	if prepare {
+		p.AddCall("PrepareAgent")
+	}
+	if delta.DifferentAt("Spec.Tags") {
+		addedOrUpdated, removed := tags.ComputeTagsDelta(desired.ko.Spec.Tags, latest.ko.Spec.Tags)
+		if len(addedOrUpdated) > 0 {
+			p.AddCall("TagResource")
+		}
+		if len(removed) > 0 {
+			p.AddCall("UntagResource")
+		}
+	}
+	return p
+}
+
+// planActionGroupCalls adds the calls syncActionGroups makes. Action groups
+// are matched by name.
+func planActionGroupCalls(p *plan.Plan, desired, latest []*svcapitypes.InlineActionGroup) {
+	latestByName := map[string]*svcapitypes.InlineActionGroup{}
+	for _, actionGroup := range latest {
+		if actionGroup != nil {
+			latestByName[aws.ToString(actionGroup.ActionGroupName)] = actionGroup
+		}
+	}
+	desiredNames := map[string]bool{}
+	for _, actionGroup := range desired {
+		if actionGroup == nil {
+			continue
+		}
+		name := aws.ToString(actionGroup.ActionGroupName)
+		desiredNames[name] = true
+		latestActionGroup, ok := latestByName[name]
+		switch {
+		case !ok:
+			p.AddCall("CreateAgentActionGroup")
+		case !equalActionGroups(actionGroup, latestActionGroup):
+			p.AddCall("UpdateAgentActionGroup")
+		}
+	}
+	for name := range latestByName {
+		if !desiredNames[name] {
+			p.AddCall("DeleteAgentActionGroup")
+		}
+	}
+}
+
+// planKnowledgeBaseCalls adds the calls syncKnowledgeBases makes. Knowledge
+// bases are matched by ID.
+func planKnowledgeBaseCalls(p *plan.Plan, desired, latest []*svcapitypes.InlineKnowledgeBase) {
+	latestByID := map[string]*svcapitypes.InlineKnowledgeBase{}
+	for _, knowledgeBase := range latest {
+		if knowledgeBase != nil {
+			latestByID[aws.ToString(knowledgeBase.KnowledgeBaseID)] = knowledgeBase
+		}
+	}
+	desiredIDs := map[string]bool{}
+	for _, knowledgeBase := range desired {
+		if knowledgeBase == nil {
+			continue
+		}
+		id := aws.ToString(knowledgeBase.KnowledgeBaseID)
+		desiredIDs[id] = true
+		latestKnowledgeBase, ok := latestByID[id]
+		switch {
+		case !ok:
+			p.AddCall("AssociateAgentKnowledgeBase")
+		case !equalKnowledgeBases(knowledgeBase, latestKnowledgeBase):
+			p.AddCall("UpdateAgentKnowledgeBase")
+		}
+	}
+	for id := range latestByID {
+		if !desiredIDs[id] {
+			p.AddCall("DisassociateAgentKnowledgeBase")
+		}
+	}
+}
+
+// differencePath returns the dotted path of a difference, e.g.
+// Spec.Instruction.
+func differencePath(difference *ackcompare.Difference) string {
+	data, err := json.Marshal(difference.Path)
+	if err != nil {
+		return ""
+	}
+	var path struct{ Parts []string }
+	if err := json.Unmarshal(data, &path); err != nil {
+		return ""
+	}
+	return strings.Join(path.Parts, ".")
+}
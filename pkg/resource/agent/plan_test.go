@@ -0,0 +1,162 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcondition "github.com/aws-controllers-k8s/runtime/pkg/condition"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/plan"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func dryRunAgent() *resource {
+	return &resource{ko: &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "planned",
+			Namespace:   "default",
+			Annotations: map[string]string{plan.DryRunAnnotation: "true"},
+		},
+		Spec: svcapitypes.AgentSpec{
+			AgentName:               aws.String("planned"),
+			AgentResourceRoleARN:    aws.String("arn:aws:iam::123456789012:role/agent-role"),
+			FoundationModel:         aws.String("anthropic.claude-3-haiku-20240307-v1:0"),
+			Instruction:             aws.String(strings.Repeat("You are a helpful support agent. ", 5)),
+			IdleSessionTTLInSeconds: aws.Int64(600),
+			AgentCollaboration:      aws.String("DISABLED"),
+			OrchestrationType:       aws.String("DEFAULT"),
+			Tags:                    map[string]*string{"team": aws.String("support")},
+		},
+	}}
+}
+
+func agentCondition(ko *svcapitypes.Agent, conditionType ackv1alpha1.ConditionType) *ackv1alpha1.Condition {
+	for _, c := range ko.Status.Conditions {
+		if c.Type == conditionType {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestDryRun_Create(t *testing.T) {
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+	desired := dryRunAgent()
+
+	res, err := rm.Create(context.Background(), desired)
+	var requeue *ackrequeue.RequeueNeededAfter
+	if !errors.As(err, &requeue) || !errors.Is(requeue.Unwrap(), errDryRun) {
+		t.Fatalf("Create() error = %v, want a dry-run requeue", err)
+	}
+	if calls := server.Calls(); len(calls) != 0 {
+		t.Errorf("calls = %v, want none in dry-run mode", calls)
+	}
+	planned := res.(*resource).ko
+	got := aws.ToString(planned.Status.Plan)
+	for _, want := range []string{
+		"Create agent planned\n",
+		`  Spec.Instruction: <unset> -> "You are a helpful support agent. You are…" (165 chars)` + "\n",
+		"  Spec.IdleSessionTTLInSeconds: <unset> -> 600\n",
+		"API calls: CreateAgent, PrepareAgent\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("plan doesn't contain %q:\n%s", want, got)
+		}
+	}
+	if c := agentCondition(planned, ConditionTypePlanReady); c == nil || c.Status != corev1.ConditionTrue {
+		t.Errorf("PlanReady condition = %v, want True", c)
+	}
+}
+
+func TestDryRun_UpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	// The agent is created, then put in dry-run mode.
+	desired := dryRunAgent()
+	delete(desired.ko.Annotations, plan.DryRunAnnotation)
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	desired.ko.Status = res.(*resource).ko.Status
+	latest := waitForAgent(t, rm, desired)
+	syncAgent(t, rm, desired)
+	latest = waitForAgent(t, rm, desired)
+	if latest.ko.Status.Plan != nil {
+		t.Errorf("plan = %q outside of the dry-run mode", *latest.ko.Status.Plan)
+	}
+
+	desired.ko.Annotations = map[string]string{plan.DryRunAnnotation: "true"}
+	desired.ko.Spec.IdleSessionTTLInSeconds = aws.Int64(900)
+	desired.ko.Spec.Tags = map[string]*string{"owner": aws.String("ml")}
+	server.ResetCalls()
+
+	res, err = rm.ReadOne(ctx, desired)
+	if err != nil {
+		t.Fatalf("ReadOne() error = %v", err)
+	}
+	latest = res.(*resource)
+	wantPlan := `Update agent ` + aws.ToString(desired.ko.Status.AgentID) + `
+  Spec.IdleSessionTTLInSeconds: 600 -> 900
+  Spec.Tags: {"team":"support"} -> {"owner":"ml"}
+API calls: UpdateAgent, PrepareAgent, TagResource, UntagResource
+`
+	if got := aws.ToString(latest.ko.Status.Plan); got != wantPlan {
+		t.Errorf("plan = %q, want %q", got, wantPlan)
+	}
+	if c := ackcondition.Synced(latest); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("Synced condition = %v, want False while changes are pending", c)
+	}
+
+	res, err = rm.Update(ctx, desired, latest, newResourceDelta(desired, latest))
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	updated := res.(*resource)
+	if !reflect.DeepEqual(updated.ko.Spec, desired.ko.Spec) {
+		t.Error("Update() didn't return the desired Spec, the latest one would be saved to the resource")
+	}
+	if got := aws.ToString(updated.ko.Status.Plan); got != wantPlan {
+		t.Errorf("plan after Update() = %q, want %q", got, wantPlan)
+	}
+
+	res, err = rm.Delete(ctx, latest)
+	if !errors.Is(err, errDryRun) {
+		t.Fatalf("Delete() error = %v, want a dry-run requeue", err)
+	}
+	if got, want := aws.ToString(res.(*resource).ko.Status.Plan), "API calls: DeleteAgent\n"; !strings.HasSuffix(got, want) {
+		t.Errorf("plan after Delete() = %q, want DeleteAgent", got)
+	}
+	for _, call := range server.Calls() {
+		switch call {
+		case "GetAgent", "ListTagsForResource":
+		default:
+			t.Errorf("%s called in dry-run mode", call)
+		}
+	}
+}
@@ -61,6 +61,11 @@ func (rm *resourceManager) ResolveReferences(
 	apiReader client.Reader,
 	res acktypes.AWSResource,
 ) (acktypes.AWSResource, bool, error) {
+	// The AgentClass, span and Events of the resolution wrap the generated
+	// code below, see resolve.go.
+	if !isResolvingReferences(ctx) {
+		return rm.resolveAgentReferences(ctx, apiReader, res)
+	}
 	ko := rm.concreteResource(res).ko
 
 	resourceHasReferences := false
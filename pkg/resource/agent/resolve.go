@@ -0,0 +1,55 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resolvingReferencesKey marks the context of the generated ResolveReferences
+// called by resolveAgentReferences.
+type resolvingReferencesKey struct{}
+
+// isResolvingReferences returns whether the context is the one
+// resolveAgentReferences passes to the generated ResolveReferences.
+func isResolvingReferences(ctx context.Context) bool {
+	return ctx.Value(resolvingReferencesKey{}) != nil
+}
+
+// resolveAgentReferences applies the AgentClass of the agent before its
+// references are resolved by the generated ResolveReferences, traces the
+// resolution and emits a Warning when it fails.
+func (rm *resourceManager) resolveAgentReferences(
+	ctx context.Context,
+	apiReader client.Reader,
+	res acktypes.AWSResource,
+) (_ acktypes.AWSResource, _ bool, err error) {
+	ko := rm.concreteResource(res).ko
+	ctx, span := startAgentSpan(ctx, "Agent.ResolveReferences", ko)
+	defer func() {
+		endAgentSpan(span, nil, err)
+		recordReferenceEvent(ko, err)
+	}()
+
+	hasClass, err := rm.applyAgentClass(ctx, apiReader, ko)
+	if err != nil {
+		return &resource{ko}, hasClass, err
+	}
+	ctx = context.WithValue(ctx, resolvingReferencesKey{}, true)
+	resolved, hasReferences, err := rm.ResolveReferences(ctx, apiReader, &resource{ko})
+	return resolved, hasClass || hasReferences, err
+}
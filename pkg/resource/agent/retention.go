@@ -0,0 +1,53 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/cluster"
+)
+
+// isRetained returns whether the agent is kept in AWS when its Agent is
+// deleted. The deletion policy is resolved like the runtime does: the
+// ackv1alpha1.AnnotationDeletionPolicy annotation of the Agent, then the one
+// of its namespace for the service, then the policy of the controller. The
+// namespace is only read when the controller has set the reader of the
+// cluster, see cluster.SetReader.
+func (rm *resourceManager) isRetained(
+	ctx context.Context,
+	ko *svcapitypes.Agent,
+	md acktypes.ServiceControllerMetadata,
+) (bool, error) {
+	if policy, ok := ko.GetAnnotations()[ackv1alpha1.AnnotationDeletionPolicy]; ok {
+		return ackv1alpha1.DeletionPolicy(policy) == ackv1alpha1.DeletionPolicyRetain, nil
+	}
+	if reader := cluster.Reader(); reader != nil {
+		var ns corev1.Namespace
+		if err := reader.Get(ctx, client.ObjectKey{Name: ko.Namespace}, &ns); err != nil {
+			return false, err
+		}
+		annotation := md.ServiceAlias + "." + ackv1alpha1.AnnotationDeletionPolicy
+		if policy, ok := ns.Annotations[annotation]; ok && policy != "" {
+			return ackv1alpha1.DeletionPolicy(policy) == ackv1alpha1.DeletionPolicyRetain, nil
+		}
+	}
+	return rm.cfg.DeletionPolicy == ackv1alpha1.DeletionPolicyRetain, nil
+}
@@ -0,0 +1,54 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func TestRetainedTag(t *testing.T) {
+	ctx := context.Background()
+	rm := newFakeResourceManager(t, fakebedrockagent.NewServer(t))
+	rm.cfg.DeletionPolicy = ackv1alpha1.DeletionPolicyRetain
+
+	for annotation, want := range map[string]bool{
+		"":                                       true,
+		string(ackv1alpha1.DeletionPolicyDelete): false,
+		string(ackv1alpha1.DeletionPolicyRetain): true,
+	} {
+		desired := dryRunAgent()
+		desired.ko.Annotations = nil
+		if annotation != "" {
+			desired.ko.Annotations = map[string]string{ackv1alpha1.AnnotationDeletionPolicy: annotation}
+		}
+		if err := rm.EnsureTags(ctx, desired, acktypes.ServiceControllerMetadata{}); err != nil {
+			t.Fatalf("EnsureTags() error = %v", err)
+		}
+		if _, got := desired.ko.Spec.Tags[tags.RetainedTagKey]; got != want {
+			t.Errorf("EnsureTags() with the deletion policy annotation %q set the retained tag = %v, want %v",
+				annotation, got, want)
+		}
+		rm.FilterSystemTags(desired, nil)
+		if _, ok := desired.ko.Spec.Tags[tags.RetainedTagKey]; ok {
+			t.Errorf("FilterSystemTags() kept the retained tag in the spec")
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws-controllers-k8s/runtime/pkg/featuregate"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	corev1 "k8s.io/api/core/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/revision"
+)
+
+// ConditionTypeRolledBack reports that the agent is reconciled to the spec
+// of the revision named by its RollbackAnnotation instead of its own spec.
+const ConditionTypeRolledBack ackv1alpha1.ConditionType = "RolledBack"
+
+// deltaPathRollback is the virtual spec field added to the delta of an
+// agent whose RollbackAnnotation names a revision that cannot be applied.
+const deltaPathRollback = "Spec.Rollback"
+
+// revisionSpec returns the spec recorded in a revision of the agent: the
+// desired spec without its tags and maintenance window, which aren't
+// prepared, and with its references resolved.
+func revisionSpec(ko *svcapitypes.Agent) *svcapitypes.AgentSpec {
+	spec := ko.Spec.DeepCopy()
+	spec.Tags = nil
+	spec.MaintenanceWindow = nil
+	spec.AgentResourceRoleRef = nil
+	return spec
+}
+
+// recordRevision adds the desired spec, or the spec of the revision it is
+// rolled back to, to the revisions of the observed agent once the agent is
+// prepared with it, i.e. PREPARED without pending changes. Preparing the
+// spec of the newest revision again only moves its PreparedAt.
+func recordRevision(desired *resource, observed *resource) {
+	if rolledBack, _, err := rolledBackAgent(desired.ko); err == nil {
+		desired = &resource{rolledBack}
+	}
+	ko := observed.ko
+	if revision.HistoryLimit() == 0 ||
+		aws.ToString(ko.Status.AgentStatus) != string(svcsdktypes.AgentStatusPrepared) ||
+		ko.Status.PreparedAt == nil ||
+		len(newResourceDelta(desired, observed).Differences) > 0 {
+		return
+	}
+	// PreparedAt is kept as saved by the API server, to the second.
+	preparedAt := ko.Status.PreparedAt.Rfc3339Copy()
+	var newest *svcapitypes.AgentRevision
+	if n := len(ko.Status.Revisions); n > 0 {
+		newest = ko.Status.Revisions[n-1]
+	}
+	spec, hash := revision.Encode(revisionSpec(desired.ko))
+	if newest != nil && aws.ToString(newest.SpecHash) == hash {
+		newest.PreparedAt = &preparedAt
+		return
+	}
+	number := int64(1)
+	if newest != nil {
+		number = aws.ToInt64(newest.Revision) + 1
+	}
+	revisions := append(ko.Status.Revisions, &svcapitypes.AgentRevision{
+		PreparedAt: &preparedAt,
+		Revision:   aws.Int64(number),
+		Spec:       aws.String(spec),
+		SpecHash:   aws.String(hash),
+	})
+	if excess := len(revisions) - revision.HistoryLimit(); excess > 0 {
+		revisions = revisions[excess:]
+	}
+	boundRevisionSpecs(revisions)
+	ko.Status.Revisions = revisions
+}
+
+// boundRevisionSpecs keeps the specs of the newest revisions that fit in
+// revision.MaxSpecBytes, and only the hashes of the older ones, which then
+// cannot be rolled back to.
+func boundRevisionSpecs(revisions []*svcapitypes.AgentRevision) {
+	size := 0
+	for i := len(revisions) - 1; i >= 0; i-- {
+		size += len(aws.ToString(revisions[i].Spec))
+		if size > revision.MaxSpecBytes {
+			revisions[i].Spec = nil
+		}
+	}
+}
+
+// findRollbackRevision returns the revision named by the RollbackAnnotation
+// of the agent, or nil when the agent isn't rolled back. It fails when the
+// revision isn't among the revisions of the agent.
+func findRollbackRevision(ko *svcapitypes.Agent) (*svcapitypes.AgentRevision, error) {
+	target, ok := revision.RollbackTarget(ko)
+	if !ok {
+		return nil, nil
+	}
+	for _, r := range ko.Status.Revisions {
+		if r != nil && revision.Matches(target, aws.ToInt64(r.Revision), aws.ToString(r.SpecHash)) {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf(
+		"cannot roll back to revision %q, which isn't among the revisions in status.revisions",
+		target,
+	)
+}
+
+// rolledBackAgent returns a copy of the agent with the spec of the revision
+// named by its RollbackAnnotation, keeping the tags and maintenance window of
+// the agent, or the agent itself when the agent isn't rolled back.
+func rolledBackAgent(ko *svcapitypes.Agent) (*svcapitypes.Agent, *svcapitypes.AgentRevision, error) {
+	r, err := findRollbackRevision(ko)
+	if err != nil || r == nil {
+		return ko, nil, err
+	}
+	if r.Spec == nil {
+		return ko, nil, fmt.Errorf(
+			"cannot roll back to revision %d, whose spec isn't kept as the newer ones exceed %d bytes",
+			aws.ToInt64(r.Revision), revision.MaxSpecBytes,
+		)
+	}
+	var spec svcapitypes.AgentSpec
+	if err := json.Unmarshal([]byte(aws.ToString(r.Spec)), &spec); err != nil {
+		return ko, nil, fmt.Errorf("cannot roll back to revision %d: %w", aws.ToInt64(r.Revision), err)
+	}
+	rolledBack := ko.DeepCopy()
+	spec.Tags = rolledBack.Spec.Tags
+	spec.MaintenanceWindow = rolledBack.Spec.MaintenanceWindow
+	rolledBack.Spec = spec
+	return rolledBack, r, nil
+}
+
+// compareRollback returns a copy of the desired agent with the spec of the
+// revision named by its RollbackAnnotation, which the delta compares instead
+// of its own spec, so that an agent in sync with its own spec is updated to
+// the revision. When the revision cannot be applied, the desired agent is
+// returned and the virtual field Spec.Rollback is added to the delta, so
+// that Update reports the error in the RolledBack condition.
+func compareRollback(delta *ackcompare.Delta, desired *resource) *resource {
+	target, ok := revision.RollbackTarget(desired.ko)
+	if !ok {
+		return desired
+	}
+	ko, _, err := rolledBackAgent(desired.ko)
+	if err != nil {
+		delta.Add(deltaPathRollback, target, err.Error())
+		return desired
+	}
+	return &resource{ko}
+}
+
+// rollBack returns a copy of the desired agent with the spec of the revision
+// named by its RollbackAnnotation and the RolledBack condition set, or the
+// desired agent itself when it isn't rolled back. When the revision cannot be
+// applied, it returns a copy of the latest agent with the RolledBack
+// condition False and an error, with a requeue, so that the spec of the
+// agent isn't applied instead.
+//
+// The annotation requires the IgnoreFieldDrift feature gate, with which the
+// runtime reconciles a resource as soon as its annotations change. Without
+// it, a rollback would only be applied at the next resync.
+func (rm *resourceManager) rollBack(desired *resource, latest *resource) (*resource, error) {
+	if _, ok := revision.RollbackTarget(desired.ko); ok && !rm.cfg.FeatureGates.IsEnabled(featuregate.IgnoreFieldDrift) {
+		err := fmt.Errorf(
+			"the %s annotation requires the %s feature gate", revision.RollbackAnnotation, featuregate.IgnoreFieldDrift,
+		)
+		failed := latest.ko.DeepCopy()
+		setCondition(failed, ConditionTypeRolledBack, corev1.ConditionFalse, aws.String(err.Error()))
+		return &resource{failed}, ackerr.NewTerminalError(err)
+	}
+	ko, r, err := rolledBackAgent(desired.ko)
+	if err != nil {
+		failed := latest.ko.DeepCopy()
+		setCondition(failed, ConditionTypeRolledBack, corev1.ConditionFalse, aws.String(err.Error()))
+		return &resource{failed}, ackrequeue.NeededAfter(err, ackrequeue.DefaultRequeueAfterDuration)
+	}
+	if r == nil {
+		return desired, nil
+	}
+	setCondition(ko, ConditionTypeRolledBack, corev1.ConditionTrue, aws.String(fmt.Sprintf(
+		"Rolled back to revision %d (%s); remove the %s annotation to apply the spec again",
+		aws.ToInt64(r.Revision), aws.ToString(r.SpecHash), revision.RollbackAnnotation,
+	)))
+	return &resource{ko}, nil
+}
+
+// keepDeclaredSpec sets the spec of the agent created or updated from the
+// spec of a revision back to the spec of the agent, which the runtime then
+// saves, so that the rollback doesn't overwrite it.
+func keepDeclaredSpec(res **resource, spec svcapitypes.AgentSpec) {
+	if *res != nil && (*res).ko != nil {
+		(*res).ko.Spec = spec
+	}
+}
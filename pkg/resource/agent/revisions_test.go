@@ -0,0 +1,208 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	"github.com/aws-controllers-k8s/runtime/pkg/featuregate"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/revision"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func TestRevisions_RecordAndRollBack(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+	rm.cfg.FeatureGates = featuregate.FeatureGates{featuregate.IgnoreFieldDrift: {Enabled: true}}
+
+	desired := dryRunAgent()
+	desired.ko.Annotations = nil
+	first := aws.ToString(desired.ko.Spec.Instruction)
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	desired.ko.Status = res.(*resource).ko.Status
+	waitForAgent(t, rm, desired)
+	syncAgent(t, rm, desired)
+	desired.ko.Status = waitForAgent(t, rm, desired).ko.Status
+
+	second := strings.Repeat("You are a terse support agent. ", 5)
+	desired.ko.Spec.Instruction = aws.String(second)
+	syncAgent(t, rm, desired)
+	desired.ko.Status = waitForAgent(t, rm, desired).ko.Status
+
+	revisions := desired.ko.Status.Revisions
+	if len(revisions) != 2 {
+		t.Fatalf("revisions = %d, want one per prepared spec", len(revisions))
+	}
+	for i, want := range []string{first, second} {
+		if got := aws.ToInt64(revisions[i].Revision); got != int64(i+1) {
+			t.Errorf("revisions[%d].Revision = %d, want %d", i, got, i+1)
+		}
+		if !strings.Contains(aws.ToString(revisions[i].Spec), want) {
+			t.Errorf("revisions[%d].Spec = %s, want the instruction %q", i, aws.ToString(revisions[i].Spec), want)
+		}
+		if strings.Contains(aws.ToString(revisions[i].Spec), `"tags"`) {
+			t.Errorf("revisions[%d].Spec records the tags", i)
+		}
+	}
+
+	// A revision that isn't recorded isn't rolled back to, and the spec of
+	// the agent isn't applied instead.
+	desired.ko.Annotations = map[string]string{revision.RollbackAnnotation: "9"}
+	latest := waitForAgent(t, rm, desired)
+	server.ResetCalls()
+	delta := newResourceDelta(desired, latest)
+	if !delta.DifferentAt("Spec") {
+		t.Fatal("delta has no Spec difference, want the unknown revision reported by Update")
+	}
+	res, err = rm.Update(ctx, desired, latest, delta)
+	var requeue *ackrequeue.RequeueNeededAfter
+	if !errors.As(err, &requeue) {
+		t.Errorf("Update() error = %v, want a requeue for an unknown revision", err)
+	}
+	if c := agentCondition(res.(*resource).ko, ConditionTypeRolledBack); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("RolledBack condition = %v, want False for an unknown revision", c)
+	}
+	if calls := server.Calls(); len(calls) != 0 {
+		t.Errorf("calls = %v, want none for an unknown revision", calls)
+	}
+
+	// The agent is in sync with its own spec, and is updated to the spec of
+	// the revision.
+	desired.ko.Annotations[revision.RollbackAnnotation] = "1"
+	latest = waitForAgent(t, rm, desired)
+	delta = newResourceDelta(desired, latest)
+	if !delta.DifferentAt("Spec.Instruction") {
+		t.Fatal("delta has no Spec.Instruction difference, want the spec of revision 1 compared")
+	}
+	res, err = rm.Update(ctx, desired, latest, delta)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	updated := res.(*resource)
+	if got := aws.ToString(updated.ko.Spec.Instruction); got != second {
+		t.Errorf("instruction of the updated agent = %q, want the spec of the agent kept", got)
+	}
+	if c := agentCondition(updated.ko, ConditionTypeRolledBack); c == nil || c.Status != corev1.ConditionTrue {
+		t.Errorf("RolledBack condition = %v, want True", c)
+	}
+	latest = waitForAgent(t, rm, desired)
+
+	agent, _ := server.Agent(aws.ToString(desired.ko.Status.AgentID))
+	if got := agent["instruction"]; got != first {
+		t.Errorf("instruction in AWS = %q, want the one of revision 1", got)
+	}
+	revisions = latest.ko.Status.Revisions
+	if n := len(revisions); n != 3 || aws.ToString(revisions[2].SpecHash) != aws.ToString(revisions[0].SpecHash) {
+		t.Errorf("revisions = %d, want the rollback recorded as revision 3 with the spec of revision 1", n)
+	}
+
+	// The agent rolled back isn't updated again.
+	desired.ko.Status = latest.ko.Status
+	server.ResetCalls()
+	syncAgent(t, rm, desired)
+	if calls := server.Calls(); slices.Contains(calls, "UpdateAgent") {
+		t.Errorf("calls = %v, want no UpdateAgent once rolled back", calls)
+	}
+}
+
+func TestRollBack_RequiresIgnoreFieldDrift(t *testing.T) {
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	desired := dryRunAgent()
+	desired.ko.Annotations = map[string]string{revision.RollbackAnnotation: "1"}
+	res, err := rm.Create(context.Background(), desired)
+	if !errors.Is(err, ackerr.Terminal) {
+		t.Errorf("Create() error = %v, want a terminal error without the IgnoreFieldDrift gate", err)
+	}
+	if c := agentCondition(res.(*resource).ko, ConditionTypeRolledBack); c == nil || c.Status != corev1.ConditionFalse {
+		t.Errorf("RolledBack condition = %v, want False without the IgnoreFieldDrift gate", c)
+	}
+	if calls := server.Calls(); len(calls) != 0 {
+		t.Errorf("calls = %v, want none without the IgnoreFieldDrift gate", calls)
+	}
+}
+
+func TestRecordRevision_HistoryLimit(t *testing.T) {
+	revision.SetHistoryLimit(2)
+	t.Cleanup(func() { revision.SetHistoryLimit(revision.DefaultHistoryLimit) })
+
+	desired := dryRunAgent()
+	observed := &resource{ko: desired.ko.DeepCopy()}
+	observed.ko.Status.AgentStatus = aws.String(fakebedrockagent.StatusPrepared)
+	for i := 0; i < 3; i++ {
+		desired.ko.Spec.IdleSessionTTLInSeconds = aws.Int64(int64(600 + i))
+		observed.ko.Spec.IdleSessionTTLInSeconds = desired.ko.Spec.IdleSessionTTLInSeconds
+		now := metav1.Now()
+		observed.ko.Status.PreparedAt = &now
+		recordRevision(desired, observed)
+	}
+	// Preparing the newest spec again adds no revision.
+	recordRevision(desired, observed)
+
+	revisions := observed.ko.Status.Revisions
+	if len(revisions) != 2 {
+		t.Fatalf("revisions = %d, want the history limit", len(revisions))
+	}
+	if first, last := aws.ToInt64(revisions[0].Revision), aws.ToInt64(revisions[1].Revision); first != 2 || last != 3 {
+		t.Errorf("revisions = %d..%d, want the newest ones, 2..3", first, last)
+	}
+}
+
+func TestRecordRevision_BoundsSpecs(t *testing.T) {
+	desired := dryRunAgent()
+	observed := &resource{ko: desired.ko.DeepCopy()}
+	observed.ko.Status.AgentStatus = aws.String(fakebedrockagent.StatusPrepared)
+	for i := 0; i < 3; i++ {
+		// Each spec takes a bit more than a third of revision.MaxSpecBytes.
+		desired.ko.Spec.Instruction = aws.String(strings.Repeat(string(rune('a'+i)), revision.MaxSpecBytes/3))
+		observed.ko.Spec.Instruction = desired.ko.Spec.Instruction
+		now := metav1.Now()
+		observed.ko.Status.PreparedAt = &now
+		recordRevision(desired, observed)
+	}
+
+	revisions := observed.ko.Status.Revisions
+	if len(revisions) != 3 {
+		t.Fatalf("revisions = %d, want 3", len(revisions))
+	}
+	for i, wantSpec := range []bool{false, true, true} {
+		if got := revisions[i].Spec != nil; got != wantSpec {
+			t.Errorf("revisions[%d] has a spec = %v, want %v", i, got, wantSpec)
+		}
+		if revisions[i].SpecHash == nil {
+			t.Errorf("revisions[%d] has no hash", i)
+		}
+	}
+
+	desired.ko.Annotations = map[string]string{revision.RollbackAnnotation: "1"}
+	desired.ko.Status.Revisions = revisions
+	if _, _, err := rolledBackAgent(desired.ko); err == nil {
+		t.Error("rolledBackAgent() error = nil, want an error for a revision without its spec")
+	}
+}
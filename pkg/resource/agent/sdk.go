@@ -64,6 +64,17 @@ func (rm *resourceManager) sdkFind(
 	defer func() {
 		exit(err)
 	}()
+	ctx, span := startAgentSpan(ctx, "Agent.ReadOne", r.ko)
+	defer func() {
+		endAgentSpan(span, latest, err)
+	}()
+	// An observed agent is read by the ID of the agent it adopts, see
+	// observe.go.
+	if isObserveOnly(r) {
+		if r, err = rm.adoptObservedAgent(ctx, r); err != nil {
+			return nil, err
+		}
+	}
 	// If any required fields in the input shape are missing, AWS resource is
 	// not created yet. Return NotFound here to indicate to callers that the
 	// resource isn't yet created.
@@ -75,10 +86,15 @@ func (rm *resourceManager) sdkFind(
 	if err != nil {
 		return nil, err
 	}
+	// The generated client has no span middleware, see tracing.go.
+	agentCtx := ctx
+	ctx, sdkSpan := startSDKSpan(ctx, "GetAgent")
 
 	var resp *svcsdk.GetAgentOutput
 	resp, err = rm.sdkapi.GetAgent(ctx, input)
 	rm.metrics.RecordAPICall("READ_ONE", "GetAgent", err)
+	endSDKSpan(sdkSpan, resp, err)
+	ctx = agentCtx
 	if err != nil {
 		var awsErr smithy.APIError
 		if errors.As(err, &awsErr) && awsErr.ErrorCode() == "ResourceNotFoundException" {
@@ -305,10 +321,35 @@ func (rm *resourceManager) sdkFind(
 	}
 
 	rm.setStatusDefaults(ko)
-	ko.Spec.Tags, err = rm.getTags(ctx, string(*ko.Status.ACKResourceMetadata.ARN))
+	ko.Spec.Tags, err = rm.getTags(ctx, &resource{ko})
 	if err != nil {
 		return nil, err
 	}
+	if ko.Spec.ActionGroups != nil {
+		ko.Spec.ActionGroups, err = getActionGroups(ctx, rm.tracedSDKAPI(), rm.metrics, *ko.Status.AgentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if ko.Spec.KnowledgeBases != nil {
+		ko.Spec.KnowledgeBases, err = getKnowledgeBases(ctx, rm.tracedSDKAPI(), rm.metrics, *ko.Status.AgentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	recordAgentStatus(ko)
+	recordAgentStatusEvent(r.ko.Status.AgentStatus, ko)
+	observed := &resource{ko}
+	mirrorOwnerTag(r, observed)
+	setOwnerStatus(observed)
+	// An observed agent is only mirrored, see observe.go.
+	if isObserveOnly(r) {
+		setObserveOnlyStatus(observed)
+		return observed, nil
+	}
+	setDryRunPlan(r, observed)
+	recordRevision(r, observed)
+	setPauseStatus(r, observed)
 	return &resource{ko}, nil
 }
 
@@ -348,15 +389,50 @@ func (rm *resourceManager) sdkCreate(
 	defer func() {
 		exit(err)
 	}()
+	// An observed agent is adopted, never created, see observe.go.
+	if isObserveOnly(desired) {
+		return observedCreate(desired)
+	}
+	// A paused agent isn't created, see pause.go.
+	if isPaused(desired) {
+		return pausedWrite(desired, "created")
+	}
+
+	// An agent with a rollback annotation is created with the spec of the
+	// revision it names, see revisions.go, but keeps its own spec.
+	rolledBack, err := rm.rollBack(desired, desired)
+	if err != nil {
+		return rolledBack, err
+	}
+	if rolledBack != desired {
+		defer keepDeclaredSpec(&created, desired.ko.Spec)
+		desired = rolledBack
+	}
+
+	// An agent in dry-run mode gets the plan of its creation, see plan.go.
+	if isDryRun(desired) {
+		return planCreate(desired)
+	}
+
+	ctx, span := startAgentSpan(ctx, "Agent.Create", desired.ko)
+	defer func() {
+		endAgentSpan(span, created, err)
+	}()
+
 	input, err := rm.newCreateRequestPayload(ctx, desired)
 	if err != nil {
 		return nil, err
 	}
+	// The generated client has no span middleware, see tracing.go.
+	agentCtx := ctx
+	ctx, sdkSpan := startSDKSpan(ctx, "CreateAgent")
 
 	var resp *svcsdk.CreateAgentOutput
 	_ = resp
 	resp, err = rm.sdkapi.CreateAgent(ctx, input)
 	rm.metrics.RecordAPICall("CREATE", "CreateAgent", err)
+	endSDKSpan(sdkSpan, resp, err)
+	ctx = agentCtx
 	if err != nil {
 		return nil, err
 	}
@@ -578,6 +654,9 @@ func (rm *resourceManager) sdkCreate(
 	}
 
 	rm.setStatusDefaults(ko)
+	recordSpecChange(ko)
+	recordAgentStatus(ko)
+	recordAgentStatusEvent(desired.ko.Status.AgentStatus, ko)
 	return &resource{ko}, nil
 }
 
@@ -774,15 +853,55 @@ func (rm *resourceManager) sdkUpdate(
 	defer func() {
 		exit(err)
 	}()
-	// If AgentStatus is not in PREPARED state we need to call PrepareAgent to finalize setup
-	// of Agent. Uses hack (see delta.go) to trigger update from non-existent Spec.AgentStatus
-	if delta.DifferentAt("Spec.AgentStatus") {
-		err = prepareAgent(ctx, rm.sdkapi, rm.metrics, *desired.ko.Status.AgentID)
-		if err != nil {
-			return nil, err
-		}
+	// The spec of an observed agent is mirrored from AWS instead of applied,
+	// see observe.go.
+	if isObserveOnly(desired) {
+		return observedUpdate(latest), nil
+	}
+	// A paused agent isn't updated, see pause.go.
+	if isPaused(desired) {
+		return pausedUpdate(desired, latest), nil
+	}
+	// Changes to the immutable fields fail the update, see validation.go.
+	if err := checkImmutableFieldChanges(delta); err != nil {
+		return nil, err
+	}
+
+	// An agent with a rollback annotation is updated to the spec of the
+	// revision it names, which the delta already compares, see
+	// revisions.go, but keeps its own spec.
+	rolledBack, err := rm.rollBack(desired, latest)
+	if err != nil {
+		return rolledBack, err
+	}
+	if rolledBack != desired {
+		defer keepDeclaredSpec(&updated, desired.ko.Spec)
+		desired = rolledBack
 	}
 
+	// An agent in dry-run mode only gets the plan written by ReadOne, see
+	// plan.go.
+	if isDryRun(desired) {
+		return planUpdate(desired, latest)
+	}
+	// An agent owned by another cluster isn't updated, see ownership.go.
+	if conflict, ok := ownershipConflict(desired, latest); ok {
+		return conflict, nil
+	}
+	// Changes waiting for an approval aren't applied, see approval.go.
+	if awaiting, ok := awaitApproval(desired, latest, delta); ok {
+		return awaiting, nil
+	}
+
+	ctx, span := startAgentSpan(ctx, "Agent.Update", latest.ko)
+	defer func() {
+		endAgentSpan(span, updated, err)
+	}()
+
+	// UpdateAgent is sent the key of the agent, which its desired spec may
+	// leave unset, see agentclass.go.
+	desired, _ = keepEncryptionKey(desired, latest)
+
 	if delta.DifferentAt("Spec.Tags") {
 		err := rm.syncTags(
 			ctx,
@@ -794,7 +913,19 @@ func (rm *resourceManager) sdkUpdate(
 		}
 	}
 
-	if !delta.DifferentExcept("Spec.AgentStatus", "Spec.Tags") {
+	// Changes that prepare the agent again wait for its maintenance window,
+	// see maintenance.go.
+	if deferred, ok, err := deferChanges(desired, latest, delta); err != nil || ok {
+		return deferred, err
+	}
+
+	// Action groups, knowledge bases and the PREPARED state (see delta.go)
+	// are reconciled without calling UpdateAgent.
+	if !delta.DifferentExcept("Spec.AgentStatus", "Spec.Tags", "Spec.ActionGroups", "Spec.KnowledgeBases") {
+		err = rm.syncAgentComponents(ctx, desired, latest, delta, false, latest.ko.Status.AgentStatus)
+		if err != nil {
+			return nil, err
+		}
 		return desired, nil
 	}
 
@@ -802,11 +933,16 @@ func (rm *resourceManager) sdkUpdate(
 	if err != nil {
 		return nil, err
 	}
+	// The generated client has no span middleware, see tracing.go.
+	agentCtx := ctx
+	ctx, sdkSpan := startSDKSpan(ctx, "UpdateAgent")
 
 	var resp *svcsdk.UpdateAgentOutput
 	_ = resp
 	resp, err = rm.sdkapi.UpdateAgent(ctx, input)
 	rm.metrics.RecordAPICall("UPDATE", "UpdateAgent", err)
+	endSDKSpan(sdkSpan, resp, err)
+	ctx = agentCtx
 	if err != nil {
 		return nil, err
 	}
@@ -1028,6 +1164,12 @@ func (rm *resourceManager) sdkUpdate(
 	}
 
 	rm.setStatusDefaults(ko)
+	// UpdateAgent moves the agent back to NOT_PREPARED, so the components are
+	// synced and the agent prepared once all changes have been made.
+	err = rm.syncAgentComponents(ctx, desired, latest, delta, true, ko.Status.AgentStatus)
+	if err != nil {
+		return &resource{ko}, err
+	}
 	return &resource{ko}, nil
 }
 
@@ -1222,14 +1364,42 @@ func (rm *resourceManager) sdkDelete(
 	defer func() {
 		exit(err)
 	}()
+	// An observed agent is left to its owner, see observe.go, a paused agent
+	// isn't deleted, see pause.go, an agent in dry-run mode only gets the plan
+	// of its deletion, see plan.go, and an agent owned by another cluster is
+	// left to it, see ownership.go.
+	if isObserveOnly(r) {
+		return observedDelete(r), nil
+	}
+	if isPaused(r) {
+		return pausedWrite(r, "deleted")
+	}
+	if isDryRun(r) {
+		return planDelete(r)
+	}
+	if foreignDelete(r) {
+		return r, nil
+	}
+
+	ctx, span := startAgentSpan(ctx, "Agent.Delete", r.ko)
+	defer func() {
+		endAgentSpan(span, latest, err)
+	}()
+
 	input, err := rm.newDeleteRequestPayload(r)
 	if err != nil {
 		return nil, err
 	}
+	// The generated client has no span middleware, see tracing.go.
+	ctx, sdkSpan := startSDKSpan(ctx, "DeleteAgent")
 	var resp *svcsdk.DeleteAgentOutput
 	_ = resp
 	resp, err = rm.sdkapi.DeleteAgent(ctx, input)
 	rm.metrics.RecordAPICall("DELETE", "DeleteAgent", err)
+	endSDKSpan(sdkSpan, resp, err)
+	if err == nil {
+		forgetAgentMetrics(r.ko)
+	}
 	return nil, err
 }
 
@@ -0,0 +1,425 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	acktags "github.com/aws-controllers-k8s/runtime/pkg/tags"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlrtclient "sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlrtmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	svcevents "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/events"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+)
+
+// serviceAlias is the value of the acktags.ServiceAliasTagFormat placeholder
+// of the resource tags of the controller.
+const serviceAlias = "bedrockagent"
+
+// sweptTags locates, in the tags the controller gives its agents with the
+// --resource-tags flag, the tag identifying the controller and the one
+// holding the namespace of the agent.
+type sweptTags struct {
+	// controllerKey is the key of the tag whose value starts with
+	// controllerPrefix on the agents of the controller.
+	controllerKey    string
+	controllerPrefix string
+	// namespaceKey is the key of the tag whose value is the namespace of the
+	// agent between namespacePrefix and namespaceSuffix. The agents have no
+	// namespace when it is empty.
+	namespaceKey    string
+	namespacePrefix string
+	namespaceSuffix string
+}
+
+// parseSweptTags returns the sweptTags of the resource tags, formatted like
+// the values of the --resource-tags flag. The controller tag is the first
+// one whose value has the acktags.ServiceAliasTagFormat placeholder, e.g.
+// services.k8s.aws/controller-version=%CONTROLLER_SERVICE%-%CONTROLLER_VERSION%,
+// and is required. Its value is matched up to the placeholder following the
+// service alias, e.g. the version of the controller, so that the agents of
+// the former versions are swept too.
+func parseSweptTags(resourceTags []string) (sweptTags, error) {
+	var swept sweptTags
+	for _, tag := range resourceTags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok {
+			return sweptTags{}, fmt.Errorf("invalid resource tag: %s", tag)
+		}
+		if before, after, ok := strings.Cut(value, acktags.ServiceAliasTagFormat); ok &&
+			swept.controllerKey == "" && !strings.Contains(before, "%") {
+			if i := strings.Index(after, "%"); i >= 0 {
+				after = after[:i]
+			}
+			swept.controllerKey = key
+			swept.controllerPrefix = before + serviceAlias + after
+		}
+		if before, after, ok := strings.Cut(value, acktags.NamespaceTagFormat); ok &&
+			swept.namespaceKey == "" && !strings.Contains(before+after, "%") {
+			swept.namespaceKey = key
+			swept.namespacePrefix = before
+			swept.namespaceSuffix = after
+		}
+	}
+	if swept.controllerKey == "" {
+		return sweptTags{}, fmt.Errorf(
+			"no resource tag value has the %s placeholder, which identifies the agents of the controller",
+			acktags.ServiceAliasTagFormat,
+		)
+	}
+	return swept, nil
+}
+
+// isController returns whether the agent with the given tags was created by
+// the controller.
+func (t sweptTags) isController(agentTags map[string]string) bool {
+	return strings.HasPrefix(agentTags[t.controllerKey], t.controllerPrefix)
+}
+
+// namespace returns the namespace the agent with the given tags is tagged
+// with.
+func (t sweptTags) namespace(agentTags map[string]string) string {
+	if t.namespaceKey == "" {
+		return ""
+	}
+	value := agentTags[t.namespaceKey]
+	if !strings.HasPrefix(value, t.namespacePrefix) || !strings.HasSuffix(value, t.namespaceSuffix) {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(value, t.namespacePrefix), t.namespaceSuffix)
+}
+
+// Reasons of the Events emitted about orphaned agents.
+const (
+	EventReasonOrphanedAgent           = "OrphanedAgent"
+	EventReasonOrphanedAgentDeleted    = "OrphanedAgentDeleted"
+	EventReasonOrphanedAgentDeleteFail = "OrphanedAgentDeleteFailed"
+)
+
+var (
+	orphanedAgentsGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "ack_bedrockagent_orphaned_agents",
+			Help: "Number of agents tagged by the controller without an Agent resource, found by the last sweep.",
+		},
+		[]string{"namespace"},
+	)
+	orphanedAgentsDeletedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ack_bedrockagent_orphaned_agents_deleted_total",
+			Help: "Total number of orphaned agents deleted by the sweeper.",
+		},
+		[]string{"namespace"},
+	)
+)
+
+func init() {
+	ctrlrtmetrics.Registry.MustRegister(orphanedAgentsGauge, orphanedAgentsDeletedTotal)
+}
+
+type sweeperClient interface {
+	agentsClient
+	GetAgent(context.Context, *svcsdk.GetAgentInput, ...func(*svcsdk.Options)) (*svcsdk.GetAgentOutput, error)
+	ListTagsForResource(context.Context, *svcsdk.ListTagsForResourceInput, ...func(*svcsdk.Options)) (*svcsdk.ListTagsForResourceOutput, error)
+	DeleteAgent(context.Context, *svcsdk.DeleteAgentInput, ...func(*svcsdk.Options)) (*svcsdk.DeleteAgentOutput, error)
+}
+
+// SweeperOptions configures a Sweeper.
+type SweeperOptions struct {
+	// Interval is the time between two sweeps.
+	Interval time.Duration
+	// Delete deletes the agents orphaned for longer than GracePeriod. The
+	// orphaned agents are only reported otherwise.
+	Delete bool
+	// GracePeriod is the time an agent stays orphaned before it is deleted,
+	// counted from the sweep that found it.
+	GracePeriod time.Duration
+	// Namespaces restricts the sweep to the agents tagged with one of the
+	// namespaces, e.g. the namespaces watched by the controller. Every agent
+	// is swept when it is empty.
+	Namespaces []string
+	// ResourceTags are the tags the controller gives its agents, i.e. the
+	// values of its --resource-tags flag, which identify them.
+	ResourceTags []string
+}
+
+// Sweeper finds the agents created by the controller, i.e. carrying its ACK
+// system tags, whose Agent resource is gone, e.g. because it was deleted
+// while the controller was down or its finalizer was removed by hand. It
+// reports them with rate-limited Events, regarding the missing Agent, and
+// metrics, and deletes them after a grace period when so configured.
+//
+// An agent is still owned while an Agent resource has its AgentID, or has
+// its name in the namespace it is tagged with, so that the agent of an Agent
+// whose creation isn't recorded yet is never swept. The agents tagged with
+// tags.RetainedTagKey, whose Agent was deleted with the retain deletion
+// policy, are never swept either.
+type Sweeper struct {
+	client   sweeperClient
+	reader   ctrlrtclient.Reader
+	recorder *svcevents.Recorder
+	log      logr.Logger
+	opts     SweeperOptions
+	tags     sweptTags
+
+	mu      sync.Mutex
+	orphans map[string]orphan
+}
+
+// orphan is an agent found orphaned by a sweep.
+type orphan struct {
+	// since is the time of the sweep that found the agent orphaned.
+	since time.Time
+	// regarding stands for the missing Agent in the Events about the agent.
+	regarding *svcapitypes.Agent
+}
+
+// sweeperNow returns the current time. It is replaced in tests.
+var sweeperNow = time.Now
+
+// NewSweeper returns a Sweeper of the agents in the account and region of
+// the AWS config, reading the Agent resources with the reader. It fails when
+// the resource tags don't identify the agents of the controller.
+func NewSweeper(
+	cfg aws.Config,
+	reader ctrlrtclient.Reader,
+	recorder *svcevents.Recorder,
+	log logr.Logger,
+	opts SweeperOptions,
+) (*Sweeper, error) {
+	return newSweeper(svcsdk.NewFromConfig(cfg), reader, recorder, log, opts)
+}
+
+func newSweeper(
+	client sweeperClient,
+	reader ctrlrtclient.Reader,
+	recorder *svcevents.Recorder,
+	log logr.Logger,
+	opts SweeperOptions,
+) (*Sweeper, error) {
+	swept, err := parseSweptTags(opts.ResourceTags)
+	if err != nil {
+		return nil, err
+	}
+	return &Sweeper{
+		client:   client,
+		reader:   reader,
+		recorder: recorder,
+		log:      log,
+		opts:     opts,
+		tags:     swept,
+		orphans:  map[string]orphan{},
+	}, nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable: only the
+// leader sweeps.
+func (s *Sweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// Start sweeps every Interval until the context is done. It implements
+// manager.Runnable, so that the manager of the controller runs the Sweeper.
+func (s *Sweeper) Start(ctx context.Context) error {
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.sweep(ctx); err != nil {
+			s.log.Error(err, "unable to sweep the orphaned agents")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// sweep reports, and deletes once their grace period is over, the agents
+// orphaned at the time of the call.
+func (s *Sweeper) sweep(ctx context.Context) error {
+	agents, err := s.listAgents(ctx)
+	if err != nil {
+		return err
+	}
+	ownedIDs := map[string]bool{}
+	ownedNames := map[string]bool{}
+	for _, ko := range agents {
+		if ko.Status.AgentID != nil {
+			ownedIDs[*ko.Status.AgentID] = true
+		}
+		ownedNames[ko.Namespace+"/"+aws.ToString(ko.Spec.AgentName)] = true
+	}
+
+	var summaries []svcsdktypes.AgentSummary
+	input := &svcsdk.ListAgentsInput{}
+	for {
+		resp, err := s.client.ListAgents(ctx, input)
+		if err != nil {
+			return err
+		}
+		summaries = append(summaries, resp.AgentSummaries...)
+		if resp.NextToken == nil {
+			break
+		}
+		input.NextToken = resp.NextToken
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	orphaned := map[string]bool{}
+	counts := map[string]float64{}
+	for _, summary := range summaries {
+		agentID := aws.ToString(summary.AgentId)
+		if ownedIDs[agentID] || summary.AgentStatus == svcsdktypes.AgentStatusDeleting {
+			continue
+		}
+		namespace, ok, err := s.orphanNamespace(ctx, agentID)
+		if err != nil {
+			s.log.Error(err, "unable to read the tags of the agent", "agentID", agentID)
+			continue
+		}
+		if !ok || ownedNames[namespace+"/"+aws.ToString(summary.AgentName)] {
+			continue
+		}
+		orphaned[agentID] = true
+		counts[namespace]++
+		s.handleOrphan(ctx, agentID, aws.ToString(summary.AgentName), namespace)
+	}
+	for agentID, o := range s.orphans {
+		if !orphaned[agentID] {
+			s.forgetOrphan(agentID, o)
+		}
+	}
+	orphanedAgentsGauge.Reset()
+	for namespace, count := range counts {
+		orphanedAgentsGauge.WithLabelValues(namespace).Set(count)
+	}
+	return nil
+}
+
+// listAgents returns the Agent resources of the swept namespaces, listed
+// one namespace at a time when the sweep is restricted, as the controller
+// may only be allowed to read those.
+func (s *Sweeper) listAgents(ctx context.Context) ([]svcapitypes.Agent, error) {
+	namespaces := s.opts.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{corev1.NamespaceAll}
+	}
+	var agents []svcapitypes.Agent
+	for _, namespace := range namespaces {
+		var list svcapitypes.AgentList
+		if err := s.reader.List(ctx, &list, ctrlrtclient.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		agents = append(agents, list.Items...)
+	}
+	return agents, nil
+}
+
+// orphanNamespace returns the namespace the agent is tagged with, and whether
+// the agent was created by the controller in a swept namespace and isn't
+// retained.
+func (s *Sweeper) orphanNamespace(ctx context.Context, agentID string) (string, bool, error) {
+	agent, err := s.client.GetAgent(ctx, &svcsdk.GetAgentInput{AgentId: aws.String(agentID)})
+	if err != nil {
+		return "", false, err
+	}
+	resp, err := s.client.ListTagsForResource(ctx, &svcsdk.ListTagsForResourceInput{
+		ResourceArn: agent.Agent.AgentArn,
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if !s.tags.isController(resp.Tags) {
+		return "", false, nil
+	}
+	// The agents of another cluster are never orphans of this one, and the
+	// retained agents are meant to outlive their Agent.
+	if tags.IsOwnedElsewhere(resp.Tags[tags.OwnerClusterTagKey]) || tags.IsRetained(resp.Tags) {
+		return "", false, nil
+	}
+	namespace := s.tags.namespace(resp.Tags)
+	if len(s.opts.Namespaces) == 0 {
+		return namespace, true, nil
+	}
+	for _, swept := range s.opts.Namespaces {
+		if swept == namespace {
+			return namespace, true, nil
+		}
+	}
+	return namespace, false, nil
+}
+
+// handleOrphan reports the agent the first time it is found orphaned, and
+// deletes it once its grace period is over.
+func (s *Sweeper) handleOrphan(ctx context.Context, agentID, agentName, namespace string) {
+	o, ok := s.orphans[agentID]
+	if !ok {
+		// The Events regard the missing Agent, so that they are listed with
+		// the Events of its namespace.
+		o = orphan{
+			since: sweeperNow(),
+			regarding: &svcapitypes.Agent{
+				TypeMeta:   metav1.TypeMeta{APIVersion: svcapitypes.GroupVersion.String(), Kind: "Agent"},
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: agentName},
+			},
+		}
+		s.orphans[agentID] = o
+		s.log.Info("found an orphaned agent", "agentID", agentID, "namespace", namespace, "agentName", agentName)
+		s.recorder.Warning(
+			o.regarding, EventReasonOrphanedAgent, "Sweep",
+			"Agent %s was created by the controller but has no Agent resource", agentID,
+		)
+	}
+	if !s.opts.Delete || sweeperNow().Sub(o.since) < s.opts.GracePeriod {
+		return
+	}
+	_, err := s.client.DeleteAgent(ctx, &svcsdk.DeleteAgentInput{AgentId: aws.String(agentID)})
+	if err != nil {
+		s.log.Error(err, "unable to delete an orphaned agent", "agentID", agentID)
+		s.recorder.Warning(
+			o.regarding, EventReasonOrphanedAgentDeleteFail, "DeleteAgent",
+			"Unable to delete the orphaned agent %s: %v", agentID, err,
+		)
+		return
+	}
+	orphanedAgentsDeletedTotal.WithLabelValues(namespace).Inc()
+	s.log.Info("deleted an orphaned agent", "agentID", agentID, "namespace", namespace, "agentName", agentName)
+	s.recorder.Normal(
+		o.regarding, EventReasonOrphanedAgentDeleted, "DeleteAgent",
+		"Deleted agent %s, orphaned since %s", agentID, o.since.Format(time.RFC3339),
+	)
+	s.forgetOrphan(agentID, o)
+}
+
+// forgetOrphan stops tracking an agent that is deleted or no longer
+// orphaned, and releases the rate limits of its Events.
+func (s *Sweeper) forgetOrphan(agentID string, o orphan) {
+	delete(s.orphans, agentID)
+	s.recorder.Forget(o.regarding)
+}
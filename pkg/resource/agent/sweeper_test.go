@@ -0,0 +1,186 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	svcevents "github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/events"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/tags"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+func TestSweeper(t *testing.T) {
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	client := svcsdk.NewFromConfig(server.Config())
+	createAgent := func(name string, tags map[string]string) string {
+		t.Helper()
+		resp, err := client.CreateAgent(ctx, &svcsdk.CreateAgentInput{
+			AgentName: aws.String(name),
+			Tags:      tags,
+		})
+		if err != nil {
+			t.Fatalf("CreateAgent() error = %v", err)
+		}
+		return aws.ToString(resp.Agent.AgentId)
+	}
+	// The controller is run with custom --resource-tags.
+	ownedTags := func(namespace string) map[string]string {
+		return map[string]string{
+			"example.com/controller": "ack-bedrockagent-v1.0.0",
+			"example.com/namespace":  "k8s-" + namespace,
+		}
+	}
+	trackedID := createAgent("tracked", ownedTags("default"))
+	createAgent("creating", ownedTags("default"))
+	orphanID := createAgent("orphan", ownedTags("default"))
+	retainedTags := ownedTags("default")
+	retainedTags[tags.RetainedTagKey] = "true"
+	retainedID := createAgent("retained", retainedTags)
+	createAgent("default-tags", map[string]string{
+		"services.k8s.aws/controller-version": "bedrockagent-v1.0.0",
+		"services.k8s.aws/namespace":          "default",
+	})
+	createAgent("unwatched", ownedTags("other"))
+	createAgent("unmanaged", map[string]string{"team": "ml"})
+
+	scheme := runtime.NewScheme()
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	tracked := &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tracked"},
+		Spec:       svcapitypes.AgentSpec{AgentName: aws.String("renamed")},
+	}
+	tracked.Status.AgentID = aws.String(trackedID)
+	// The creation of this agent isn't recorded in its status yet.
+	creating := &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "creating"},
+		Spec:       svcapitypes.AgentSpec{AgentName: aws.String("creating")},
+	}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tracked, creating).Build()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sweeperNow = func() time.Time { return now }
+	t.Cleanup(func() { sweeperNow = time.Now })
+	recorder := events.NewFakeRecorder(10)
+	sweeper, err := newSweeper(client, reader, svcevents.NewRecorder(recorder), logr.Discard(), SweeperOptions{
+		Delete:      true,
+		GracePeriod: time.Hour,
+		Namespaces:  []string{"default"},
+		ResourceTags: []string{
+			"example.com/controller=ack-%CONTROLLER_SERVICE%-%CONTROLLER_VERSION%",
+			"example.com/namespace=k8s-%K8S_NAMESPACE%",
+		},
+	})
+	if err != nil {
+		t.Fatalf("newSweeper() error = %v", err)
+	}
+	if !sweeper.NeedLeaderElection() {
+		t.Error("NeedLeaderElection() = false, want only the leader to sweep")
+	}
+
+	sweep := func() []string {
+		t.Helper()
+		server.ResetCalls()
+		if err := sweeper.sweep(ctx); err != nil {
+			t.Fatalf("sweep() error = %v", err)
+		}
+		var got []string
+		for len(recorder.Events) > 0 {
+			got = append(got, <-recorder.Events)
+		}
+		return got
+	}
+	deleted := func() bool {
+		for _, call := range server.Calls() {
+			if call == "DeleteAgent" {
+				return true
+			}
+		}
+		return false
+	}
+
+	got := sweep()
+	if len(got) != 1 || !strings.HasPrefix(got[0], "Warning OrphanedAgent Agent "+orphanID) {
+		t.Errorf("first sweep events = %q, want one OrphanedAgent event for %s", got, orphanID)
+	}
+	if deleted() {
+		t.Errorf("first sweep deleted an agent before the grace period")
+	}
+
+	now = now.Add(30 * time.Minute)
+	if got := sweep(); len(got) != 0 || deleted() {
+		t.Errorf("sweep within the grace period events = %q, deleted = %v, want none", got, deleted())
+	}
+
+	now = now.Add(time.Hour)
+	got = sweep()
+	if len(got) != 1 || !strings.HasPrefix(got[0], "Normal OrphanedAgentDeleted Deleted agent "+orphanID) {
+		t.Errorf("sweep after the grace period events = %q, want OrphanedAgentDeleted", got)
+	}
+	if agent, _ := server.Agent(orphanID); agent["agentStatus"] != fakebedrockagent.StatusDeleting {
+		t.Errorf("orphaned agent status = %v, want DELETING", agent["agentStatus"])
+	}
+	if agent, _ := server.Agent(retainedID); agent["agentStatus"] == fakebedrockagent.StatusDeleting {
+		t.Errorf("retained agent status = DELETING, want it kept")
+	}
+
+	// The deleting agent is no longer reported.
+	if got := sweep(); len(got) != 0 || deleted() {
+		t.Errorf("sweep of a deleting agent events = %q, deleted = %v, want none", got, deleted())
+	}
+}
+
+func TestParseSweptTags(t *testing.T) {
+	swept, err := parseSweptTags([]string{
+		"services.k8s.aws/controller-version=%CONTROLLER_SERVICE%-%CONTROLLER_VERSION%",
+		"services.k8s.aws/namespace=%K8S_NAMESPACE%",
+	})
+	if err != nil {
+		t.Fatalf("parseSweptTags() of the default resource tags error = %v", err)
+	}
+	want := sweptTags{
+		controllerKey:    "services.k8s.aws/controller-version",
+		controllerPrefix: "bedrockagent-",
+		namespaceKey:     "services.k8s.aws/namespace",
+	}
+	if swept != want {
+		t.Errorf("parseSweptTags() of the default resource tags = %+v, want %+v", swept, want)
+	}
+
+	for _, resourceTags := range [][]string{
+		nil,
+		{"services.k8s.aws/namespace=%K8S_NAMESPACE%"},
+		{"team=%K8S_NAMESPACE%-%CONTROLLER_SERVICE%"},
+		{"invalid"},
+	} {
+		if _, err := parseSweptTags(resourceTags); err == nil {
+			t.Errorf("parseSweptTags(%q) error = nil, want an error", resourceTags)
+		}
+	}
+}
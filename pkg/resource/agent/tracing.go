@@ -0,0 +1,110 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"sync"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/tracing"
+)
+
+// Attributes of the Agent spans.
+const (
+	attributeAgentName = attribute.Key("ack.bedrockagent.agent.name")
+	attributeAgentID   = attribute.Key("aws.bedrockagent.agent_id")
+)
+
+// startAgentSpan starts a span for an operation on the agent. The SDK calls
+// made with the returned context carry the agent ID, if it is known.
+func startAgentSpan(
+	ctx context.Context,
+	name string,
+	ko *svcapitypes.Agent,
+) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.K8SNamespaceName(ko.Namespace),
+		attributeAgentName.String(ko.Name),
+	}
+	if ko.Status.AgentID != nil {
+		ctx = tracing.ContextWithAttributes(ctx, attributeAgentID.String(*ko.Status.AgentID))
+	}
+	return tracing.Start(ctx, name, append(attrs, tracing.AttributesFromContext(ctx)...)...)
+}
+
+// endAgentSpan ends the span of an operation on the agent, adding the ID of
+// the agent it created. An agent that isn't found isn't an error.
+func endAgentSpan(span trace.Span, r *resource, err error) {
+	if r != nil && r.ko != nil && r.ko.Status.AgentID != nil {
+		span.SetAttributes(attributeAgentID.String(*r.ko.Status.AgentID))
+	}
+	tracing.End(span, err, ackerr.NotFound)
+}
+
+// startSDKSpan starts the span of a call of the generated code to the
+// operation. The client of the resource manager, created in manager.go, has
+// no tracing.AddSDKSpans middleware, so the hooks around the generated calls
+// trace them.
+func startSDKSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracing.StartSDKCall(ctx, svcsdk.ServiceID, operation)
+}
+
+// endSDKSpan ends the span of a call of the generated code, given its
+// response, which is nil when the call failed.
+func endSDKSpan(span trace.Span, resp any, err error) {
+	var metadata middleware.Metadata
+	switch resp := resp.(type) {
+	case *svcsdk.GetAgentOutput:
+		if resp != nil {
+			metadata = resp.ResultMetadata
+		}
+	case *svcsdk.CreateAgentOutput:
+		if resp != nil {
+			metadata = resp.ResultMetadata
+		}
+	case *svcsdk.UpdateAgentOutput:
+		if resp != nil {
+			metadata = resp.ResultMetadata
+		}
+	case *svcsdk.DeleteAgentOutput:
+		if resp != nil {
+			metadata = resp.ResultMetadata
+		}
+	}
+	tracing.EndSDKCall(span, metadata, err)
+}
+
+// tracedClients holds, by client of a resource manager, the copy of the
+// client with the tracing.AddSDKSpans middleware.
+var tracedClients sync.Map
+
+// tracedSDKAPI returns the client of the resource manager with a span for
+// each call, for the calls made by the hooks.
+func (rm *resourceManager) tracedSDKAPI() *svcsdk.Client {
+	if traced, ok := tracedClients.Load(rm.sdkapi); ok {
+		return traced.(*svcsdk.Client)
+	}
+	traced, _ := tracedClients.LoadOrStore(rm.sdkapi, svcsdk.New(rm.sdkapi.Options(), func(o *svcsdk.Options) {
+		o.APIOptions = append(o.APIOptions, tracing.AddSDKSpans)
+	}))
+	return traced.(*svcsdk.Client)
+}
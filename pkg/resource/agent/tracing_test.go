@@ -0,0 +1,161 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/testutil/fakebedrockagent"
+)
+
+// withSpanRecorder records the spans in memory until the end of the test.
+func withSpanRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+// findSpan returns the first span with the given name, and whether there is
+// one.
+func findSpan(spans tracetest.SpanStubs, name string) (tracetest.SpanStub, bool) {
+	for _, span := range spans {
+		if span.Name == name {
+			return span, true
+		}
+	}
+	return tracetest.SpanStub{}, false
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) string {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestAgentSpans_FakeServer(t *testing.T) {
+	exporter := withSpanRecorder(t)
+	ctx := context.Background()
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	desired := &resource{ko: &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "traced", Namespace: "tracing"},
+		Spec: svcapitypes.AgentSpec{
+			AgentName:            aws.String("traced"),
+			AgentResourceRoleARN: aws.String("arn:aws:iam::123456789012:role/agent-role"),
+			FoundationModel:      aws.String("anthropic.claude-3-haiku-20240307-v1:0"),
+			Instruction:          aws.String("You are a helpful agent that answers questions."),
+		},
+	}}
+	res, err := rm.Create(ctx, desired)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	desired.ko.Status = res.(*resource).ko.Status
+	agentID := aws.ToString(desired.ko.Status.AgentID)
+	if _, err := rm.ReadOne(ctx, desired); err != nil {
+		t.Fatalf("ReadOne() error = %v", err)
+	}
+	spans := exporter.GetSpans()
+
+	create, ok := findSpan(spans, "Agent.Create")
+	if !ok {
+		t.Fatal("no Agent.Create span")
+	}
+	createAgent, ok := findSpan(spans, "Bedrock Agent.CreateAgent")
+	if !ok {
+		t.Fatal("no CreateAgent span")
+	}
+	readOne, ok := findSpan(spans, "Agent.ReadOne")
+	if !ok {
+		t.Fatal("no Agent.ReadOne span")
+	}
+	getAgent, ok := findSpan(spans, "Bedrock Agent.GetAgent")
+	if !ok {
+		t.Fatal("no GetAgent span")
+	}
+
+	for _, tc := range []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Create namespace", spanAttribute(create, "k8s.namespace.name"), "tracing"},
+		{"Create name", spanAttribute(create, attributeAgentName), "traced"},
+		{"Create agent ID", spanAttribute(create, attributeAgentID), agentID},
+		{"CreateAgent method", spanAttribute(createAgent, "rpc.method"), "CreateAgent"},
+		{"CreateAgent request ID", spanAttribute(createAgent, "aws.request_id"), fakebedrockagent.RequestID(1)},
+		{"ReadOne agent ID", spanAttribute(readOne, attributeAgentID), agentID},
+		{"GetAgent agent ID", spanAttribute(getAgent, attributeAgentID), agentID},
+		{"GetAgent system", spanAttribute(getAgent, "rpc.system"), "aws-api"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.name, tc.got, tc.want)
+		}
+	}
+	if createAgent.Parent.SpanID() != create.SpanContext.SpanID() {
+		t.Error("the CreateAgent span isn't a child of the Agent.Create span")
+	}
+	if getAgent.Parent.SpanID() != readOne.SpanContext.SpanID() {
+		t.Error("the GetAgent span isn't a child of the Agent.ReadOne span")
+	}
+	if spanAttribute(getAgent, "aws.request_id") == "" {
+		t.Error("the GetAgent span has no request ID")
+	}
+}
+
+func TestAgentSpans_NotFoundIsNotAnError(t *testing.T) {
+	exporter := withSpanRecorder(t)
+	server := fakebedrockagent.NewServer(t)
+	rm := newFakeResourceManager(t, server)
+
+	missing := &resource{ko: &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "missing", Namespace: "tracing"},
+		Status:     svcapitypes.AgentStatus{AgentID: aws.String("MISSING0001")},
+	}}
+	if _, err := rm.ReadOne(context.Background(), missing); err == nil {
+		t.Fatal("ReadOne() of a missing agent succeeded")
+	}
+	spans := exporter.GetSpans()
+	readOne, ok := findSpan(spans, "Agent.ReadOne")
+	if !ok {
+		t.Fatal("no Agent.ReadOne span")
+	}
+	if readOne.Status.Code == codes.Error {
+		t.Errorf("Agent.ReadOne status = %v, want an agent that isn't found not to be an error", readOne.Status)
+	}
+	getAgent, ok := findSpan(spans, "Bedrock Agent.GetAgent")
+	if !ok {
+		t.Fatal("no GetAgent span")
+	}
+	if getAgent.Status.Code != codes.Error {
+		t.Errorf("GetAgent status = %v, want the ResourceNotFoundException recorded", getAgent.Status)
+	}
+}
@@ -0,0 +1,740 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	ackcompare "github.com/aws-controllers-k8s/runtime/pkg/compare"
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	svcsdktypes "github.com/aws/aws-sdk-go-v2/service/bedrockagent/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/maintenance"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/observe"
+)
+
+var (
+	agentNameRegex                = regexp.MustCompile(`^([0-9a-zA-Z][_-]?){1,100}$`)
+	agentResourceRoleARNRegex     = regexp.MustCompile(`^arn:aws(-[^:]+)?:iam::([0-9]{12})?:role/.+$`)
+	customerEncryptionKeyARNRegex = regexp.MustCompile(`^arn:aws(|-cn|-us-gov):kms:[a-zA-Z0-9-]*:[0-9]{12}:key/[a-zA-Z0-9-]{36}$`)
+	foundationModelRegex          = regexp.MustCompile(`^(arn:aws(-[^:]{1,12})?:(bedrock|sagemaker):[a-z0-9-]{1,20}:([0-9]{12})?:([a-z-]+/)?)?([a-zA-Z0-9.-]{1,63}){0,2}(([:][a-z0-9-]{1,63}){0,2})?(/[a-z0-9]{1,12})?$`)
+	guardrailIdentifierRegex      = regexp.MustCompile(`^(([a-z0-9]+)|(arn:aws(-[^:]+)?:bedrock:[a-z0-9-]{1,20}:[0-9]{12}:guardrail/[a-z0-9]+))$`)
+	guardrailVersionRegex         = regexp.MustCompile(`^(([0-9]{1,8})|(DRAFT))$`)
+	knowledgeBaseIDRegex          = regexp.MustCompile(`^[0-9a-zA-Z]{10}$`)
+	lambdaARNRegex                = regexp.MustCompile(`^arn:(aws[a-zA-Z-]*)?:lambda:[a-z]{2}(-gov)?-[a-z]+-\d{1}:\d{12}:function:[a-zA-Z0-9-_\.]+(:(\$LATEST|[a-zA-Z0-9-_]+))?$`)
+)
+
+const (
+	minIdleSessionTTLInSeconds = 60
+	maxIdleSessionTTLInSeconds = 5400
+	minInstructionLength       = 40
+	maxInstructionLength       = 20000
+	maxDescriptionLength       = 200
+	maxStorageDays             = 365
+	maxPromptConfigurations    = 10
+	maxStopSequences           = 4
+	maxTopK                    = 500
+	maxMaximumLength           = 4096
+	maxActionGroups            = 20
+)
+
+var (
+	agentCollaborationValues = []string{
+		string(svcapitypes.AgentCollaboration_DISABLED),
+		string(svcapitypes.AgentCollaboration_SUPERVISOR),
+		string(svcapitypes.AgentCollaboration_SUPERVISOR_ROUTER),
+	}
+	orchestrationTypeValues = []string{
+		string(svcapitypes.OrchestrationType_DEFAULT),
+		string(svcapitypes.OrchestrationType_CUSTOM_ORCHESTRATION),
+	}
+	memoryTypeValues = []string{
+		string(svcapitypes.MemoryType_SESSION_SUMMARY),
+	}
+	promptTypeValues = []string{
+		string(svcapitypes.PromptType_PRE_PROCESSING),
+		string(svcapitypes.PromptType_ORCHESTRATION),
+		string(svcapitypes.PromptType_POST_PROCESSING),
+		string(svcapitypes.PromptType_KNOWLEDGE_BASE_RESPONSE_GENERATION),
+		string(svcapitypes.PromptType_MEMORY_SUMMARIZATION),
+	}
+	creationModeValues = []string{
+		string(svcapitypes.CreationMode_DEFAULT),
+		string(svcapitypes.CreationMode_OVERRIDDEN),
+	}
+	promptStateValues = []string{
+		string(svcapitypes.PromptState_ENABLED),
+		string(svcapitypes.PromptState_DISABLED),
+	}
+	actionGroupStateValues = []string{
+		string(svcapitypes.ActionGroupState_ENABLED),
+		string(svcapitypes.ActionGroupState_DISABLED),
+	}
+	knowledgeBaseStateValues = []string{
+		string(svcapitypes.KnowledgeBaseState_ENABLED),
+		string(svcapitypes.KnowledgeBaseState_DISABLED),
+	}
+	requireConfirmationValues = []string{
+		string(svcapitypes.RequireConfirmation_ENABLED),
+		string(svcapitypes.RequireConfirmation_DISABLED),
+	}
+	customControlMethodValues = []string{
+		string(svcapitypes.CustomControlMethod_RETURN_CONTROL),
+	}
+	actionGroupSignatureValues = []string{
+		string(svcsdktypes.ActionGroupSignatureAmazonUserinput),
+		string(svcsdktypes.ActionGroupSignatureAmazonCodeinterpreter),
+		string(svcsdktypes.ActionGroupSignatureAnthropicComputer),
+		string(svcsdktypes.ActionGroupSignatureAnthropicBash),
+		string(svcsdktypes.ActionGroupSignatureAnthropicTexteditor),
+	}
+	parameterTypeValues = []string{
+		string(svcsdktypes.TypeString),
+		string(svcsdktypes.TypeNumber),
+		string(svcsdktypes.TypeInteger),
+		string(svcsdktypes.TypeBoolean),
+		string(svcsdktypes.TypeArray),
+	}
+)
+
+// validateAgentSpec checks the supplied AgentSpec against the constraints
+// documented by the Bedrock Agent API and returns every violation found, so
+// that invalid specs can be rejected before they are sent to AWS.
+func validateAgentSpec(
+	spec *svcapitypes.AgentSpec,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+
+	if spec.AgentName == nil {
+		errs = append(errs, field.Required(fldPath.Child("agentName"), ""))
+	} else {
+		errs = append(errs, validatePattern(fldPath.Child("agentName"), *spec.AgentName, agentNameRegex)...)
+	}
+	if spec.AgentResourceRoleARN != nil && spec.AgentResourceRoleRef != nil {
+		errs = append(errs, field.Forbidden(
+			fldPath.Child("agentResourceRoleRef"),
+			"only one of agentResourceRoleARN or agentResourceRoleRef may be specified",
+		))
+	}
+	if spec.AgentResourceRoleARN != nil {
+		errs = append(errs, validatePattern(fldPath.Child("agentResourceRoleARN"), *spec.AgentResourceRoleARN, agentResourceRoleARNRegex)...)
+	}
+	if spec.CustomerEncryptionKeyARN != nil {
+		errs = append(errs, validatePattern(fldPath.Child("customerEncryptionKeyARN"), *spec.CustomerEncryptionKeyARN, customerEncryptionKeyARNRegex)...)
+	}
+	if spec.FoundationModel != nil {
+		errs = append(errs, validatePattern(fldPath.Child("foundationModel"), *spec.FoundationModel, foundationModelRegex)...)
+	}
+	if spec.AgentCollaboration != nil {
+		errs = append(errs, validateEnum(fldPath.Child("agentCollaboration"), *spec.AgentCollaboration, agentCollaborationValues)...)
+	}
+	if spec.Description != nil {
+		errs = append(errs, validateLength(fldPath.Child("description"), *spec.Description, 1, maxDescriptionLength)...)
+	}
+	if spec.Instruction != nil {
+		errs = append(errs, validateLength(fldPath.Child("instruction"), *spec.Instruction, minInstructionLength, maxInstructionLength)...)
+	}
+	if spec.IdleSessionTTLInSeconds != nil {
+		errs = append(errs, validateRange(fldPath.Child("idleSessionTTLInSeconds"), *spec.IdleSessionTTLInSeconds, minIdleSessionTTLInSeconds, maxIdleSessionTTLInSeconds)...)
+	}
+	errs = append(errs, validateOrchestration(spec, fldPath)...)
+	if spec.GuardrailConfiguration != nil {
+		errs = append(errs, validateGuardrailConfiguration(spec.GuardrailConfiguration, fldPath.Child("guardrailConfiguration"))...)
+	}
+	if spec.MemoryConfiguration != nil {
+		errs = append(errs, validateMemoryConfiguration(spec.MemoryConfiguration, fldPath.Child("memoryConfiguration"))...)
+	}
+	if spec.PromptOverrideConfiguration != nil {
+		errs = append(errs, validatePromptOverrideConfiguration(spec.PromptOverrideConfiguration, fldPath.Child("promptOverrideConfiguration"))...)
+	}
+	if spec.MaintenanceWindow != nil {
+		errs = append(errs, validateMaintenanceWindow(spec.MaintenanceWindow, fldPath.Child("maintenanceWindow"))...)
+	}
+	errs = append(errs, validateActionGroups(spec.ActionGroups, fldPath.Child("actionGroups"))...)
+	errs = append(errs, validateKnowledgeBases(spec.KnowledgeBases, fldPath.Child("knowledgeBases"))...)
+	return errs
+}
+
+// validateAgentAnnotations checks that the ACK ignore-field-drift annotation
+// only names fields of the Agent spec, that the ACK adoption-fields
+// annotation names the agentID, and that the observe.PolicyAnnotation names
+// a management policy.
+func validateAgentAnnotations(
+	annotations map[string]string,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	if policy := annotations[observe.PolicyAnnotation]; !observe.IsValidPolicy(policy) {
+		errs = append(errs, field.NotSupported(
+			fldPath.Key(observe.PolicyAnnotation), policy, []string{observe.PolicyFull, observe.PolicyObserve},
+		))
+	}
+	if value, ok := annotations[ackv1alpha1.AnnotationAdoptionFields]; ok {
+		// The runtime only adopts an agent by its ID. An agent is adopted by
+		// name with the Observe policy, see observe.go.
+		var fields map[string]string
+		if err := json.Unmarshal([]byte(value), &fields); err != nil {
+			errs = append(errs, field.Invalid(
+				fldPath.Key(ackv1alpha1.AnnotationAdoptionFields), value, "must be a JSON object of strings",
+			))
+		} else if fields["agentID"] == "" {
+			errs = append(errs, field.Required(
+				fldPath.Key(ackv1alpha1.AnnotationAdoptionFields),
+				fmt.Sprintf("agentID is required; to adopt an agent by name, set the %s annotation to %s instead",
+					observe.PolicyAnnotation, observe.PolicyObserve),
+			))
+		}
+	}
+	if value, ok := annotations[ackv1alpha1.AnnotationIgnoreFieldDrift]; ok {
+		var unknown []string
+		for _, path := range strings.Split(value, ",") {
+			if path = strings.TrimSpace(path); path != "" && !isAgentSpecPath(path) {
+				unknown = append(unknown, path)
+			}
+		}
+		if len(unknown) > 0 {
+			errs = append(errs, field.Invalid(
+				fldPath.Key(ackv1alpha1.AnnotationIgnoreFieldDrift), value,
+				fmt.Sprintf("unknown spec fields %s", strings.Join(unknown, ", ")),
+			))
+		}
+	}
+	return errs
+}
+
+// isAgentSpecPath returns true if the dotted JSON path, e.g.
+// "spec.guardrailConfiguration.guardrailVersion", names a field of the Agent
+// spec or a key of one of its maps. Paths can't go through lists, as the ACK
+// runtime doesn't index them.
+func isAgentSpecPath(path string) bool {
+	parts := strings.Split(path, ".")
+	if len(parts) < 2 || parts[0] != "spec" {
+		return false
+	}
+	t := reflect.TypeOf(svcapitypes.AgentSpec{})
+	for _, name := range parts[1:] {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch t.Kind() {
+		case reflect.Map:
+			t = t.Elem()
+			continue
+		case reflect.Struct:
+		default:
+			return false
+		}
+		found := false
+		for i := 0; i < t.NumField(); i++ {
+			tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if tag == name {
+				t, found = t.Field(i).Type, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// validateAgentSpecUpdate rejects changes to the Agent fields that cannot be
+// modified once set. Changing AgentName would rename a live agent and
+// changing CustomerEncryptionKeyARN would re-encrypt it, so both require a
+// new Agent resource instead.
+func validateAgentSpecUpdate(
+	oldSpec *svcapitypes.AgentSpec,
+	newSpec *svcapitypes.AgentSpec,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, validateImmutable(fldPath.Child("agentName"), oldSpec.AgentName, newSpec.AgentName)...)
+	errs = append(errs, validateImmutable(fldPath.Child("customerEncryptionKeyARN"), oldSpec.CustomerEncryptionKeyARN, newSpec.CustomerEncryptionKeyARN)...)
+	return errs
+}
+
+// validateOrchestration checks the OrchestrationType enum and its
+// relationship with CustomOrchestration: a custom orchestration executor is
+// required for CUSTOM_ORCHESTRATION and meaningless for any other type.
+func validateOrchestration(
+	spec *svcapitypes.AgentSpec,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	orchestrationType := string(svcapitypes.OrchestrationType_DEFAULT)
+	if spec.OrchestrationType != nil {
+		orchestrationType = *spec.OrchestrationType
+		errs = append(errs, validateEnum(fldPath.Child("orchestrationType"), orchestrationType, orchestrationTypeValues)...)
+	}
+
+	executorPath := fldPath.Child("customOrchestration", "executor")
+	hasExecutor := spec.CustomOrchestration != nil && spec.CustomOrchestration.Executor != nil
+	if orchestrationType == string(svcapitypes.OrchestrationType_CUSTOM_ORCHESTRATION) {
+		if !hasExecutor || spec.CustomOrchestration.Executor.Lambda == nil {
+			errs = append(errs, field.Required(
+				executorPath.Child("lambda"),
+				"customOrchestration.executor.lambda is required when orchestrationType is CUSTOM_ORCHESTRATION",
+			))
+		}
+	} else if hasExecutor {
+		errs = append(errs, field.Forbidden(
+			executorPath,
+			"customOrchestration.executor may only be set when orchestrationType is CUSTOM_ORCHESTRATION",
+		))
+	}
+	if hasExecutor && spec.CustomOrchestration.Executor.Lambda != nil {
+		errs = append(errs, validatePattern(executorPath.Child("lambda"), *spec.CustomOrchestration.Executor.Lambda, lambdaARNRegex)...)
+	}
+	return errs
+}
+
+// validateGuardrailConfiguration checks the guardrail identifier and version
+// patterns.
+func validateGuardrailConfiguration(
+	cfg *svcapitypes.GuardrailConfiguration,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	if cfg.GuardrailIdentifier != nil {
+		errs = append(errs, validatePattern(fldPath.Child("guardrailIdentifier"), *cfg.GuardrailIdentifier, guardrailIdentifierRegex)...)
+	}
+	if cfg.GuardrailVersion != nil {
+		errs = append(errs, validatePattern(fldPath.Child("guardrailVersion"), *cfg.GuardrailVersion, guardrailVersionRegex)...)
+	}
+	return errs
+}
+
+// validateMaintenanceWindow checks that the schedule is a standard cron
+// expression, the duration a positive Go duration and the time zone an IANA
+// one.
+func validateMaintenanceWindow(
+	w *svcapitypes.MaintenanceWindow,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	if w.Schedule == nil {
+		errs = append(errs, field.Required(fldPath.Child("schedule"), ""))
+	}
+	if w.Duration == nil {
+		errs = append(errs, field.Required(fldPath.Child("duration"), ""))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	var fieldErr *maintenance.FieldError
+	if _, err := parseMaintenanceWindow(w); errors.As(err, &fieldErr) {
+		errs = append(errs, field.Invalid(fldPath.Child(fieldErr.Field), fieldErr.Value, fieldErr.Err.Error()))
+	}
+	return errs
+}
+
+// validateMemoryConfiguration checks that exactly one known memory type is
+// enabled and that the storage and session settings are within bounds.
+func validateMemoryConfiguration(
+	cfg *svcapitypes.MemoryConfiguration,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	typesPath := fldPath.Child("enabledMemoryTypes")
+	switch len(cfg.EnabledMemoryTypes) {
+	case 0:
+		errs = append(errs, field.Required(typesPath, "exactly one memory type must be enabled"))
+	case 1:
+	default:
+		errs = append(errs, field.TooMany(typesPath, len(cfg.EnabledMemoryTypes), 1))
+	}
+	for i, memoryType := range cfg.EnabledMemoryTypes {
+		if memoryType == nil {
+			errs = append(errs, field.Required(typesPath.Index(i), ""))
+			continue
+		}
+		errs = append(errs, validateEnum(typesPath.Index(i), *memoryType, memoryTypeValues)...)
+	}
+	if cfg.StorageDays != nil {
+		errs = append(errs, validateRange(fldPath.Child("storageDays"), *cfg.StorageDays, 0, maxStorageDays)...)
+	}
+	if cfg.SessionSummaryConfiguration != nil && cfg.SessionSummaryConfiguration.MaxRecentSessions != nil {
+		maxRecentSessions := *cfg.SessionSummaryConfiguration.MaxRecentSessions
+		if maxRecentSessions < 1 {
+			errs = append(errs, field.Invalid(
+				fldPath.Child("sessionSummaryConfiguration", "maxRecentSessions"),
+				maxRecentSessions, "must be greater than or equal to 1",
+			))
+		}
+	}
+	return errs
+}
+
+// validatePromptOverrideConfiguration checks the override Lambda ARN and each
+// prompt configuration. A prompt type may only be configured once, and
+// OVERRIDDEN prompts must carry the template that overrides the default.
+func validatePromptOverrideConfiguration(
+	cfg *svcapitypes.PromptOverrideConfiguration,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	if cfg.OverrideLambda != nil {
+		errs = append(errs, validatePattern(fldPath.Child("overrideLambda"), *cfg.OverrideLambda, lambdaARNRegex)...)
+	}
+
+	configsPath := fldPath.Child("promptConfigurations")
+	if len(cfg.PromptConfigurations) > maxPromptConfigurations {
+		errs = append(errs, field.TooMany(configsPath, len(cfg.PromptConfigurations), maxPromptConfigurations))
+	}
+	seenPromptTypes := map[string]bool{}
+	for i, promptConfig := range cfg.PromptConfigurations {
+		idxPath := configsPath.Index(i)
+		if promptConfig == nil {
+			errs = append(errs, field.Required(idxPath, ""))
+			continue
+		}
+		if promptConfig.PromptType != nil {
+			promptType := *promptConfig.PromptType
+			errs = append(errs, validateEnum(idxPath.Child("promptType"), promptType, promptTypeValues)...)
+			if seenPromptTypes[promptType] {
+				errs = append(errs, field.Duplicate(idxPath.Child("promptType"), promptType))
+			}
+			seenPromptTypes[promptType] = true
+		}
+		if promptConfig.PromptCreationMode != nil {
+			errs = append(errs, validateEnum(idxPath.Child("promptCreationMode"), *promptConfig.PromptCreationMode, creationModeValues)...)
+			if *promptConfig.PromptCreationMode == string(svcapitypes.CreationMode_OVERRIDDEN) &&
+				promptConfig.BasePromptTemplate == nil {
+				errs = append(errs, field.Required(
+					idxPath.Child("basePromptTemplate"),
+					"basePromptTemplate is required when promptCreationMode is OVERRIDDEN",
+				))
+			}
+		}
+		if promptConfig.ParserMode != nil {
+			errs = append(errs, validateEnum(idxPath.Child("parserMode"), *promptConfig.ParserMode, creationModeValues)...)
+		}
+		if promptConfig.PromptState != nil {
+			errs = append(errs, validateEnum(idxPath.Child("promptState"), *promptConfig.PromptState, promptStateValues)...)
+		}
+		if promptConfig.InferenceConfiguration != nil {
+			errs = append(errs, validateInferenceConfiguration(promptConfig.InferenceConfiguration, idxPath.Child("inferenceConfiguration"))...)
+		}
+	}
+	return errs
+}
+
+// validateActionGroups checks the inline action groups. Names must be unique
+// since action groups are matched by name, and each action group needs either
+// a built-in signature or an executor with exactly one schema.
+func validateActionGroups(
+	actionGroups []*svcapitypes.InlineActionGroup,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	if len(actionGroups) > maxActionGroups {
+		errs = append(errs, field.TooMany(fldPath, len(actionGroups), maxActionGroups))
+	}
+	seenNames := map[string]bool{}
+	for i, actionGroup := range actionGroups {
+		idxPath := fldPath.Index(i)
+		if actionGroup == nil {
+			errs = append(errs, field.Required(idxPath, ""))
+			continue
+		}
+		if actionGroup.ActionGroupName == nil {
+			errs = append(errs, field.Required(idxPath.Child("actionGroupName"), ""))
+		} else {
+			name := *actionGroup.ActionGroupName
+			errs = append(errs, validatePattern(idxPath.Child("actionGroupName"), name, agentNameRegex)...)
+			if seenNames[name] {
+				errs = append(errs, field.Duplicate(idxPath.Child("actionGroupName"), name))
+			}
+			seenNames[name] = true
+		}
+		if actionGroup.ActionGroupState != nil {
+			errs = append(errs, validateEnum(idxPath.Child("actionGroupState"), *actionGroup.ActionGroupState, actionGroupStateValues)...)
+		}
+		if actionGroup.Description != nil {
+			errs = append(errs, validateLength(idxPath.Child("description"), *actionGroup.Description, 1, maxDescriptionLength)...)
+		}
+		if actionGroup.ParentActionGroupSignature != nil {
+			errs = append(errs, validateEnum(
+				idxPath.Child("parentActionGroupSignature"),
+				*actionGroup.ParentActionGroupSignature,
+				actionGroupSignatureValues,
+			)...)
+			if actionGroup.ActionGroupExecutor != nil {
+				errs = append(errs, field.Forbidden(idxPath.Child("actionGroupExecutor"), "must not be set together with parentActionGroupSignature"))
+			}
+			if actionGroup.APISchema != nil {
+				errs = append(errs, field.Forbidden(idxPath.Child("apiSchema"), "must not be set together with parentActionGroupSignature"))
+			}
+			if actionGroup.FunctionSchema != nil {
+				errs = append(errs, field.Forbidden(idxPath.Child("functionSchema"), "must not be set together with parentActionGroupSignature"))
+			}
+			continue
+		}
+		errs = append(errs, validateActionGroupExecutor(actionGroup.ActionGroupExecutor, idxPath.Child("actionGroupExecutor"))...)
+		switch {
+		case actionGroup.APISchema != nil && actionGroup.FunctionSchema != nil:
+			errs = append(errs, field.Forbidden(idxPath.Child("functionSchema"), "only one of apiSchema or functionSchema may be specified"))
+		case actionGroup.APISchema == nil && actionGroup.FunctionSchema == nil:
+			errs = append(errs, field.Required(idxPath.Child("apiSchema"), "one of apiSchema or functionSchema is required"))
+		case actionGroup.APISchema != nil:
+			errs = append(errs, validateAPISchema(actionGroup.APISchema, idxPath.Child("apiSchema"))...)
+		default:
+			errs = append(errs, validateFunctionSchema(actionGroup.FunctionSchema, idxPath.Child("functionSchema"))...)
+		}
+	}
+	return errs
+}
+
+func validateActionGroupExecutor(
+	executor *svcapitypes.InlineActionGroupExecutor,
+	fldPath *field.Path,
+) field.ErrorList {
+	if executor == nil {
+		return field.ErrorList{field.Required(fldPath, "actionGroupExecutor is required unless parentActionGroupSignature is set")}
+	}
+	var errs field.ErrorList
+	switch {
+	case executor.Lambda != nil && executor.CustomControl != nil:
+		errs = append(errs, field.Forbidden(fldPath.Child("customControl"), "only one of lambda or customControl may be specified"))
+	case executor.Lambda != nil:
+		errs = append(errs, validatePattern(fldPath.Child("lambda"), *executor.Lambda, lambdaARNRegex)...)
+	case executor.CustomControl != nil:
+		errs = append(errs, validateEnum(fldPath.Child("customControl"), *executor.CustomControl, customControlMethodValues)...)
+	default:
+		errs = append(errs, field.Required(fldPath.Child("lambda"), "one of lambda or customControl is required"))
+	}
+	return errs
+}
+
+func validateAPISchema(
+	schema *svcapitypes.InlineAPISchema,
+	fldPath *field.Path,
+) field.ErrorList {
+	switch {
+	case schema.Payload != nil && schema.S3 != nil:
+		return field.ErrorList{field.Forbidden(fldPath.Child("s3"), "only one of payload or s3 may be specified")}
+	case schema.Payload == nil && schema.S3 == nil:
+		return field.ErrorList{field.Required(fldPath.Child("payload"), "one of payload or s3 is required")}
+	}
+	return nil
+}
+
+func validateFunctionSchema(
+	schema *svcapitypes.InlineFunctionSchema,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	functionsPath := fldPath.Child("functions")
+	if len(schema.Functions) == 0 {
+		errs = append(errs, field.Required(functionsPath, ""))
+	}
+	seenNames := map[string]bool{}
+	for i, function := range schema.Functions {
+		idxPath := functionsPath.Index(i)
+		if function == nil {
+			errs = append(errs, field.Required(idxPath, ""))
+			continue
+		}
+		if function.Name == nil {
+			errs = append(errs, field.Required(idxPath.Child("name"), ""))
+		} else {
+			if seenNames[*function.Name] {
+				errs = append(errs, field.Duplicate(idxPath.Child("name"), *function.Name))
+			}
+			seenNames[*function.Name] = true
+		}
+		if function.RequireConfirmation != nil {
+			errs = append(errs, validateEnum(idxPath.Child("requireConfirmation"), *function.RequireConfirmation, requireConfirmationValues)...)
+		}
+		for key, parameter := range function.Parameters {
+			paramPath := idxPath.Child("parameters").Key(key)
+			if parameter == nil || parameter.Type == nil {
+				errs = append(errs, field.Required(paramPath.Child("type"), ""))
+				continue
+			}
+			errs = append(errs, validateEnum(paramPath.Child("type"), *parameter.Type, parameterTypeValues)...)
+		}
+	}
+	return errs
+}
+
+// validateKnowledgeBases checks the inline knowledge base associations.
+// Knowledge bases are matched by ID, so IDs must be unique.
+func validateKnowledgeBases(
+	knowledgeBases []*svcapitypes.InlineKnowledgeBase,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	seenIDs := map[string]bool{}
+	for i, knowledgeBase := range knowledgeBases {
+		idxPath := fldPath.Index(i)
+		if knowledgeBase == nil {
+			errs = append(errs, field.Required(idxPath, ""))
+			continue
+		}
+		if knowledgeBase.KnowledgeBaseID == nil {
+			errs = append(errs, field.Required(idxPath.Child("knowledgeBaseID"), ""))
+		} else {
+			id := *knowledgeBase.KnowledgeBaseID
+			errs = append(errs, validatePattern(idxPath.Child("knowledgeBaseID"), id, knowledgeBaseIDRegex)...)
+			if seenIDs[id] {
+				errs = append(errs, field.Duplicate(idxPath.Child("knowledgeBaseID"), id))
+			}
+			seenIDs[id] = true
+		}
+		if knowledgeBase.Description == nil {
+			errs = append(errs, field.Required(idxPath.Child("description"), ""))
+		} else {
+			errs = append(errs, validateLength(idxPath.Child("description"), *knowledgeBase.Description, 1, maxDescriptionLength)...)
+		}
+		if knowledgeBase.KnowledgeBaseState != nil {
+			errs = append(errs, validateEnum(idxPath.Child("knowledgeBaseState"), *knowledgeBase.KnowledgeBaseState, knowledgeBaseStateValues)...)
+		}
+	}
+	return errs
+}
+
+// validateInferenceConfiguration checks the inference parameters of a prompt
+// configuration against their documented bounds.
+func validateInferenceConfiguration(
+	cfg *svcapitypes.InferenceConfiguration,
+	fldPath *field.Path,
+) field.ErrorList {
+	var errs field.ErrorList
+	if cfg.Temperature != nil && (*cfg.Temperature < 0 || *cfg.Temperature > 1) {
+		errs = append(errs, field.Invalid(fldPath.Child("temperature"), *cfg.Temperature, "must be between 0 and 1"))
+	}
+	if cfg.TopP != nil && (*cfg.TopP < 0 || *cfg.TopP > 1) {
+		errs = append(errs, field.Invalid(fldPath.Child("topP"), *cfg.TopP, "must be between 0 and 1"))
+	}
+	if cfg.TopK != nil {
+		errs = append(errs, validateRange(fldPath.Child("topK"), *cfg.TopK, 0, maxTopK)...)
+	}
+	if cfg.MaximumLength != nil {
+		errs = append(errs, validateRange(fldPath.Child("maximumLength"), *cfg.MaximumLength, 0, maxMaximumLength)...)
+	}
+	if len(cfg.StopSequences) > maxStopSequences {
+		errs = append(errs, field.TooMany(fldPath.Child("stopSequences"), len(cfg.StopSequences), maxStopSequences))
+	}
+	return errs
+}
+
+// validatePattern returns an error if value does not match the regex.
+func validatePattern(
+	fldPath *field.Path,
+	value string,
+	regex *regexp.Regexp,
+) field.ErrorList {
+	if !regex.MatchString(value) {
+		return field.ErrorList{field.Invalid(fldPath, value, "must match the pattern "+regex.String())}
+	}
+	return nil
+}
+
+// validateEnum returns an error if value is not one of the supported values.
+func validateEnum(
+	fldPath *field.Path,
+	value string,
+	supported []string,
+) field.ErrorList {
+	for _, s := range supported {
+		if value == s {
+			return nil
+		}
+	}
+	return field.ErrorList{field.NotSupported(fldPath, value, supported)}
+}
+
+// validateLength returns an error if the length of value is outside of
+// [min, max].
+func validateLength(
+	fldPath *field.Path,
+	value string,
+	min int,
+	max int,
+) field.ErrorList {
+	if len(value) < min {
+		return field.ErrorList{field.Invalid(fldPath, len(value), fmt.Sprintf("must be at least %d characters long", min))}
+	}
+	if len(value) > max {
+		return field.ErrorList{field.TooLong(fldPath, "", max)}
+	}
+	return nil
+}
+
+// validateRange returns an error if value is outside of [min, max].
+func validateRange(
+	fldPath *field.Path,
+	value int64,
+	min int64,
+	max int64,
+) field.ErrorList {
+	if value < min || value > max {
+		return field.ErrorList{field.Invalid(
+			fldPath, value,
+			fmt.Sprintf("must be between %d and %d", min, max),
+		)}
+	}
+	return nil
+}
+
+// checkImmutableFieldChanges returns a terminal error naming the fields of
+// validateAgentSpecUpdate that differ between the desired and the latest
+// agent, e.g. after the agent was renamed in the console. sdkUpdate checks
+// them once a paused agent is left alone, which is why the fields aren't
+// marked is_immutable in generator.yaml.
+func checkImmutableFieldChanges(delta *ackcompare.Delta) error {
+	var fields []string
+	for _, name := range []string{"AgentName", "CustomerEncryptionKeyARN"} {
+		if delta.DifferentAt("Spec." + name) {
+			fields = append(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return ackerr.NewTerminalError(fmt.Errorf(
+		"immutable Spec fields have been modified: %s", strings.Join(fields, ","),
+	))
+}
+
+// validateImmutable returns an error if a field that was already set has been
+// changed or removed.
+func validateImmutable(
+	fldPath *field.Path,
+	oldValue *string,
+	newValue *string,
+) field.ErrorList {
+	if oldValue == nil {
+		return nil
+	}
+	if newValue == nil || *newValue != *oldValue {
+		return field.ErrorList{field.Forbidden(
+			fldPath,
+			fmt.Sprintf("field is immutable once set (was %q); create a new Agent to change it", *oldValue),
+		)}
+	}
+	return nil
+}
@@ -0,0 +1,517 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/observe"
+)
+
+func validAgentSpec() svcapitypes.AgentSpec {
+	return svcapitypes.AgentSpec{
+		AgentName:               aws.String("my-agent"),
+		AgentResourceRoleARN:    aws.String("arn:aws:iam::123456789012:role/agent-role"),
+		FoundationModel:         aws.String("anthropic.claude-3-haiku-20240307-v1:0"),
+		Instruction:             aws.String(strings.Repeat("You are a helpful agent. ", 4)),
+		IdleSessionTTLInSeconds: aws.Int64(600),
+		OrchestrationType:       aws.String("DEFAULT"),
+	}
+}
+
+func TestValidateAgentSpec(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(spec *svcapitypes.AgentSpec)
+		wantFields []string
+	}{
+		{
+			name:   "valid spec",
+			mutate: func(spec *svcapitypes.AgentSpec) {},
+		},
+		{
+			name: "invalid agent name",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.AgentName = aws.String("my agent!")
+			},
+			wantFields: []string{"spec.agentName"},
+		},
+		{
+			name: "missing agent name",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.AgentName = nil
+			},
+			wantFields: []string{"spec.agentName"},
+		},
+		{
+			name: "idle session TTL out of range",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.IdleSessionTTLInSeconds = aws.Int64(30)
+			},
+			wantFields: []string{"spec.idleSessionTTLInSeconds"},
+		},
+		{
+			name: "unknown orchestration type",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.OrchestrationType = aws.String("MAGIC")
+			},
+			wantFields: []string{"spec.orchestrationType"},
+		},
+		{
+			name: "custom orchestration without executor",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.OrchestrationType = aws.String("CUSTOM_ORCHESTRATION")
+			},
+			wantFields: []string{"spec.customOrchestration.executor.lambda"},
+		},
+		{
+			name: "custom orchestration with executor",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.OrchestrationType = aws.String("CUSTOM_ORCHESTRATION")
+				spec.CustomOrchestration = &svcapitypes.CustomOrchestration{
+					Executor: &svcapitypes.OrchestrationExecutor{
+						Lambda: aws.String("arn:aws:lambda:us-west-2:123456789012:function:orchestrator"),
+					},
+				}
+			},
+		},
+		{
+			name: "executor without custom orchestration",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.CustomOrchestration = &svcapitypes.CustomOrchestration{
+					Executor: &svcapitypes.OrchestrationExecutor{
+						Lambda: aws.String("arn:aws:lambda:us-west-2:123456789012:function:orchestrator"),
+					},
+				}
+			},
+			wantFields: []string{"spec.customOrchestration.executor"},
+		},
+		{
+			name: "memory storage days out of bounds",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.MemoryConfiguration = &svcapitypes.MemoryConfiguration{
+					EnabledMemoryTypes: []*string{aws.String("SESSION_SUMMARY")},
+					StorageDays:        aws.Int64(400),
+				}
+			},
+			wantFields: []string{"spec.memoryConfiguration.storageDays"},
+		},
+		{
+			name: "memory without enabled types",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.MemoryConfiguration = &svcapitypes.MemoryConfiguration{
+					StorageDays: aws.Int64(30),
+				}
+			},
+			wantFields: []string{"spec.memoryConfiguration.enabledMemoryTypes"},
+		},
+		{
+			name: "valid maintenance window",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.MaintenanceWindow = &svcapitypes.MaintenanceWindow{
+					Schedule: aws.String("0 2 * * SAT"),
+					Duration: aws.String("2h"),
+					TimeZone: aws.String("UTC"),
+				}
+			},
+		},
+		{
+			name: "invalid maintenance window",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.MaintenanceWindow = &svcapitypes.MaintenanceWindow{
+					Schedule: aws.String("every saturday"),
+				}
+			},
+			wantFields: []string{"spec.maintenanceWindow.duration"},
+		},
+		{
+			name: "maintenance window with invalid schedule",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.MaintenanceWindow = &svcapitypes.MaintenanceWindow{
+					Schedule: aws.String("every saturday"),
+					Duration: aws.String("2h"),
+				}
+			},
+			wantFields: []string{"spec.maintenanceWindow.schedule"},
+		},
+		{
+			name: "duplicate and overridden prompt without template",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.PromptOverrideConfiguration = &svcapitypes.PromptOverrideConfiguration{
+					PromptConfigurations: []*svcapitypes.PromptConfiguration{
+						{
+							PromptType:         aws.String("ORCHESTRATION"),
+							PromptCreationMode: aws.String("DEFAULT"),
+						},
+						{
+							PromptType:         aws.String("ORCHESTRATION"),
+							PromptCreationMode: aws.String("OVERRIDDEN"),
+						},
+					},
+				}
+			},
+			wantFields: []string{
+				"spec.promptOverrideConfiguration.promptConfigurations[1].basePromptTemplate",
+				"spec.promptOverrideConfiguration.promptConfigurations[1].promptType",
+			},
+		},
+		{
+			name: "valid action groups and knowledge bases",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.ActionGroups = []*svcapitypes.InlineActionGroup{
+					{
+						ActionGroupName:            aws.String("user-input"),
+						ParentActionGroupSignature: aws.String("AMAZON.UserInput"),
+					},
+					{
+						ActionGroupName: aws.String("weather"),
+						ActionGroupExecutor: &svcapitypes.InlineActionGroupExecutor{
+							CustomControl: aws.String("RETURN_CONTROL"),
+						},
+						FunctionSchema: &svcapitypes.InlineFunctionSchema{
+							Functions: []*svcapitypes.InlineFunction{
+								{
+									Name: aws.String("get-forecast"),
+									Parameters: map[string]*svcapitypes.InlineParameterDetail{
+										"city": {Type: aws.String("string"), Required: aws.Bool(true)},
+									},
+								},
+							},
+						},
+					},
+				}
+				spec.KnowledgeBases = []*svcapitypes.InlineKnowledgeBase{
+					{
+						KnowledgeBaseID: aws.String("ABCDE12345"),
+						Description:     aws.String("Product documentation"),
+					},
+				}
+			},
+		},
+		{
+			name: "invalid action groups",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.ActionGroups = []*svcapitypes.InlineActionGroup{
+					{
+						ActionGroupName:            aws.String("user-input"),
+						ParentActionGroupSignature: aws.String("AMAZON.UserInput"),
+						ActionGroupExecutor: &svcapitypes.InlineActionGroupExecutor{
+							CustomControl: aws.String("RETURN_CONTROL"),
+						},
+					},
+					{
+						ActionGroupName: aws.String("user-input"),
+						ActionGroupExecutor: &svcapitypes.InlineActionGroupExecutor{
+							Lambda: aws.String("arn:aws:lambda:us-west-2:123456789012:function:actions"),
+						},
+						APISchema:      &svcapitypes.InlineAPISchema{Payload: aws.String("openapi: 3.0.0")},
+						FunctionSchema: &svcapitypes.InlineFunctionSchema{},
+					},
+					{
+						ActionGroupName: aws.String("no-executor"),
+						APISchema:       &svcapitypes.InlineAPISchema{},
+					},
+				}
+			},
+			wantFields: []string{
+				"spec.actionGroups[0].actionGroupExecutor",
+				"spec.actionGroups[1].actionGroupName",
+				"spec.actionGroups[1].functionSchema",
+				"spec.actionGroups[2].actionGroupExecutor",
+				"spec.actionGroups[2].apiSchema.payload",
+			},
+		},
+		{
+			name: "invalid function schema",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.ActionGroups = []*svcapitypes.InlineActionGroup{
+					{
+						ActionGroupName: aws.String("weather"),
+						ActionGroupExecutor: &svcapitypes.InlineActionGroupExecutor{
+							CustomControl: aws.String("RETURN_CONTROL"),
+						},
+						FunctionSchema: &svcapitypes.InlineFunctionSchema{
+							Functions: []*svcapitypes.InlineFunction{
+								{
+									Name:                aws.String("get-forecast"),
+									RequireConfirmation: aws.String("MAYBE"),
+									Parameters: map[string]*svcapitypes.InlineParameterDetail{
+										"city": {Type: aws.String("object")},
+									},
+								},
+								{Name: aws.String("get-forecast")},
+							},
+						},
+					},
+				}
+			},
+			wantFields: []string{
+				"spec.actionGroups[0].functionSchema.functions[0].parameters[city].type",
+				"spec.actionGroups[0].functionSchema.functions[0].requireConfirmation",
+				"spec.actionGroups[0].functionSchema.functions[1].name",
+			},
+		},
+		{
+			name: "invalid knowledge bases",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.KnowledgeBases = []*svcapitypes.InlineKnowledgeBase{
+					{
+						KnowledgeBaseID:    aws.String("ABCDE12345"),
+						Description:        aws.String("Product documentation"),
+						KnowledgeBaseState: aws.String("ON"),
+					},
+					{
+						KnowledgeBaseID: aws.String("ABCDE12345"),
+					},
+					{
+						KnowledgeBaseID: aws.String("kb-1"),
+						Description:     aws.String("Support tickets"),
+					},
+				}
+			},
+			wantFields: []string{
+				"spec.knowledgeBases[0].knowledgeBaseState",
+				"spec.knowledgeBases[1].description",
+				"spec.knowledgeBases[1].knowledgeBaseID",
+				"spec.knowledgeBases[2].knowledgeBaseID",
+			},
+		},
+		{
+			name: "all errors are reported",
+			mutate: func(spec *svcapitypes.AgentSpec) {
+				spec.AgentName = aws.String("-")
+				spec.IdleSessionTTLInSeconds = aws.Int64(10000)
+				spec.Instruction = aws.String("too short")
+				spec.CustomerEncryptionKeyARN = aws.String("not-an-arn")
+			},
+			wantFields: []string{
+				"spec.agentName",
+				"spec.customerEncryptionKeyARN",
+				"spec.idleSessionTTLInSeconds",
+				"spec.instruction",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := validAgentSpec()
+			tt.mutate(&spec)
+			errs := validateAgentSpec(&spec, field.NewPath("spec"))
+			gotFields := []string{}
+			for _, err := range errs {
+				gotFields = append(gotFields, err.Field)
+			}
+			sort.Strings(gotFields)
+			want := tt.wantFields
+			if want == nil {
+				want = []string{}
+			}
+			if strings.Join(gotFields, ",") != strings.Join(want, ",") {
+				t.Errorf("validateAgentSpec() fields = %v, want %v (errors: %v)", gotFields, want, errs)
+			}
+		})
+	}
+}
+
+func TestAgentValidator_ValidateCreate(t *testing.T) {
+	v := &agentValidator{}
+	ko := &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-agent"},
+		Spec:       validAgentSpec(),
+	}
+	if _, err := v.ValidateCreate(context.Background(), ko); err != nil {
+		t.Fatalf("ValidateCreate() unexpected error = %v", err)
+	}
+
+	ko.Spec.AgentName = aws.String("bad name")
+	ko.Spec.IdleSessionTTLInSeconds = aws.Int64(1)
+	_, err := v.ValidateCreate(context.Background(), ko)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("ValidateCreate() error = %v, want Invalid", err)
+	}
+	statusErr := err.(*apierrors.StatusError)
+	if len(statusErr.ErrStatus.Details.Causes) != 2 {
+		t.Errorf("ValidateCreate() causes = %v, want 2", statusErr.ErrStatus.Details.Causes)
+	}
+}
+
+func TestValidateAgentAnnotations(t *testing.T) {
+	fldPath := field.NewPath("metadata", "annotations")
+	ignored := map[string]string{
+		ackv1alpha1.AnnotationIgnoreFieldDrift: "spec.instruction, spec.guardrailConfiguration.guardrailVersion, spec.tags.team",
+	}
+	if errs := validateAgentAnnotations(ignored, fldPath); len(errs) != 0 {
+		t.Errorf("validateAgentAnnotations() = %v, want no error for spec fields", errs)
+	}
+	ignored[ackv1alpha1.AnnotationIgnoreFieldDrift] = "spec.instruction,spec.Description,instruction,spec.actionGroups.actionGroupName,spec.instruction.value"
+	errs := validateAgentAnnotations(ignored, fldPath)
+	if len(errs) != 1 || !strings.Contains(errs[0].Detail, "spec.Description, instruction, spec.actionGroups.actionGroupName, spec.instruction.value") {
+		t.Errorf("validateAgentAnnotations() = %v, want the unknown fields", errs)
+	}
+	if errs := validateAgentAnnotations(map[string]string{observe.PolicyAnnotation: "observe"}, fldPath); len(errs) != 0 {
+		t.Errorf("validateAgentAnnotations() = %v, want no error for the Observe policy", errs)
+	}
+	if errs := validateAgentAnnotations(map[string]string{observe.PolicyAnnotation: "ReadOnly"}, fldPath); len(errs) != 1 {
+		t.Errorf("validateAgentAnnotations() = %v, want an unsupported policy", errs)
+	}
+	for value, want := range map[string]int{
+		`{"agentID": "ABCDEFGHIJ"}`:      0,
+		`{"agentName": "support-agent"}`: 1,
+		`agentID=ABCDEFGHIJ`:             1,
+	} {
+		if errs := validateAgentAnnotations(map[string]string{ackv1alpha1.AnnotationAdoptionFields: value}, fldPath); len(errs) != want {
+			t.Errorf("validateAgentAnnotations(%s) = %v, want %d errors", value, errs, want)
+		}
+	}
+}
+
+func TestValidateAgentSpecUpdate(t *testing.T) {
+	kmsKey := "arn:aws:kms:us-west-2:123456789012:key/11111111-2222-3333-4444-555555555555"
+	otherKMSKey := "arn:aws:kms:us-west-2:123456789012:key/66666666-7777-8888-9999-000000000000"
+	tests := []struct {
+		name       string
+		mutateOld  func(spec *svcapitypes.AgentSpec)
+		mutateNew  func(spec *svcapitypes.AgentSpec)
+		wantFields []string
+	}{
+		{
+			name:      "no changes",
+			mutateOld: func(spec *svcapitypes.AgentSpec) {},
+			mutateNew: func(spec *svcapitypes.AgentSpec) {},
+		},
+		{
+			name:      "mutable field changed",
+			mutateOld: func(spec *svcapitypes.AgentSpec) {},
+			mutateNew: func(spec *svcapitypes.AgentSpec) {
+				spec.IdleSessionTTLInSeconds = aws.Int64(900)
+			},
+		},
+		{
+			name:      "agent name changed",
+			mutateOld: func(spec *svcapitypes.AgentSpec) {},
+			mutateNew: func(spec *svcapitypes.AgentSpec) {
+				spec.AgentName = aws.String("renamed-agent")
+			},
+			wantFields: []string{"spec.agentName"},
+		},
+		{
+			name:      "encryption key set for the first time",
+			mutateOld: func(spec *svcapitypes.AgentSpec) {},
+			mutateNew: func(spec *svcapitypes.AgentSpec) {
+				spec.CustomerEncryptionKeyARN = aws.String(kmsKey)
+			},
+		},
+		{
+			name: "encryption key changed",
+			mutateOld: func(spec *svcapitypes.AgentSpec) {
+				spec.CustomerEncryptionKeyARN = aws.String(kmsKey)
+			},
+			mutateNew: func(spec *svcapitypes.AgentSpec) {
+				spec.CustomerEncryptionKeyARN = aws.String(otherKMSKey)
+			},
+			wantFields: []string{"spec.customerEncryptionKeyARN"},
+		},
+		{
+			name: "encryption key removed",
+			mutateOld: func(spec *svcapitypes.AgentSpec) {
+				spec.CustomerEncryptionKeyARN = aws.String(kmsKey)
+			},
+			mutateNew:  func(spec *svcapitypes.AgentSpec) {},
+			wantFields: []string{"spec.customerEncryptionKeyARN"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldSpec := validAgentSpec()
+			tt.mutateOld(&oldSpec)
+			newSpec := validAgentSpec()
+			tt.mutateNew(&newSpec)
+			errs := validateAgentSpecUpdate(&oldSpec, &newSpec, field.NewPath("spec"))
+			gotFields := []string{}
+			for _, err := range errs {
+				gotFields = append(gotFields, err.Field)
+			}
+			want := tt.wantFields
+			if want == nil {
+				want = []string{}
+			}
+			if strings.Join(gotFields, ",") != strings.Join(want, ",") {
+				t.Errorf("validateAgentSpecUpdate() fields = %v, want %v (errors: %v)", gotFields, want, errs)
+			}
+		})
+	}
+}
+
+func TestAgentValidator_ValidateUpdate(t *testing.T) {
+	v := &agentValidator{}
+	oldObj := &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-agent"},
+		Spec:       validAgentSpec(),
+	}
+	newObj := oldObj.DeepCopy()
+	newObj.Spec.AgentName = aws.String("renamed-agent")
+	_, err := v.ValidateUpdate(context.Background(), oldObj, newObj)
+	if !apierrors.IsInvalid(err) {
+		t.Fatalf("ValidateUpdate() error = %v, want Invalid", err)
+	}
+	if !strings.Contains(err.Error(), "immutable") {
+		t.Errorf("ValidateUpdate() error = %v, want immutable field message", err)
+	}
+
+	// The spec of an observed Agent follows the renames made in AWS.
+	oldObj.Annotations = map[string]string{observe.PolicyAnnotation: observe.PolicyObserve}
+	newObj.Annotations = oldObj.Annotations
+	if _, err := v.ValidateUpdate(context.Background(), oldObj, newObj); err != nil {
+		t.Errorf("ValidateUpdate() of an observed Agent error = %v, want none", err)
+	}
+}
+
+func TestAgentValidator_ValidateUpdate_UnchangedSpec(t *testing.T) {
+	v := &agentValidator{}
+	// The Agent was admitted before its instruction became too short.
+	oldObj := &svcapitypes.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-agent"},
+		Spec:       validAgentSpec(),
+	}
+	oldObj.Spec.Instruction = aws.String("too short")
+
+	newObj := oldObj.DeepCopy()
+	newObj.Finalizers = []string{"finalizers.bedrockagent.services.k8s.aws/Agent"}
+	newObj.Status.AgentStatus = aws.String("PREPARED")
+	if _, err := v.ValidateUpdate(context.Background(), oldObj, newObj); err != nil {
+		t.Errorf("ValidateUpdate() error = %v, want none for an unchanged spec", err)
+	}
+
+	newObj.Spec.Description = aws.String("changed")
+	if _, err := v.ValidateUpdate(context.Background(), oldObj, newObj); !apierrors.IsInvalid(err) {
+		t.Errorf("ValidateUpdate() error = %v, want Invalid for a changed spec", err)
+	}
+
+	now := metav1.Now()
+	newObj.DeletionTimestamp = &now
+	newObj.Finalizers = nil
+	if _, err := v.ValidateUpdate(context.Background(), oldObj, newObj); err != nil {
+		t.Errorf("ValidateUpdate() error = %v, want none for a deleted Agent", err)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	ackrtwebhook "github.com/aws-controllers-k8s/runtime/pkg/webhook"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrlrt "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+	svcapitypesv1beta1 "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1beta1"
+	"github.com/aws-controllers-k8s/bedrockagent-controller/pkg/resource/observe"
+)
+
+// +kubebuilder:webhook:path=/mutate-bedrockagent-services-k8s-aws-v1alpha1-agent,mutating=true,failurePolicy=fail,sideEffects=None,groups=bedrockagent.services.k8s.aws,resources=agents,verbs=create;update,versions=v1alpha1,name=magent.bedrockagent.services.k8s.aws,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-bedrockagent-services-k8s-aws-v1alpha1-agent,mutating=false,failurePolicy=fail,sideEffects=None,groups=bedrockagent.services.k8s.aws,resources=agents,verbs=create;update,versions=v1alpha1,name=vagent.bedrockagent.services.k8s.aws,admissionReviewVersions=v1
+
+var agentDefaultingWebhook = ackrtwebhook.New(
+	"v1alpha1",
+	"Agent",
+	"defaulting",
+	func(mgr ctrlrt.Manager) error {
+		return ctrlrt.NewWebhookManagedBy(mgr, &svcapitypes.Agent{}).
+			WithDefaulter(&agentDefaulter{}).
+			Complete()
+	},
+)
+
+// agentConversionWebhook serves the /convert endpoint for Agent. v1alpha1 is
+// the hub; the v1beta1 Agent type implements conversion to and from it, and
+// is added to the scheme of the manager for the webhook to find it.
+var agentConversionWebhook = ackrtwebhook.New(
+	"v1beta1",
+	"Agent",
+	string(ackrtwebhook.WebhookTypeConversion),
+	func(mgr ctrlrt.Manager) error {
+		if err := svcapitypesv1beta1.AddToScheme(mgr.GetScheme()); err != nil {
+			return err
+		}
+		return ctrlrt.NewWebhookManagedBy(mgr, &svcapitypes.Agent{}).
+			Complete()
+	},
+)
+
+var agentValidatingWebhook = ackrtwebhook.New(
+	"v1alpha1",
+	"Agent",
+	"validating",
+	func(mgr ctrlrt.Manager) error {
+		return ctrlrt.NewWebhookManagedBy(mgr, &svcapitypes.Agent{}).
+			WithValidator(&agentValidator{}).
+			Complete()
+	},
+)
+
+func init() {
+	for _, webhook := range []*ackrtwebhook.Webhook{
+		agentDefaultingWebhook,
+		agentValidatingWebhook,
+		agentConversionWebhook,
+	} {
+		if err := ackrtwebhook.RegisterWebhook(webhook); err != nil {
+			msg := fmt.Sprintf("cannot register webhook: %v", err)
+			panic(msg)
+		}
+	}
+}
+
+// agentDefaulter sets the defaults that the Bedrock Agent API would otherwise
+// assign server-side, so the stored spec matches what AWS reports back.
+type agentDefaulter struct{}
+
+var _ admission.Defaulter[*svcapitypes.Agent] = &agentDefaulter{}
+
+// Default applies the server-side defaults to the Agent spec.
+func (d *agentDefaulter) Default(
+	ctx context.Context,
+	obj *svcapitypes.Agent,
+) error {
+	setAgentSpecDefaults(&obj.Spec)
+	return nil
+}
+
+// agentValidator rejects Agent specs that the Bedrock Agent API is known to
+// refuse, so that users get immediate feedback at admission time instead of
+// a Terminal condition after the first reconcile.
+type agentValidator struct{}
+
+var _ admission.Validator[*svcapitypes.Agent] = &agentValidator{}
+
+// ValidateCreate validates the annotations and spec of a newly created Agent.
+func (v *agentValidator) ValidateCreate(
+	ctx context.Context,
+	obj *svcapitypes.Agent,
+) (admission.Warnings, error) {
+	errs := validateAgentAnnotations(obj.Annotations, field.NewPath("metadata", "annotations"))
+	errs = append(errs, validateAgentSpec(&obj.Spec, field.NewPath("spec"))...)
+	return nil, invalidAgentError(obj, errs)
+}
+
+// ValidateUpdate validates the annotations and spec of an updated Agent and
+// rejects changes to immutable fields, except for an observed Agent whose
+// spec is mirrored from AWS. Only the changed parts are validated, so that
+// the finalizer, status and metadata updates of an Agent admitted under older
+// rules still go through, and an Agent being deleted is never refused.
+func (v *agentValidator) ValidateUpdate(
+	ctx context.Context,
+	oldObj *svcapitypes.Agent,
+	newObj *svcapitypes.Agent,
+) (admission.Warnings, error) {
+	if newObj.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	var errs field.ErrorList
+	if !equality.Semantic.DeepEqual(oldObj.Spec, newObj.Spec) {
+		specPath := field.NewPath("spec")
+		if !observe.IsObserveOnly(newObj) {
+			errs = validateAgentSpecUpdate(&oldObj.Spec, &newObj.Spec, specPath)
+		}
+		errs = append(errs, validateAgentSpec(&newObj.Spec, specPath)...)
+	}
+	if !equality.Semantic.DeepEqual(oldObj.Annotations, newObj.Annotations) {
+		errs = append(errs, validateAgentAnnotations(newObj.Annotations, field.NewPath("metadata", "annotations"))...)
+	}
+	return nil, invalidAgentError(newObj, errs)
+}
+
+// ValidateDelete allows every Agent deletion.
+func (v *agentValidator) ValidateDelete(
+	ctx context.Context,
+	obj *svcapitypes.Agent,
+) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// invalidAgentError aggregates every validation error into a single Invalid
+// API error, or returns nil when the list is empty.
+func invalidAgentError(
+	obj *svcapitypes.Agent,
+	errs field.ErrorList,
+) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(
+		svcapitypes.GroupVersion.WithKind("Agent").GroupKind(),
+		obj.Name,
+		errs,
+	)
+}
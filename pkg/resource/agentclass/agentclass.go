@@ -0,0 +1,137 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package agentclass implements the AgentClass, which holds the defaults of
+// the spec of the Agents naming it, the way a StorageClass does for volumes.
+// The defaults are merged into the desired spec of each Agent, never into the
+// stored one, so changes to the class reach its Agents on their next sync.
+// The customerEncryptionKeyARN, which cannot change, is the exception: it only
+// applies to the Agents being created.
+package agentclass
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+// DefaultClassAnnotation marks, when "true", the AgentClass applied to the
+// Agents without an agentClassName.
+const DefaultClassAnnotation = "bedrockagent.services.k8s.aws/is-default-class"
+
+// IsDefault returns whether the class carries the DefaultClassAnnotation.
+func IsDefault(obj metav1.Object) bool {
+	isDefault, err := strconv.ParseBool(obj.GetAnnotations()[DefaultClassAnnotation])
+	return err == nil && isDefault
+}
+
+// ForAgent returns the class named by the agentClassName of the Agent, or the
+// default class when it names none. It returns nil when the Agent names no
+// class and there is no default one, and an error when the named class
+// doesn't exist.
+func ForAgent(
+	ctx context.Context,
+	reader client.Reader,
+	ko *svcapitypes.Agent,
+) (*svcapitypes.AgentClass, error) {
+	name := ko.Spec.AgentClassName
+	if name == nil || *name == "" {
+		return Default(ctx, reader)
+	}
+	class := &svcapitypes.AgentClass{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: *name}, class); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("agentClassName: AgentClass %q not found", *name)
+		}
+		return nil, err
+	}
+	return class, nil
+}
+
+// Default returns the class carrying the DefaultClassAnnotation, or nil when
+// there is none. The newest one wins when several carry it, as with
+// StorageClasses.
+func Default(
+	ctx context.Context,
+	reader client.Reader,
+) (*svcapitypes.AgentClass, error) {
+	list := &svcapitypes.AgentClassList{}
+	if err := reader.List(ctx, list); err != nil {
+		return nil, err
+	}
+	var defaults []*svcapitypes.AgentClass
+	for i := range list.Items {
+		if IsDefault(&list.Items[i]) {
+			defaults = append(defaults, &list.Items[i])
+		}
+	}
+	if len(defaults) == 0 {
+		return nil, nil
+	}
+	sort.Slice(defaults, func(i, j int) bool {
+		ti, tj := defaults[i].CreationTimestamp, defaults[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return tj.Before(&ti)
+		}
+		return defaults[i].Name < defaults[j].Name
+	})
+	return defaults[0], nil
+}
+
+// Apply sets the fields of the spec left unset to their value in the class,
+// and returns their JSON names. The role is only applied to a spec setting
+// neither agentResourceRoleARN nor agentResourceRoleRef.
+func Apply(spec *svcapitypes.AgentSpec, class *svcapitypes.AgentClassSpec) []string {
+	var fields []string
+	if spec.AgentResourceRoleARN == nil && spec.AgentResourceRoleRef == nil {
+		if class.AgentResourceRoleARN != nil {
+			spec.AgentResourceRoleARN = copyString(class.AgentResourceRoleARN)
+			fields = append(fields, "agentResourceRoleARN")
+		}
+		if class.AgentResourceRoleRef != nil {
+			spec.AgentResourceRoleRef = class.AgentResourceRoleRef.DeepCopy()
+			fields = append(fields, "agentResourceRoleRef")
+		}
+	}
+	if spec.CustomerEncryptionKeyARN == nil && class.CustomerEncryptionKeyARN != nil {
+		spec.CustomerEncryptionKeyARN = copyString(class.CustomerEncryptionKeyARN)
+		fields = append(fields, "customerEncryptionKeyARN")
+	}
+	if spec.FoundationModel == nil && class.FoundationModel != nil {
+		spec.FoundationModel = copyString(class.FoundationModel)
+		fields = append(fields, "foundationModel")
+	}
+	if spec.GuardrailConfiguration == nil && class.GuardrailConfiguration != nil {
+		spec.GuardrailConfiguration = class.GuardrailConfiguration.DeepCopy()
+		fields = append(fields, "guardrailConfiguration")
+	}
+	if spec.IdleSessionTTLInSeconds == nil && class.IdleSessionTTLInSeconds != nil {
+		ttl := *class.IdleSessionTTLInSeconds
+		spec.IdleSessionTTLInSeconds = &ttl
+		fields = append(fields, "idleSessionTTLInSeconds")
+	}
+	return fields
+}
+
+func copyString(s *string) *string {
+	c := *s
+	return &c
+}
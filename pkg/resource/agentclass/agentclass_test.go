@@ -0,0 +1,124 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package agentclass
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	ackv1alpha1 "github.com/aws-controllers-k8s/runtime/apis/core/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	svcapitypes "github.com/aws-controllers-k8s/bedrockagent-controller/apis/v1alpha1"
+)
+
+func TestIsDefault(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "true": true, "false": false, "yes": false} {
+		obj := &metav1.ObjectMeta{Annotations: map[string]string{DefaultClassAnnotation: value}}
+		if got := IsDefault(obj); got != want {
+			t.Errorf("IsDefault(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestForAgent(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := svcapitypes.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	class := func(name string, isDefault bool, created time.Time) *svcapitypes.AgentClass {
+		c := &svcapitypes.AgentClass{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+		if isDefault {
+			c.Annotations = map[string]string{DefaultClassAnnotation: "true"}
+		}
+		return c
+	}
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		class("support", false, created),
+		class("old-default", true, created),
+		class("new-default", true, created.Add(time.Hour)),
+	).Build()
+
+	tests := []struct {
+		name      string
+		className *string
+		want      string
+		wantErr   bool
+	}{
+		{name: "named class", className: aws.String("support"), want: "support"},
+		{name: "newest default class", want: "new-default"},
+		{name: "missing class", className: aws.String("missing"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ko := &svcapitypes.Agent{Spec: svcapitypes.AgentSpec{AgentClassName: tt.className}}
+			got, err := ForAgent(ctx, reader, ko)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ForAgent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Name != tt.want {
+				t.Errorf("ForAgent() = %q, want %q", got.Name, tt.want)
+			}
+		})
+	}
+
+	empty := fake.NewClientBuilder().WithScheme(scheme).Build()
+	if got, err := ForAgent(ctx, empty, &svcapitypes.Agent{}); err != nil || got != nil {
+		t.Errorf("ForAgent() = %v, %v, want no class without a default one", got, err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	class := &svcapitypes.AgentClassSpec{
+		AgentResourceRoleRef: &ackv1alpha1.AWSResourceReferenceWrapper{
+			From: &ackv1alpha1.AWSResourceReference{Name: aws.String("agent-role")},
+		},
+		CustomerEncryptionKeyARN: aws.String("arn:aws:kms:us-west-2:123456789012:key/11111111-2222-3333-4444-555555555555"),
+		FoundationModel:          aws.String("anthropic.claude-3-haiku-20240307-v1:0"),
+		IdleSessionTTLInSeconds:  aws.Int64(900),
+	}
+	spec := &svcapitypes.AgentSpec{
+		AgentResourceRoleARN:    aws.String("arn:aws:iam::123456789012:role/agent-role"),
+		IdleSessionTTLInSeconds: aws.Int64(300),
+	}
+	fields := Apply(spec, class)
+	if want := []string{"customerEncryptionKeyARN", "foundationModel"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("Apply() = %v, want %v", fields, want)
+	}
+	if spec.AgentResourceRoleRef != nil {
+		t.Errorf("agentResourceRoleRef = %v, want none beside the role of the agent", spec.AgentResourceRoleRef)
+	}
+	if got := aws.ToInt64(spec.IdleSessionTTLInSeconds); got != 300 {
+		t.Errorf("idleSessionTTLInSeconds = %d, want the TTL of the agent", got)
+	}
+	*spec.FoundationModel = "changed"
+	if got := aws.ToString(class.FoundationModel); got == "changed" {
+		t.Errorf("Apply() shares the fields of the class with the spec")
+	}
+
+	spec = &svcapitypes.AgentSpec{}
+	if fields := Apply(spec, class); len(fields) != 4 || fields[0] != "agentResourceRoleRef" {
+		t.Errorf("Apply() = %v, want the role reference of the class first", fields)
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package events emits Kubernetes Events about the resources of the
+// controller, with a rate limit so that a flapping resource doesn't flood the
+// API server.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+)
+
+// +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+
+const (
+	// DefaultBurst is the number of Events with the same reason a resource
+	// can emit at once.
+	DefaultBurst = 5
+	// DefaultInterval is the time it takes a resource to earn back one Event
+	// of a given reason once its burst is spent.
+	DefaultInterval = time.Minute
+)
+
+// limiterKey identifies the Events of one reason about one resource.
+type limiterKey struct {
+	object string
+	reason string
+}
+
+// objectKey identifies the resource of an Event: its UID, or its namespace
+// and name when it has none, e.g. an object standing for a missing resource.
+func objectKey(obj runtime.Object) (string, bool) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	if uid := accessor.GetUID(); uid != "" {
+		return string(uid), true
+	}
+	return types.NamespacedName{Namespace: accessor.GetNamespace(), Name: accessor.GetName()}.String(), true
+}
+
+// Recorder emits Events through an EventRecorder, dropping those that exceed
+// the rate limit of their resource and reason. A nil Recorder drops every
+// Event.
+type Recorder struct {
+	recorder events.EventRecorder
+	limit    rate.Limit
+	burst    int
+
+	mu       sync.Mutex
+	limiters map[limiterKey]*rate.Limiter
+}
+
+// NewRecorder returns a Recorder emitting Events through the given
+// EventRecorder, with the DefaultBurst and DefaultInterval rate limit.
+func NewRecorder(recorder events.EventRecorder) *Recorder {
+	return NewRecorderWithLimit(recorder, DefaultBurst, DefaultInterval)
+}
+
+// NewRecorderWithLimit returns a Recorder allowing each resource burst Events
+// of a given reason, then one every interval.
+func NewRecorderWithLimit(
+	recorder events.EventRecorder,
+	burst int,
+	interval time.Duration,
+) *Recorder {
+	return &Recorder{
+		recorder: recorder,
+		limit:    rate.Every(interval),
+		burst:    burst,
+		limiters: map[limiterKey]*rate.Limiter{},
+	}
+}
+
+var recorder *Recorder
+
+// SetRecorder sets the Recorder used by the resource managers, which the
+// controller creates at startup with the Event recorder of its manager, see
+// pkg/controller. Events are dropped until then.
+func SetRecorder(r *Recorder) {
+	recorder = r
+}
+
+// GetRecorder returns the Recorder set with SetRecorder, or nil.
+func GetRecorder() *Recorder {
+	return recorder
+}
+
+// Normal emits an Event of type Normal about obj.
+func (r *Recorder) Normal(obj runtime.Object, reason, action, note string, args ...interface{}) {
+	r.eventf(obj, corev1.EventTypeNormal, reason, action, note, args...)
+}
+
+// Warning emits an Event of type Warning about obj.
+func (r *Recorder) Warning(obj runtime.Object, reason, action, note string, args ...interface{}) {
+	r.eventf(obj, corev1.EventTypeWarning, reason, action, note, args...)
+}
+
+func (r *Recorder) eventf(
+	obj runtime.Object,
+	eventType, reason, action, note string,
+	args ...interface{},
+) {
+	if r == nil || !r.allow(obj, reason) {
+		return
+	}
+	r.recorder.Eventf(obj, nil, eventType, reason, action, note, args...)
+}
+
+// allow reports whether an Event with the given reason about obj is within
+// the rate limit.
+func (r *Recorder) allow(obj runtime.Object, reason string) bool {
+	object, ok := objectKey(obj)
+	if !ok {
+		return false
+	}
+	key := limiterKey{object: object, reason: reason}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limiter, ok := r.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(r.limit, r.burst)
+		r.limiters[key] = limiter
+	}
+	return limiter.Allow()
+}
+
+// Forget releases the rate limits of a deleted resource.
+func (r *Recorder) Forget(obj runtime.Object) {
+	if r == nil {
+		return
+	}
+	object, ok := objectKey(obj)
+	if !ok {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key := range r.limiters {
+		if key.object == object {
+			delete(r.limiters, key)
+		}
+	}
+}
+
+// ForgetDeleted returns the handler of an informer of the resources that
+// releases their rate limits once they are deleted, whichever way their
+// finalizer was removed, e.g. by the runtime for a retained resource, which
+// the resource manager doesn't see.
+func (r *Recorder) ForgetDeleted() toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if obj, ok := obj.(runtime.Object); ok {
+				r.Forget(obj)
+			}
+		},
+	}
+}
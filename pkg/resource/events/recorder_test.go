@@ -0,0 +1,141 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+)
+
+func configMap(uid types.UID) *corev1.ConfigMap {
+	return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: string(uid), UID: uid}}
+}
+
+func drain(fake *events.FakeRecorder) []string {
+	var got []string
+	for {
+		select {
+		case e := <-fake.Events:
+			got = append(got, e)
+		default:
+			return got
+		}
+	}
+}
+
+func TestRecorder_RateLimit(t *testing.T) {
+	first, second := configMap("first"), configMap("second")
+	for _, tc := range []struct {
+		name string
+		emit func(r *Recorder)
+		want []string
+	}{
+		{
+			name: "burst of one reason",
+			emit: func(r *Recorder) {
+				for i := 0; i < 4; i++ {
+					r.Normal(first, "Changed", "Update", "change %d", i)
+				}
+			},
+			want: []string{"Normal Changed change 0", "Normal Changed change 1"},
+		},
+		{
+			name: "reasons are limited separately",
+			emit: func(r *Recorder) {
+				r.Normal(first, "Changed", "Update", "change")
+				r.Normal(first, "Changed", "Update", "change")
+				r.Normal(first, "Changed", "Update", "change")
+				r.Warning(first, "Failed", "Update", "failure")
+			},
+			want: []string{"Normal Changed change", "Normal Changed change", "Warning Failed failure"},
+		},
+		{
+			name: "resources are limited separately",
+			emit: func(r *Recorder) {
+				r.Normal(first, "Changed", "Update", "first")
+				r.Normal(first, "Changed", "Update", "first")
+				r.Normal(first, "Changed", "Update", "first")
+				r.Normal(second, "Changed", "Update", "second")
+			},
+			want: []string{"Normal Changed first", "Normal Changed first", "Normal Changed second"},
+		},
+		{
+			name: "resources without a UID are limited by name",
+			emit: func(r *Recorder) {
+				missing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "missing"}}
+				other := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "other"}}
+				r.Warning(missing, "Orphaned", "Sweep", "missing")
+				r.Warning(missing, "Orphaned", "Sweep", "missing")
+				r.Warning(missing, "Orphaned", "Sweep", "missing")
+				r.Warning(other, "Orphaned", "Sweep", "other")
+			},
+			want: []string{"Warning Orphaned missing", "Warning Orphaned missing", "Warning Orphaned other"},
+		},
+		{
+			name: "deletions reset the limit",
+			emit: func(r *Recorder) {
+				r.Normal(first, "Changed", "Update", "before")
+				r.Normal(first, "Changed", "Update", "before")
+				r.ForgetDeleted().OnDelete(toolscache.DeletedFinalStateUnknown{Key: "ns/first", Obj: first})
+				r.Normal(first, "Changed", "Update", "after")
+				r.Normal(second, "Changed", "Update", "second")
+				r.Normal(second, "Changed", "Update", "second")
+				r.ForgetDeleted().OnDelete(second)
+				r.Normal(second, "Changed", "Update", "second")
+			},
+			want: []string{
+				"Normal Changed before", "Normal Changed before", "Normal Changed after",
+				"Normal Changed second", "Normal Changed second", "Normal Changed second",
+			},
+		},
+		{
+			name: "forget resets the limit",
+			emit: func(r *Recorder) {
+				r.Normal(first, "Changed", "Update", "before")
+				r.Normal(first, "Changed", "Update", "before")
+				r.Forget(first)
+				r.Normal(first, "Changed", "Update", "after")
+			},
+			want: []string{"Normal Changed before", "Normal Changed before", "Normal Changed after"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := events.NewFakeRecorder(10)
+			tc.emit(NewRecorderWithLimit(fake, 2, time.Hour))
+			got := drain(fake)
+			if len(got) != len(tc.want) {
+				t.Fatalf("events = %q, want %q", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("event %d = %q, want %q", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var r *Recorder
+	// A nil Recorder, used before SetRecorder is called, drops the Events.
+	r.Normal(configMap("uid"), "Changed", "Update", "change")
+	r.Warning(configMap("uid"), "Failed", "Update", "failure")
+	r.Forget(configMap("uid"))
+}
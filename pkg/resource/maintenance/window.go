@@ -0,0 +1,98 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package maintenance evaluates the maintenance windows in which disruptive
+// changes to an AWS resource are applied. A window opens on each activation
+// of a cron schedule, in a time zone, and stays open for a duration. The
+// OverrideAnnotation applies the changes outside of the window.
+package maintenance
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OverrideAnnotation applies the changes to a resource right away, outside
+// of its maintenance window, when "true".
+const OverrideAnnotation = "bedrockagent.services.k8s.aws/ignore-maintenance-window"
+
+// Window is a recurring period in which changes are applied.
+type Window struct {
+	schedule cron.Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// FieldError is the error returned by Parse for an invalid field of a window:
+// "schedule", "duration" or "timeZone".
+type FieldError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Field, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Parse returns the window opening on each activation of the standard cron
+// schedule (five fields, or a descriptor like @daily), in the IANA time zone,
+// for the Go duration, e.g. "2h". An empty time zone means UTC.
+func Parse(schedule string, duration string, timeZone string) (*Window, error) {
+	s, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, &FieldError{Field: "schedule", Value: schedule, Err: err}
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, &FieldError{Field: "duration", Value: duration, Err: err}
+	}
+	if d <= 0 {
+		return nil, &FieldError{Field: "duration", Value: duration, Err: errors.New("must be positive")}
+	}
+	location := time.UTC
+	if timeZone != "" {
+		if location, err = time.LoadLocation(timeZone); err != nil {
+			return nil, &FieldError{Field: "timeZone", Value: timeZone, Err: err}
+		}
+	}
+	return &Window{schedule: s, duration: d, location: location}, nil
+}
+
+// Open returns whether the window is open at the given time. When it is
+// closed, it also returns the time at which it opens next, which is zero for
+// a schedule that is never activated, like February 30th.
+func (w *Window) Open(now time.Time) (bool, time.Time) {
+	// The window is open when the schedule was activated less than its
+	// duration ago.
+	start := w.schedule.Next(now.In(w.location).Add(-w.duration))
+	if !start.IsZero() && !start.After(now) {
+		return true, time.Time{}
+	}
+	return false, start
+}
+
+// IsOverridden returns whether the resource carries the OverrideAnnotation.
+func IsOverridden(obj metav1.Object) bool {
+	overridden, err := strconv.ParseBool(obj.GetAnnotations()[OverrideAnnotation])
+	return err == nil && overridden
+}
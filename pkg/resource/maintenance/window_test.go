@@ -0,0 +1,102 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package maintenance
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		schedule string
+		duration string
+		timeZone string
+		wantErr  string
+	}{
+		{name: "valid", schedule: "0 2 * * SAT", duration: "2h", timeZone: "Europe/Paris"},
+		{name: "descriptor in UTC", schedule: "@daily", duration: "30m"},
+		{name: "invalid schedule", schedule: "0 2 * *", duration: "2h", wantErr: "schedule"},
+		{name: "seconds field", schedule: "0 0 2 * * SAT", duration: "2h", wantErr: "schedule"},
+		{name: "invalid duration", schedule: "@daily", duration: "2 hours", wantErr: "duration"},
+		{name: "zero duration", schedule: "@daily", duration: "0s", wantErr: "duration"},
+		{name: "invalid time zone", schedule: "@daily", duration: "2h", timeZone: "Mars/Olympus", wantErr: "timeZone"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse(tc.schedule, tc.duration, tc.timeZone)
+			var fieldErr *FieldError
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("Parse() error = %v, want none", err)
+				}
+			} else if !errors.As(err, &fieldErr) || fieldErr.Field != tc.wantErr {
+				t.Errorf("Parse() error = %v, want an invalid %s", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestWindow_Open(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	// Saturdays from 02:00 to 04:00, Paris time.
+	w, err := Parse("0 2 * * SAT", "2h", "Europe/Paris")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2026, time.October, 17, hour, minute, 0, 0, paris).UTC()
+	}
+	for _, tc := range []struct {
+		name     string
+		now      time.Time
+		wantOpen bool
+		wantNext time.Time
+	}{
+		{name: "before", now: saturday(1, 59), wantNext: saturday(2, 0)},
+		{name: "opening", now: saturday(2, 0), wantOpen: true},
+		{name: "during", now: saturday(3, 59), wantOpen: true},
+		{name: "closing", now: saturday(4, 0), wantNext: saturday(2, 0).AddDate(0, 0, 7)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			open, next := w.Open(tc.now)
+			if open != tc.wantOpen || !next.Equal(tc.wantNext) {
+				t.Errorf("Open() = %v, %v, want %v, %v", open, next, tc.wantOpen, tc.wantNext)
+			}
+		})
+	}
+
+	never, err := Parse("0 0 30 2 *", "1h", "")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if open, next := never.Open(saturday(2, 0)); open || !next.IsZero() {
+		t.Errorf("Open() of a schedule never activated = %v, %v, want closed forever", open, next)
+	}
+}
+
+func TestIsOverridden(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "true": true, "false": false, "now!": false} {
+		obj := &metav1.ObjectMeta{Annotations: map[string]string{OverrideAnnotation: value}}
+		if got := IsOverridden(obj); got != want {
+			t.Errorf("IsOverridden(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package observe implements the management policy of the resources owned by
+// other systems, e.g. a Terraform stack. The controller adopts such a
+// resource, by ID or by name, and mirrors it from AWS, but never creates,
+// updates, tags or deletes it.
+package observe
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PolicyAnnotation sets the management policy of a resource: PolicyFull,
+	// the default, or PolicyObserve.
+	PolicyAnnotation = "bedrockagent.services.k8s.aws/management-policy"
+	// IDAnnotation names the AWS resource an observed resource adopts. The
+	// resource is adopted by name when it is unset.
+	IDAnnotation = "bedrockagent.services.k8s.aws/observe-id"
+)
+
+// The management policies.
+const (
+	// PolicyFull creates, updates and deletes the AWS resource.
+	PolicyFull = "Full"
+	// PolicyObserve only reads the AWS resource.
+	PolicyObserve = "Observe"
+)
+
+// IsValidPolicy returns whether the value names a management policy, compared
+// without case. An empty value is the default policy.
+func IsValidPolicy(value string) bool {
+	return value == "" || strings.EqualFold(value, PolicyFull) || strings.EqualFold(value, PolicyObserve)
+}
+
+// IsObserveOnly returns whether the PolicyAnnotation of the resource is
+// PolicyObserve.
+func IsObserveOnly(obj metav1.Object) bool {
+	return strings.EqualFold(obj.GetAnnotations()[PolicyAnnotation], PolicyObserve)
+}
+
+// ID returns the ID named by the IDAnnotation of the resource, if any.
+func ID(obj metav1.Object) (string, bool) {
+	id := strings.TrimSpace(obj.GetAnnotations()[IDAnnotation])
+	return id, id != ""
+}
@@ -0,0 +1,47 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package observe
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsObserveOnly(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "Full": false, "Observe": true, "observe": true, "read-only": false} {
+		obj := &metav1.ObjectMeta{Annotations: map[string]string{PolicyAnnotation: value}}
+		if got := IsObserveOnly(obj); got != want {
+			t.Errorf("IsObserveOnly(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestIsValidPolicy(t *testing.T) {
+	for value, want := range map[string]bool{"": true, "full": true, "Observe": true, "ReadOnly": false} {
+		if got := IsValidPolicy(value); got != want {
+			t.Errorf("IsValidPolicy(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestID(t *testing.T) {
+	if id, ok := ID(&metav1.ObjectMeta{}); ok {
+		t.Errorf("ID() = %q without the annotation", id)
+	}
+	obj := &metav1.ObjectMeta{Annotations: map[string]string{IDAnnotation: " ABCDEFGHIJ "}}
+	if id, ok := ID(obj); !ok || id != "ABCDEFGHIJ" {
+		t.Errorf("ID() = %q, %v, want ABCDEFGHIJ", id, ok)
+	}
+}
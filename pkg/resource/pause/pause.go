@@ -0,0 +1,32 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package pause implements the annotation pausing the reconciliation of a
+// resource, e.g. while it is edited by hand during an incident. A paused
+// resource is still read, but never created, updated or deleted.
+package pause
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotation pauses the reconciliation of a resource when "true".
+const Annotation = "bedrockagent.services.k8s.aws/paused"
+
+// IsPaused returns whether the resource carries the Annotation.
+func IsPaused(obj metav1.Object) bool {
+	paused, err := strconv.ParseBool(obj.GetAnnotations()[Annotation])
+	return err == nil && paused
+}
@@ -0,0 +1,74 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package plan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RequireApprovalAnnotation makes the changes to a resource wait for an
+	// approval when "true", and takes the resource out of the controller-wide
+	// approval mode when "false".
+	RequireApprovalAnnotation = "bedrockagent.services.k8s.aws/require-approval"
+	// ApprovedPlanAnnotation holds the hash of the approved plan. Only the
+	// plan with that exact hash is applied.
+	ApprovedPlanAnnotation = "bedrockagent.services.k8s.aws/approved-plan"
+)
+
+var requireApproval bool
+
+// SetRequireApproval enables or disables the controller-wide approval mode,
+// which the --require-approval flag of the controller sets, see
+// pkg/controller.
+func SetRequireApproval(enabled bool) {
+	requireApproval = enabled
+}
+
+// RequiresApproval returns whether the changes to the resource must be
+// approved before they are applied. The RequireApprovalAnnotation of the
+// resource, when it is a valid boolean, takes precedence over the
+// controller-wide mode.
+func RequiresApproval(obj metav1.Object) bool {
+	return boolAnnotation(obj, RequireApprovalAnnotation, requireApproval)
+}
+
+// Hash returns the hash identifying the plan for the given generation of the
+// resource. It covers the complete values of the changes, not their summary,
+// and the generation, so that an approval doesn't apply to a later spec.
+func (p *Plan) Hash(generation int64) string {
+	data, err := json.Marshal(struct {
+		Generation int64
+		Resource   string
+		Changes    []Change
+		Calls      []string
+	}{generation, p.Resource, p.Changes, p.Calls})
+	if err != nil {
+		// The changes are copied from Kubernetes objects, which always
+		// encode to JSON.
+		panic(err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// IsApproved returns whether the resource carries the approval of the plan
+// with the given hash.
+func IsApproved(obj metav1.Object, hash string) bool {
+	return obj.GetAnnotations()[ApprovedPlanAnnotation] == hash
+}
@@ -0,0 +1,86 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRequiresApproval(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		flag       bool
+		annotation *string
+		want       bool
+	}{
+		{name: "disabled", want: false},
+		{name: "flag", flag: true, want: true},
+		{name: "annotation", annotation: ptr("true"), want: true},
+		{name: "annotation opts out of the flag", flag: true, annotation: ptr("false"), want: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			SetRequireApproval(tc.flag)
+			t.Cleanup(func() { SetRequireApproval(false) })
+			obj := &metav1.ObjectMeta{}
+			if tc.annotation != nil {
+				obj.Annotations = map[string]string{RequireApprovalAnnotation: *tc.annotation}
+			}
+			if got := RequiresApproval(obj); got != tc.want {
+				t.Errorf("RequiresApproval() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlan_Hash(t *testing.T) {
+	instruction := func(suffix string) *Plan {
+		return &Plan{
+			Action:   "Update",
+			Resource: "agent AGENT00001",
+			Changes: []Change{{
+				Path: "Spec.Instruction",
+				Old:  ptr("You are a support agent."),
+				New:  ptr(strings.Repeat("You are a helpful support agent. ", 5) + suffix),
+			}},
+			Calls: []string{"UpdateAgent", "PrepareAgent"},
+		}
+	}
+	base := instruction("Be brief.").Hash(2)
+
+	if got := instruction("Be brief.").Hash(2); got != base {
+		t.Errorf("Hash() of the same plan = %s, want %s", got, base)
+	}
+	if instruction("Be rude!!").String() != instruction("Be brief.").String() {
+		t.Fatal("the test plans should only differ past their summary")
+	}
+	for name, hash := range map[string]string{
+		"value past the summary": instruction("Be rude!!").Hash(2),
+		"later generation":       instruction("Be brief.").Hash(3),
+	} {
+		if hash == base {
+			t.Errorf("Hash() doesn't change with the %s", name)
+		}
+	}
+
+	obj := &metav1.ObjectMeta{Annotations: map[string]string{ApprovedPlanAnnotation: base}}
+	if !IsApproved(obj, base) {
+		t.Error("IsApproved() = false for the annotated hash")
+	}
+	if IsApproved(obj, instruction("Be brief.").Hash(3)) {
+		t.Error("IsApproved() = true for the plan of a later generation")
+	}
+}
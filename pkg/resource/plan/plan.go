@@ -0,0 +1,172 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package plan describes the changes the controller would make to an AWS
+// resource without making them. A resource is in dry-run mode when the
+// controller runs with dry-run enabled or when it carries the DryRunAnnotation.
+// In approval mode, the changes are applied once the resource carries the
+// hash of their plan in the ApprovedPlanAnnotation.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DryRunAnnotation puts a resource in dry-run mode when "true", and
+	// takes it out of the controller-wide dry-run mode when "false".
+	DryRunAnnotation = "bedrockagent.services.k8s.aws/dry-run"
+	// MaxValueLength is the length above which the values of a plan are
+	// summarised.
+	MaxValueLength = 80
+)
+
+var dryRun bool
+
+// SetDryRun enables or disables the controller-wide dry-run mode, which the
+// --dry-run flag of the controller sets, see pkg/controller.
+func SetDryRun(enabled bool) {
+	dryRun = enabled
+}
+
+// IsDryRun returns whether the changes to the resource must be planned
+// rather than applied. The DryRunAnnotation of the resource, when it is a
+// valid boolean, takes precedence over the controller-wide mode.
+func IsDryRun(obj metav1.Object) bool {
+	return boolAnnotation(obj, DryRunAnnotation, dryRun)
+}
+
+// boolAnnotation returns the value of a boolean annotation of the object, or
+// fallback when the annotation is missing or isn't a boolean.
+func boolAnnotation(obj metav1.Object, annotation string, fallback bool) bool {
+	if value, ok := obj.GetAnnotations()[annotation]; ok {
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			return enabled
+		}
+	}
+	return fallback
+}
+
+// Change is the change of a field of the resource.
+type Change struct {
+	// Path is the path of the field, e.g. Spec.Instruction.
+	Path string
+	// Old and New are the current and desired values of the field. A nil
+	// value means the field is unset.
+	Old any
+	New any
+}
+
+// Plan lists the changes to a resource and the API calls that would make
+// them.
+type Plan struct {
+	// Action is what would happen to the resource: Create, Update or Delete.
+	Action string
+	// Resource identifies the resource, e.g. its ID once it is created.
+	Resource string
+	Changes  []Change
+	Calls    []string
+}
+
+// Empty returns whether the plan makes no API call.
+func (p *Plan) Empty() bool {
+	return len(p.Calls) == 0
+}
+
+// AddCall appends an API call to the plan, unless it is already there.
+func (p *Plan) AddCall(call string) {
+	for _, existing := range p.Calls {
+		if existing == call {
+			return
+		}
+	}
+	p.Calls = append(p.Calls, call)
+}
+
+// Summary returns a one-line description of the plan, e.g. for the message
+// of a condition.
+func (p *Plan) Summary() string {
+	if p.Empty() {
+		return "No changes"
+	}
+	return fmt.Sprintf(
+		"%s: %d changed field(s), calls %s",
+		p.Action, len(p.Changes), strings.Join(p.Calls, ", "),
+	)
+}
+
+// String renders the plan for people, one change per line.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes\n"
+	}
+	var b strings.Builder
+	b.WriteString(p.Action)
+	if p.Resource != "" {
+		b.WriteString(" " + p.Resource)
+	}
+	b.WriteString("\n")
+	for _, change := range p.Changes {
+		fmt.Fprintf(&b, "  %s: %s -> %s\n", change.Path, Summarize(change.Old), Summarize(change.New))
+	}
+	fmt.Fprintf(&b, "API calls: %s\n", strings.Join(p.Calls, ", "))
+	return b.String()
+}
+
+// Summarize renders a value of a plan as JSON. Values longer than
+// MaxValueLength, like an agent instruction, are cut and followed by their
+// length.
+func Summarize(value any) string {
+	if isNil(value) {
+		return "<unset>"
+	}
+	if s, ok := value.(*string); ok {
+		value = *s
+	}
+	if s, ok := value.(string); ok {
+		length := utf8.RuneCountInString(s)
+		if length <= MaxValueLength {
+			return strconv.Quote(s)
+		}
+		quoted := strconv.Quote(truncate(s))
+		return fmt.Sprintf("%s…\" (%d chars)", quoted[:len(quoted)-1], length)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	if len(encoded) <= MaxValueLength {
+		return string(encoded)
+	}
+	return fmt.Sprintf("%s… (%d bytes)", truncate(string(encoded)), len(encoded))
+}
+
+// truncate returns the first half of MaxValueLength runes of s.
+func truncate(s string) string {
+	runes := []rune(s)
+	return string(runes[:MaxValueLength/2])
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	encoded, err := json.Marshal(value)
+	return err == nil && string(encoded) == "null"
+}
@@ -0,0 +1,114 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIsDryRun(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		flag       bool
+		annotation *string
+		want       bool
+	}{
+		{name: "disabled", want: false},
+		{name: "flag", flag: true, want: true},
+		{name: "annotation", annotation: ptr("true"), want: true},
+		{name: "annotation opts out of the flag", flag: true, annotation: ptr("false"), want: false},
+		{name: "invalid annotation falls back to the flag", flag: true, annotation: ptr("yes please"), want: true},
+		{name: "invalid annotation without the flag", annotation: ptr("yes please"), want: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			SetDryRun(tc.flag)
+			t.Cleanup(func() { SetDryRun(false) })
+			obj := &metav1.ObjectMeta{}
+			if tc.annotation != nil {
+				obj.Annotations = map[string]string{DryRunAnnotation: *tc.annotation}
+			}
+			if got := IsDryRun(obj); got != tc.want {
+				t.Errorf("IsDryRun() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	long := strings.Repeat("You are a helpful agent. ", 10)
+	for _, tc := range []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "nil", value: nil, want: "<unset>"},
+		{name: "nil pointer", value: (*string)(nil), want: "<unset>"},
+		{name: "nil map", value: map[string]*string(nil), want: "<unset>"},
+		{name: "string pointer", value: ptr("PREPARED"), want: `"PREPARED"`},
+		{name: "number", value: int64(600), want: "600"},
+		{name: "map", value: map[string]string{"team": "ml"}, want: `{"team":"ml"}`},
+		{
+			name:  "long string",
+			value: ptr(long),
+			want:  `"You are a helpful agent. You are a helpf…" (250 chars)`,
+		},
+		{
+			name:  "long object",
+			value: []string{long},
+			want:  `["You are a helpful agent. You are a hel… (254 bytes)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Summarize(tc.value); got != tc.want {
+				t.Errorf("Summarize() = %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPlan_String(t *testing.T) {
+	p := &Plan{Action: "Update", Resource: "agent AGENT00001"}
+	if got, want := p.String(), "No changes\n"; got != want {
+		t.Errorf("String() of an empty plan = %q, want %q", got, want)
+	}
+	if got, want := p.Summary(), "No changes"; got != want {
+		t.Errorf("Summary() of an empty plan = %q, want %q", got, want)
+	}
+
+	p.Changes = []Change{
+		{Path: "Spec.IdleSessionTTLInSeconds", Old: int64(600), New: int64(900)},
+		{Path: "Spec.Description", New: ptr("Support agent")},
+	}
+	p.AddCall("UpdateAgent")
+	p.AddCall("PrepareAgent")
+	p.AddCall("UpdateAgent")
+	want := `Update agent AGENT00001
+  Spec.IdleSessionTTLInSeconds: 600 -> 900
+  Spec.Description: <unset> -> "Support agent"
+API calls: UpdateAgent, PrepareAgent
+`
+	if got := p.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if got, want := p.Summary(), "Update: 2 changed field(s), calls UpdateAgent, PrepareAgent"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,82 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package revision keeps the history of the specs an AWS resource was
+// successfully applied with. A resource carrying the RollbackAnnotation is
+// reconciled to the spec of the revision it names instead of its own spec,
+// until the annotation is removed.
+package revision
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RollbackAnnotation names the revision the resource is rolled back to,
+	// by its number or by the hash of its spec.
+	RollbackAnnotation = "bedrockagent.services.k8s.aws/rollback-to"
+	// DefaultHistoryLimit is the default number of revisions kept per
+	// resource.
+	DefaultHistoryLimit = 5
+	// MaxSpecBytes bounds the size of the specs kept in the revisions of a
+	// resource, which are saved in its status, well below the size limit of
+	// an object. Only the hashes of older revisions are kept.
+	MaxSpecBytes = 256 << 10
+)
+
+var historyLimit = DefaultHistoryLimit
+
+// SetHistoryLimit sets the number of revisions kept per resource, which the
+// --revision-history-limit flag of the controller sets, see pkg/controller.
+// Zero stops the recording of revisions.
+func SetHistoryLimit(limit int) {
+	historyLimit = limit
+}
+
+// HistoryLimit returns the number of revisions kept per resource. A negative
+// limit keeps none.
+func HistoryLimit() int {
+	return max(historyLimit, 0)
+}
+
+// Encode returns the JSON encoding of a spec and the hash identifying it.
+func Encode(spec any) (string, string) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		// Specs are Kubernetes API types, which always encode to JSON.
+		panic(err)
+	}
+	sum := sha256.Sum256(data)
+	return string(data), hex.EncodeToString(sum[:])
+}
+
+// RollbackTarget returns the value of the RollbackAnnotation of the resource
+// and whether it is set.
+func RollbackTarget(obj metav1.Object) (string, bool) {
+	target, ok := obj.GetAnnotations()[RollbackAnnotation]
+	return target, ok && target != ""
+}
+
+// Matches returns whether the target of a rollback, a revision number or a
+// spec hash, designates the revision.
+func Matches(target string, number int64, specHash string) bool {
+	if n, err := strconv.ParseInt(target, 10, 64); err == nil {
+		return n == number
+	}
+	return target == specHash
+}
@@ -0,0 +1,80 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package revision
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEncode(t *testing.T) {
+	type spec struct {
+		Instruction string `json:"instruction"`
+	}
+	data, hash := Encode(spec{Instruction: "You are a support agent."})
+	if want := `{"instruction":"You are a support agent."}`; data != want {
+		t.Errorf("Encode() data = %s, want %s", data, want)
+	}
+	if _, again := Encode(spec{Instruction: "You are a support agent."}); again != hash {
+		t.Errorf("Encode() hash of the same spec = %s, want %s", again, hash)
+	}
+	if _, other := Encode(spec{Instruction: "You are a sales agent."}); other == hash {
+		t.Error("Encode() hash doesn't change with the spec")
+	}
+}
+
+func TestRollbackTarget(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		annotations map[string]string
+		want        string
+		wantOK      bool
+	}{
+		{name: "no annotation"},
+		{name: "empty annotation", annotations: map[string]string{RollbackAnnotation: ""}},
+		{name: "revision", annotations: map[string]string{RollbackAnnotation: "3"}, want: "3", wantOK: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := RollbackTarget(&metav1.ObjectMeta{Annotations: tc.annotations})
+			if got != tc.want || ok != tc.wantOK {
+				t.Errorf("RollbackTarget() = %q, %v, want %q, %v", got, ok, tc.want, tc.wantOK)
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	for _, tc := range []struct {
+		target string
+		want   bool
+	}{
+		{target: "3", want: true},
+		{target: "4", want: false},
+		{target: "9f86d081", want: true},
+		{target: "9f86d082", want: false},
+	} {
+		if got := Matches(tc.target, 3, "9f86d081"); got != tc.want {
+			t.Errorf("Matches(%q) = %v, want %v", tc.target, got, tc.want)
+		}
+	}
+}
+
+func TestSetHistoryLimit(t *testing.T) {
+	t.Cleanup(func() { SetHistoryLimit(DefaultHistoryLimit) })
+	SetHistoryLimit(-1)
+	if got := HistoryLimit(); got != 0 {
+		t.Errorf("HistoryLimit() = %d after a negative limit, want 0", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"context"
+)
+
+// Adapter gives the tag engine access to the ARN and the Spec tags of a
+// resource. The resource manager of each taggable resource implements it for
+// its own resource type.
+type Adapter[R any] interface {
+	// ResourceARN returns the ARN used in the tagging API calls.
+	ResourceARN(res R) string
+	// ResourceTags returns the tags declared in the Spec of the resource.
+	ResourceTags(res R) map[string]*string
+}
+
+// Engine reads and synchronises the tags of the resources of a single kind.
+type Engine[R any] struct {
+	client  tagsClient
+	metrics metricsRecorder
+	adapter Adapter[R]
+}
+
+// NewEngine returns an Engine using the given client and adapter.
+func NewEngine[R any](
+	client tagsClient,
+	mr metricsRecorder,
+	adapter Adapter[R],
+) *Engine[R] {
+	return &Engine[R]{
+		client:  client,
+		metrics: mr,
+		adapter: adapter,
+	}
+}
+
+// Get returns the tags of the resource.
+func (e *Engine[R]) Get(
+	ctx context.Context,
+	res R,
+) (map[string]*string, error) {
+	return GetResourceTags(ctx, e.client, e.metrics, e.adapter.ResourceARN(res))
+}
+
+// Sync updates the tags of the latest resource to match the desired
+// resource.
+func (e *Engine[R]) Sync(
+	ctx context.Context,
+	desired R,
+	latest R,
+) error {
+	return SyncResourceTags(
+		ctx,
+		e.client,
+		e.metrics,
+		e.adapter.ResourceARN(latest),
+		e.adapter.ResourceTags(desired),
+		e.adapter.ResourceTags(latest),
+	)
+}
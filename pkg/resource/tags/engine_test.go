@@ -0,0 +1,83 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"testing"
+
+	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+)
+
+type fakeResource struct {
+	arn  string
+	tags map[string]*string
+}
+
+// fakeAdapter is an Adapter for fakeResource.
+type fakeAdapter struct{}
+
+func (fakeAdapter) ResourceARN(res *fakeResource) string {
+	return res.arn
+}
+
+func (fakeAdapter) ResourceTags(res *fakeResource) map[string]*string {
+	return res.tags
+}
+
+func TestEngine(t *testing.T) {
+	const arn = "arn:aws:bedrock:us-west-2:123456789012:knowledge-base/KB00000001"
+	var tagged map[string]string
+	var untagged []string
+	client := &mockTagsClient{
+		listTagsForResourceFunc: func(ctx context.Context, input *svcsdk.ListTagsForResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.ListTagsForResourceOutput, error) {
+			if *input.ResourceArn != arn {
+				t.Errorf("Expected ResourceArn %s, got %s", arn, *input.ResourceArn)
+			}
+			return &svcsdk.ListTagsForResourceOutput{Tags: map[string]string{"key1": "value1"}}, nil
+		},
+		tagResourceFunc: func(ctx context.Context, input *svcsdk.TagResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.TagResourceOutput, error) {
+			tagged = input.Tags
+			return &svcsdk.TagResourceOutput{}, nil
+		},
+		untagResourceFunc: func(ctx context.Context, input *svcsdk.UntagResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.UntagResourceOutput, error) {
+			untagged = input.TagKeys
+			return &svcsdk.UntagResourceOutput{}, nil
+		},
+	}
+	mr := &mockMetricsRecorder{
+		recordAPICallFunc: func(opType string, opID string, err error) {},
+	}
+	engine := NewEngine[*fakeResource](client, mr, fakeAdapter{})
+
+	got, err := engine.Get(context.Background(), &fakeResource{arn: arn})
+	if err != nil {
+		t.Fatalf("Engine.Get() error = %v", err)
+	}
+	if !EqualTags(got, map[string]*string{"key1": stringPtr("value1")}) {
+		t.Errorf("Engine.Get() = %v, want key1=value1", got)
+	}
+
+	desired := &fakeResource{arn: arn, tags: map[string]*string{"key2": stringPtr("value2")}}
+	latest := &fakeResource{arn: arn, tags: got}
+	if err := engine.Sync(context.Background(), desired, latest); err != nil {
+		t.Fatalf("Engine.Sync() error = %v", err)
+	}
+	if len(tagged) != 1 || tagged["key2"] != "value2" {
+		t.Errorf("Expected key2=value2 to be tagged, got %v", tagged)
+	}
+	if len(untagged) != 1 || untagged[0] != "key1" {
+		t.Errorf("Expected key1 to be untagged, got %v", untagged)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OwnerClusterTagKey is the key of the tag holding the ID of the cluster
+	// whose controller owns the resource, so that two clusters, e.g. in a
+	// blue/green deployment, don't both reconcile it.
+	OwnerClusterTagKey = "bedrockagent.services.k8s.aws/owner-cluster"
+	// TakeoverAnnotation lets the controller write to a resource owned by
+	// another cluster when "true", which moves the ownership of the resource
+	// to the cluster of the controller.
+	TakeoverAnnotation = "bedrockagent.services.k8s.aws/take-over"
+)
+
+var clusterID string
+
+// SetClusterID sets the ID of the cluster of the controller, written in the
+// OwnerClusterTagKey tag of the resources it writes to, which the
+// --cluster-id flag of the controller sets, see pkg/controller. Ownership
+// isn't tracked until it is called with a non-empty ID.
+func SetClusterID(id string) error {
+	if id != "" {
+		if err := ValidateTag(OwnerClusterTagKey, id); err != nil {
+			return err
+		}
+	}
+	clusterID = id
+	return nil
+}
+
+// ClusterID returns the ID set with SetClusterID.
+func ClusterID() string {
+	return clusterID
+}
+
+// WithOwnerTag returns a copy of the resource tags with the OwnerClusterTagKey
+// tag of the cluster, or the tags themselves when the cluster has no ID.
+func WithOwnerTag(resourceTags map[string]*string) map[string]*string {
+	if clusterID == "" {
+		return resourceTags
+	}
+	owned := make(map[string]*string, len(resourceTags)+1)
+	for key, value := range resourceTags {
+		owned[key] = value
+	}
+	id := clusterID
+	owned[OwnerClusterTagKey] = &id
+	return owned
+}
+
+// OwnerCluster returns the ID of the cluster owning the resource with the
+// given tags, or an empty string when it has no owner.
+func OwnerCluster(resourceTags map[string]*string) string {
+	return stringValue(resourceTags[OwnerClusterTagKey])
+}
+
+// IsOwnedElsewhere returns whether the owner, as returned by OwnerCluster, is
+// another cluster than the one of the controller. Resources without an owner
+// belong to any cluster, and every resource belongs to a controller without
+// a cluster ID.
+func IsOwnedElsewhere(owner string) bool {
+	return clusterID != "" && owner != "" && owner != clusterID
+}
+
+// IsTakeover returns whether the resource carries the TakeoverAnnotation.
+func IsTakeover(obj metav1.Object) bool {
+	takeover, err := strconv.ParseBool(obj.GetAnnotations()[TakeoverAnnotation])
+	return err == nil && takeover
+}
@@ -0,0 +1,58 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestOwnership(t *testing.T) {
+	t.Cleanup(func() { clusterID = "" })
+	resourceTags := map[string]*string{"team": stringPtr("ml")}
+
+	if got := WithOwnerTag(resourceTags); len(got) != 1 {
+		t.Errorf("WithOwnerTag() without a cluster ID = %v, want the resource tags", got)
+	}
+	if IsOwnedElsewhere("blue") {
+		t.Errorf("IsOwnedElsewhere() without a cluster ID = true, want false")
+	}
+
+	if err := SetClusterID("green!"); err == nil {
+		t.Errorf("SetClusterID() of an invalid tag value error = nil")
+	}
+	if err := SetClusterID("green"); err != nil {
+		t.Fatalf("SetClusterID() error = %v", err)
+	}
+	owned := WithOwnerTag(resourceTags)
+	if got := OwnerCluster(owned); got != "green" || len(owned) != 2 {
+		t.Errorf("WithOwnerTag() = %v, want the team tag and the owner tag green", owned)
+	}
+	if _, ok := resourceTags[OwnerClusterTagKey]; ok {
+		t.Errorf("WithOwnerTag() modified the resource tags")
+	}
+	for owner, want := range map[string]bool{"": false, "green": false, "blue": true} {
+		if got := IsOwnedElsewhere(owner); got != want {
+			t.Errorf("IsOwnedElsewhere(%q) = %v, want %v", owner, got, want)
+		}
+	}
+
+	for value, want := range map[string]bool{"": false, "true": true, "false": false, "yes": false} {
+		obj := &metav1.ObjectMeta{Annotations: map[string]string{TakeoverAnnotation: value}}
+		if got := IsTakeover(obj); got != want {
+			t.Errorf("IsTakeover(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	ackrt "github.com/aws-controllers-k8s/runtime/pkg/runtime"
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	rtclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// NamespaceTagAnnotationPrefix is the prefix of the Namespace annotations
+	// propagated as tags to the resources of the namespace. The annotation
+	// "tags.bedrockagent.services.k8s.aws/team: ml" adds the tag "team=ml".
+	NamespaceTagAnnotationPrefix = "tags.bedrockagent.services.k8s.aws/"
+	// DefaultTagsConfigMapName is the default name of the ConfigMap, in the
+	// controller's namespace, whose data holds the cluster default tags.
+	DefaultTagsConfigMapName = "ack-bedrockagent-default-tags"
+)
+
+// Propagator reads the tags resources inherit from their namespace and from
+// the cluster default tags ConfigMap. It is meant to read through an informer
+// cache restricted with CacheByObject, as the tags are read on every
+// reconciliation.
+type Propagator struct {
+	reader    rtclient.Reader
+	configMap types.NamespacedName
+}
+
+// NewPropagator returns a Propagator reading Namespaces and the cluster
+// default tags ConfigMap with the given reader. An empty ConfigMap name
+// disables the cluster default tags.
+func NewPropagator(
+	reader rtclient.Reader,
+	configMap types.NamespacedName,
+) *Propagator {
+	return &Propagator{
+		reader:    reader,
+		configMap: configMap,
+	}
+}
+
+// CacheByObject returns the cache.Options.ByObject restricting the
+// ConfigMaps of an informer cache to the cluster default tags ConfigMap, the
+// only one the Propagator reads.
+func CacheByObject(configMap types.NamespacedName) map[rtclient.Object]cache.ByObject {
+	return map[rtclient.Object]cache.ByObject{
+		&corev1.ConfigMap{}: {
+			Namespaces: map[string]cache.Config{configMap.Namespace: {}},
+			Field:      fields.OneTermEqualSelector("metadata.name", configMap.Name),
+		},
+	}
+}
+
+var propagator *Propagator
+
+// SetPropagator sets the Propagator used by the resource managers, which the
+// controller creates at startup, see pkg/controller. Tag propagation is
+// disabled until then.
+func SetPropagator(p *Propagator) {
+	propagator = p
+}
+
+// GetPropagator returns the Propagator set with SetPropagator, or nil.
+func GetPropagator() *Propagator {
+	return propagator
+}
+
+// InheritedTags returns the unexpanded tags inherited by the resources of the
+// namespace. Namespace tags take precedence over the cluster default tags.
+// A missing Namespace or ConfigMap contributes no tags.
+func (p *Propagator) InheritedTags(
+	ctx context.Context,
+	namespace string,
+) (map[string]string, error) {
+	inherited := map[string]string{}
+	if p.configMap.Name != "" {
+		configMap := &corev1.ConfigMap{}
+		err := p.reader.Get(ctx, p.configMap, configMap)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		for key, value := range configMap.Data {
+			inherited[key] = value
+		}
+	}
+	ns := &corev1.Namespace{}
+	err := p.reader.Get(ctx, types.NamespacedName{Name: namespace}, ns)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, err
+	}
+	for annotation, value := range ns.GetAnnotations() {
+		if key, ok := strings.CutPrefix(annotation, NamespaceTagAnnotationPrefix); ok && key != "" {
+			inherited[key] = value
+		}
+	}
+	return inherited, nil
+}
+
+// MergeInheritedTags merges the inherited tags into the resource tags and
+// returns the merged tags along with the sorted keys that were inherited.
+// Resource tags take precedence over inherited tags. Inherited values are
+// expanded like the controller default tags (e.g. %K8S_NAMESPACE%), and tags
+// whose expanded value is empty are skipped.
+func MergeInheritedTags(
+	resourceTags map[string]*string,
+	inherited map[string]string,
+	obj rtclient.Object,
+	md acktypes.ServiceControllerMetadata,
+) (map[string]*string, []string) {
+	merged := make(map[string]*string, len(resourceTags)+len(inherited))
+	for key, value := range resourceTags {
+		merged[key] = value
+	}
+	inheritedKeys := []string{}
+	for key, value := range inherited {
+		if _, ok := merged[key]; ok {
+			continue
+		}
+		for format, resolve := range ackrt.ACKResourceTagFormats {
+			value = strings.ReplaceAll(value, format, resolve(obj, md))
+		}
+		if value == "" {
+			continue
+		}
+		merged[key] = &value
+		inheritedKeys = append(inheritedKeys, key)
+	}
+	sort.Strings(inheritedKeys)
+	return merged, inheritedKeys
+}
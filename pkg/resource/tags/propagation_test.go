@@ -0,0 +1,133 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	acktypes "github.com/aws-controllers-k8s/runtime/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPropagator_InheritedTags(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ack-system", Name: DefaultTagsConfigMapName},
+		Data: map[string]string{
+			"cost-center": "1234",
+			"team":        "platform",
+		},
+	}
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "ml",
+			Annotations: map[string]string{
+				NamespaceTagAnnotationPrefix + "team":  "ml",
+				NamespaceTagAnnotationPrefix + "stage": "%K8S_NAMESPACE%-prod",
+				NamespaceTagAnnotationPrefix:           "ignored",
+				"unrelated":                            "ignored",
+			},
+		},
+	}
+	reader := fake.NewClientBuilder().WithObjects(configMap, namespace).Build()
+	configMapKey := types.NamespacedName{Namespace: "ack-system", Name: DefaultTagsConfigMapName}
+
+	tests := []struct {
+		name      string
+		configMap types.NamespacedName
+		namespace string
+		want      map[string]string
+	}{
+		{
+			name:      "namespace tags override cluster tags",
+			configMap: configMapKey,
+			namespace: "ml",
+			want: map[string]string{
+				"cost-center": "1234",
+				"stage":       "%K8S_NAMESPACE%-prod",
+				"team":        "ml",
+			},
+		},
+		{
+			name:      "missing namespace",
+			configMap: configMapKey,
+			namespace: "other",
+			want: map[string]string{
+				"cost-center": "1234",
+				"team":        "platform",
+			},
+		},
+		{
+			name:      "cluster tags disabled",
+			namespace: "ml",
+			want: map[string]string{
+				"stage": "%K8S_NAMESPACE%-prod",
+				"team":  "ml",
+			},
+		},
+		{
+			name:      "missing config map",
+			configMap: types.NamespacedName{Namespace: "ack-system", Name: "missing"},
+			namespace: "other",
+			want:      map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPropagator(reader, tt.configMap)
+			got, err := p.InheritedTags(context.Background(), tt.namespace)
+			if err != nil {
+				t.Fatalf("InheritedTags() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("InheritedTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeInheritedTags(t *testing.T) {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "ml", Name: "my-agent"}}
+	resourceTags := map[string]*string{
+		"team": stringPtr("search"),
+	}
+	inherited := map[string]string{
+		"team":        "ml",
+		"stage":       "%K8S_NAMESPACE%-prod",
+		"owner":       "%K8S_RESOURCE_NAME%",
+		"cost-center": "1234",
+		"empty":       "",
+	}
+
+	merged, inheritedKeys := MergeInheritedTags(resourceTags, inherited, obj, acktypes.ServiceControllerMetadata{})
+	want := map[string]*string{
+		"team":        stringPtr("search"),
+		"stage":       stringPtr("ml-prod"),
+		"owner":       stringPtr("my-agent"),
+		"cost-center": stringPtr("1234"),
+	}
+	if !EqualTags(merged, want) || len(merged) != len(want) {
+		t.Errorf("MergeInheritedTags() merged = %v, want %v", merged, want)
+	}
+	if !reflect.DeepEqual(inheritedKeys, []string{"cost-center", "owner", "stage"}) {
+		t.Errorf("MergeInheritedTags() inheritedKeys = %v, want [cost-center owner stage]", inheritedKeys)
+	}
+	if *resourceTags["team"] != "search" || len(resourceTags) != 1 {
+		t.Errorf("MergeInheritedTags() modified the resource tags: %v", resourceTags)
+	}
+}
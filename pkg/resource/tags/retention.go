@@ -0,0 +1,41 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+// RetainedTagKey is the key of the tag marking the resources kept in AWS
+// when their custom resource is deleted, i.e. whose deletion policy is
+// retain, so that the orphan sweeper leaves them alone.
+const RetainedTagKey = "bedrockagent.services.k8s.aws/retained"
+
+// WithRetainedTag returns a copy of the resource tags with the RetainedTagKey
+// tag when retained, and without it otherwise.
+func WithRetainedTag(resourceTags map[string]*string, retained bool) map[string]*string {
+	marked := make(map[string]*string, len(resourceTags)+1)
+	for key, value := range resourceTags {
+		marked[key] = value
+	}
+	delete(marked, RetainedTagKey)
+	if retained {
+		value := "true"
+		marked[RetainedTagKey] = &value
+	}
+	return marked
+}
+
+// IsRetained returns whether the resource with the given tags carries the
+// RetainedTagKey tag.
+func IsRetained(resourceTags map[string]string) bool {
+	_, ok := resourceTags[RetainedTagKey]
+	return ok
+}
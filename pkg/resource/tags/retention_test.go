@@ -0,0 +1,38 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import "testing"
+
+func TestRetention(t *testing.T) {
+	resourceTags := map[string]*string{"team": stringPtr("ml")}
+
+	retained := WithRetainedTag(resourceTags, true)
+	if len(retained) != 2 || stringValue(retained[RetainedTagKey]) != "true" {
+		t.Errorf("WithRetainedTag(true) = %v, want the team tag and the retained tag", retained)
+	}
+	if _, ok := resourceTags[RetainedTagKey]; ok {
+		t.Errorf("WithRetainedTag() modified the resource tags")
+	}
+	if got := WithRetainedTag(retained, false); len(got) != 1 || got["team"] == nil {
+		t.Errorf("WithRetainedTag(false) = %v, want the team tag only", got)
+	}
+
+	if !IsRetained(map[string]string{RetainedTagKey: "true"}) {
+		t.Errorf("IsRetained() of a retained resource = false")
+	}
+	if IsRetained(map[string]string{"team": "ml"}) {
+		t.Errorf("IsRetained() of a resource without the retained tag = true")
+	}
+}
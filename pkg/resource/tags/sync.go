@@ -15,11 +15,59 @@ package tags
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	ackrtlog "github.com/aws-controllers-k8s/runtime/pkg/runtime/log"
 	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	"github.com/aws/smithy-go"
 )
 
+// MaxTagsPerRequest is the maximum number of tags, or tag keys, that can be
+// sent in a single TagResource or UntagResource request.
+const MaxTagsPerRequest = 50
+
+// syncRequeueDelay is how long the reconciler waits before retrying a sync
+// whose batches failed with retryable errors.
+const syncRequeueDelay = 10 * time.Second
+
+// SyncError is returned by SyncResourceTags when some tags could not be
+// synchronised.
+type SyncError struct {
+	// FailedToTag are the keys of the tags that could not be added or
+	// updated.
+	FailedToTag []string
+	// FailedToUntag are the keys of the tags that could not be removed.
+	FailedToUntag []string
+	// Postponed are the keys of the tags whose removal was skipped because
+	// other tags could not be added or updated.
+	Postponed []string
+	// Err is the last error returned by the tagging API.
+	Err error
+}
+
+func (e *SyncError) Error() string {
+	var problems []string
+	if len(e.FailedToTag) > 0 {
+		problems = append(problems, fmt.Sprintf("failed to add or update tags %v", e.FailedToTag))
+	}
+	if len(e.FailedToUntag) > 0 {
+		problems = append(problems, fmt.Sprintf("failed to remove tags %v", e.FailedToUntag))
+	}
+	if len(e.Postponed) > 0 {
+		problems = append(problems, fmt.Sprintf("postponed removal of tags %v", e.Postponed))
+	}
+	return fmt.Sprintf("%s: %v", strings.Join(problems, "; "), e.Err)
+}
+
+func (e *SyncError) Unwrap() error {
+	return e.Err
+}
+
 type metricsRecorder interface {
 	RecordAPICall(opType string, opID string, err error)
 }
@@ -57,7 +105,14 @@ func GetResourceTags(
 }
 
 // SyncResourceTags uses TagResource and UntagResource API Calls to add, remove
-// and update resource tags.
+// and update resource tags. Tags are added and updated before any tag is
+// removed, so that a failure never leaves the resource with fewer tags than
+// both the desired and the latest sets. Tags are sent in batches of at most
+// MaxTagsPerRequest and the tags of failed batches are reported in a
+// *SyncError. When every failure can be fixed by retrying, the *SyncError is
+// wrapped in a requeue error so that the reconciler retries the sync later.
+// Removals are postponed when any tag could not be added or updated. The tags
+// to add or update are validated before any call is made.
 func SyncResourceTags(
 	ctx context.Context,
 	client tagsClient,
@@ -73,53 +128,123 @@ func SyncResourceTags(
 		exit(err)
 	}()
 
-	addedOrUpdated, removed := computeTagsDelta(desiredTags, latestTags)
+	addedOrUpdated, removed := ComputeTagsDelta(desiredTags, latestTags)
+	if err = validateTags(addedOrUpdated); err != nil {
+		return err
+	}
 
-	if len(removed) > 0 {
-		_, err = client.UntagResource(
+	syncErr := &SyncError{}
+	retryable := true
+	for _, batch := range batchTags(addedOrUpdated) {
+		_, batchErr := client.TagResource(
 			ctx,
-			&svcsdk.UntagResourceInput{
+			&svcsdk.TagResourceInput{
 				ResourceArn: &resourceARN,
-				TagKeys:     removed,
+				Tags:        batch,
 			},
 		)
-		mr.RecordAPICall("UPDATE", "UntagResource", err)
-		if err != nil {
-			return err
+		mr.RecordAPICall("UPDATE", "TagResource", batchErr)
+		if batchErr != nil {
+			for key := range batch {
+				syncErr.FailedToTag = append(syncErr.FailedToTag, key)
+			}
+			syncErr.Err = batchErr
+			retryable = retryable && isRetryable(batchErr)
 		}
 	}
 
-	if len(addedOrUpdated) > 0 {
-		_, err = client.TagResource(
+	sort.Strings(removed)
+	if len(syncErr.FailedToTag) > 0 {
+		syncErr.Postponed = removed
+		removed = nil
+	}
+	for _, batch := range batchKeys(removed) {
+		_, batchErr := client.UntagResource(
 			ctx,
-			&svcsdk.TagResourceInput{
+			&svcsdk.UntagResourceInput{
 				ResourceArn: &resourceARN,
-				Tags:        addedOrUpdated,
+				TagKeys:     batch,
 			},
 		)
-		mr.RecordAPICall("UPDATE", "TagResource", err)
-		if err != nil {
-			return err
+		mr.RecordAPICall("UPDATE", "UntagResource", batchErr)
+		if batchErr != nil {
+			syncErr.FailedToUntag = append(syncErr.FailedToUntag, batch...)
+			syncErr.Err = batchErr
+			retryable = retryable && isRetryable(batchErr)
 		}
 	}
+
+	if syncErr.Err != nil {
+		sort.Strings(syncErr.FailedToTag)
+		err = syncErr
+		if retryable {
+			err = ackrequeue.NeededAfter(syncErr, syncRequeueDelay)
+		}
+		return err
+	}
 	return nil
 }
 
-// computeTagsDelta compares two Tag arrays and return two different list
+// isRetryable returns false for the errors returned when the request itself
+// is invalid.
+func isRetryable(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ValidationException", "AccessDeniedException", "ResourceNotFoundException":
+			return false
+		}
+	}
+	return true
+}
+
+// batchKeys splits sorted tag keys into batches of at most MaxTagsPerRequest.
+func batchKeys(keys []string) [][]string {
+	var batches [][]string
+	for len(keys) > MaxTagsPerRequest {
+		batches = append(batches, keys[:MaxTagsPerRequest])
+		keys = keys[MaxTagsPerRequest:]
+	}
+	if len(keys) > 0 {
+		batches = append(batches, keys)
+	}
+	return batches
+}
+
+// batchTags splits tags into batches of at most MaxTagsPerRequest, filling
+// the batches in key order so that the requests are deterministic.
+func batchTags(tags map[string]string) []map[string]string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var batches []map[string]string
+	for _, keyBatch := range batchKeys(keys) {
+		batch := make(map[string]string, len(keyBatch))
+		for _, key := range keyBatch {
+			batch[key] = tags[key]
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// ComputeTagsDelta compares two Tag arrays and return two different list
 // containing the addedOrupdated and removed tags. The removed tags array
-// only contains the tags Keys.
-func computeTagsDelta(
+// only contains the tags Keys. Nil tag values are treated as empty strings.
+func ComputeTagsDelta(
 	a map[string]*string,
 	b map[string]*string,
 ) (addedOrUpdated map[string]string, removed []string) {
 
 	// Find the keys in the Spec have either been added or updated.
 	for key, value := range a {
-		if bValue, exists := b[key]; !exists || *value != *bValue {
+		if bValue, exists := b[key]; !exists || stringValue(value) != stringValue(bValue) {
 			if addedOrUpdated == nil {
 				addedOrUpdated = make(map[string]string)
 			}
-			addedOrUpdated[key] = *value
+			addedOrUpdated[key] = stringValue(value)
 		}
 	}
 
@@ -138,6 +263,13 @@ func EqualTags(
 	a map[string]*string,
 	b map[string]*string,
 ) bool {
-	addedOrUpdated, removed := computeTagsDelta(a, b)
+	addedOrUpdated, removed := ComputeTagsDelta(a, b)
 	return len(addedOrUpdated) == 0 && len(removed) == 0
 }
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -16,10 +16,15 @@ package tags
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
 	"testing"
 
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+	ackrequeue "github.com/aws-controllers-k8s/runtime/pkg/requeue"
 	svcsdk "github.com/aws/aws-sdk-go-v2/service/bedrockagent"
+	"github.com/aws/smithy-go"
 )
 
 // mockTagsClient is a mock implementation of the tagsClient interface
@@ -223,13 +228,13 @@ func TestSyncResourceTags(t *testing.T) {
 					recordAPICallFunc: func(opType string, opID string, err error) {
 						callCount++
 						if callCount == 1 {
-							if opType != "UPDATE" || opID != "UntagResource" || err != nil {
-								t.Errorf("Expected RecordAPICall(UPDATE, UntagResource, nil), got RecordAPICall(%s, %s, %v)", opType, opID, err)
-							}
-						} else if callCount == 2 {
 							if opType != "UPDATE" || opID != "TagResource" || err != nil {
 								t.Errorf("Expected RecordAPICall(UPDATE, TagResource, nil), got RecordAPICall(%s, %s, %v)", opType, opID, err)
 							}
+						} else if callCount == 2 {
+							if opType != "UPDATE" || opID != "UntagResource" || err != nil {
+								t.Errorf("Expected RecordAPICall(UPDATE, UntagResource, nil), got RecordAPICall(%s, %s, %v)", opType, opID, err)
+							}
 						}
 					},
 				}
@@ -390,17 +395,17 @@ func TestComputeTagsDelta(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotAddOrUpdate, gotRemoved := computeTagsDelta(tt.a, tt.b)
+			gotAddOrUpdate, gotRemoved := ComputeTagsDelta(tt.a, tt.b)
 
 			// Check added or updated tags
 			if !reflect.DeepEqual(gotAddOrUpdate, tt.wantAddOrUpdate) {
-				t.Errorf("computeTagsDelta() addedOrUpdated = %v, want %v", gotAddOrUpdate, tt.wantAddOrUpdate)
+				t.Errorf("ComputeTagsDelta() addedOrUpdated = %v, want %v", gotAddOrUpdate, tt.wantAddOrUpdate)
 			}
 
 			// For removed tags, we need to check if all expected keys are present
 			// regardless of order
 			if len(gotRemoved) != len(tt.wantRemoved) {
-				t.Errorf("computeTagsDelta() removed length = %d, want %d", len(gotRemoved), len(tt.wantRemoved))
+				t.Errorf("ComputeTagsDelta() removed length = %d, want %d", len(gotRemoved), len(tt.wantRemoved))
 			} else {
 				removedMap := make(map[string]bool)
 				for _, key := range gotRemoved {
@@ -409,7 +414,7 @@ func TestComputeTagsDelta(t *testing.T) {
 
 				for _, key := range tt.wantRemoved {
 					if !removedMap[key] {
-						t.Errorf("computeTagsDelta() removed does not contain key %s", key)
+						t.Errorf("ComputeTagsDelta() removed does not contain key %s", key)
 					}
 				}
 			}
@@ -497,6 +502,246 @@ func TestEqualTags(t *testing.T) {
 	}
 }
 
+func TestSyncResourceTags_Batching(t *testing.T) {
+	desiredTags := map[string]*string{}
+	for i := 0; i < 120; i++ {
+		desiredTags[fmt.Sprintf("new-%03d", i)] = stringPtr("value")
+	}
+	latestTags := map[string]*string{}
+	for i := 0; i < 60; i++ {
+		latestTags[fmt.Sprintf("old-%03d", i)] = stringPtr("value")
+	}
+
+	var tagBatches, untagBatches []int
+	seen := map[string]bool{}
+	client := &mockTagsClient{
+		tagResourceFunc: func(ctx context.Context, input *svcsdk.TagResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.TagResourceOutput, error) {
+			tagBatches = append(tagBatches, len(input.Tags))
+			for key := range input.Tags {
+				if seen[key] {
+					t.Errorf("Tag %s sent more than once", key)
+				}
+				seen[key] = true
+			}
+			return &svcsdk.TagResourceOutput{}, nil
+		},
+		untagResourceFunc: func(ctx context.Context, input *svcsdk.UntagResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.UntagResourceOutput, error) {
+			untagBatches = append(untagBatches, len(input.TagKeys))
+			return &svcsdk.UntagResourceOutput{}, nil
+		},
+	}
+	mr := &mockMetricsRecorder{
+		recordAPICallFunc: func(opType string, opID string, err error) {},
+	}
+
+	err := SyncResourceTags(context.Background(), client, mr, "arn:aws:bedrock:us-west-2:123456789012:agent/test-agent", desiredTags, latestTags)
+	if err != nil {
+		t.Fatalf("SyncResourceTags() error = %v", err)
+	}
+	if !reflect.DeepEqual(tagBatches, []int{50, 50, 20}) {
+		t.Errorf("TagResource batch sizes = %v, want [50 50 20]", tagBatches)
+	}
+	if !reflect.DeepEqual(untagBatches, []int{50, 10}) {
+		t.Errorf("UntagResource batch sizes = %v, want [50 10]", untagBatches)
+	}
+	if len(seen) != len(desiredTags) {
+		t.Errorf("Expected %d tags to be sent, got %d", len(desiredTags), len(seen))
+	}
+}
+
+func TestSyncResourceTags_InvalidTags(t *testing.T) {
+	client := &mockTagsClient{
+		tagResourceFunc: func(ctx context.Context, input *svcsdk.TagResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.TagResourceOutput, error) {
+			t.Error("TagResource should not be called")
+			return nil, nil
+		},
+		untagResourceFunc: func(ctx context.Context, input *svcsdk.UntagResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.UntagResourceOutput, error) {
+			t.Error("UntagResource should not be called")
+			return nil, nil
+		},
+	}
+	mr := &mockMetricsRecorder{
+		recordAPICallFunc: func(opType string, opID string, err error) {
+			t.Errorf("No API call should be recorded, got RecordAPICall(%s, %s, %v)", opType, opID, err)
+		},
+	}
+
+	err := SyncResourceTags(
+		context.Background(),
+		client,
+		mr,
+		"arn:aws:bedrock:us-west-2:123456789012:agent/test-agent",
+		map[string]*string{"aws:owner": stringPtr("me")},
+		map[string]*string{"key1": stringPtr("value1")},
+	)
+	var terminalErr *ackerr.TerminalError
+	if !errors.As(err, &terminalErr) {
+		t.Errorf("SyncResourceTags() error = %v, want a terminal error", err)
+	}
+}
+
+func TestSyncResourceTags_Failures(t *testing.T) {
+	const resourceARN = "arn:aws:bedrock:us-west-2:123456789012:agent/test-agent"
+	apiErr := errors.New("API error")
+	validationErr := &smithy.GenericAPIError{Code: "ValidationException", Message: "invalid"}
+
+	manyTags := func(prefix string, n int) map[string]*string {
+		tags := map[string]*string{}
+		for i := 0; i < n; i++ {
+			tags[fmt.Sprintf("%s-%03d", prefix, i)] = stringPtr("value")
+		}
+		return tags
+	}
+
+	tests := []struct {
+		name        string
+		desiredTags map[string]*string
+		latestTags  map[string]*string
+		// tagErrs and untagErrs are returned by successive calls; calls
+		// beyond the end of the slice succeed.
+		tagErrs           []error
+		untagErrs         []error
+		wantTagCalls      int
+		wantUntagCalls    int
+		wantFailedToTag   []string
+		wantFailedToUntag []string
+		wantPostponed     []string
+		wantErr           bool
+		wantRequeue       bool
+	}{
+		{
+			name:            "tag failure postpones removals and requeues",
+			desiredTags:     map[string]*string{"key1": stringPtr("value1")},
+			latestTags:      map[string]*string{"key2": stringPtr("value2"), "key3": stringPtr("value3")},
+			tagErrs:         []error{apiErr},
+			wantTagCalls:    1,
+			wantFailedToTag: []string{"key1"},
+			wantPostponed:   []string{"key2", "key3"},
+			wantErr:         true,
+			wantRequeue:     true,
+		},
+		{
+			name:            "validation exception is not requeued",
+			desiredTags:     map[string]*string{"key1": stringPtr("value1")},
+			tagErrs:         []error{validationErr},
+			wantTagCalls:    1,
+			wantFailedToTag: []string{"key1"},
+			wantErr:         true,
+		},
+		{
+			name:        "partial tag failure reports the failed batch",
+			desiredTags: manyTags("key", 60),
+			// The first batch (key-000 to key-049) succeeds, the second
+			// batch fails.
+			tagErrs:         []error{nil, apiErr},
+			wantTagCalls:    2,
+			wantFailedToTag: []string{"key-050", "key-051", "key-052", "key-053", "key-054", "key-055", "key-056", "key-057", "key-058", "key-059"},
+			wantErr:         true,
+			wantRequeue:     true,
+		},
+		{
+			name:            "non-retryable failure in any batch is not requeued",
+			desiredTags:     manyTags("key", 60),
+			tagErrs:         []error{validationErr, apiErr},
+			wantTagCalls:    2,
+			wantFailedToTag: sortedKeys(manyTags("key", 60)),
+			wantErr:         true,
+		},
+		{
+			name:              "untag failure keeps added tags and requeues",
+			desiredTags:       map[string]*string{"key1": stringPtr("value1")},
+			latestTags:        map[string]*string{"key2": stringPtr("value2")},
+			untagErrs:         []error{apiErr},
+			wantTagCalls:      1,
+			wantUntagCalls:    1,
+			wantFailedToUntag: []string{"key2"},
+			wantErr:           true,
+			wantRequeue:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagCalls, untagCalls := 0, 0
+			client := &mockTagsClient{
+				tagResourceFunc: func(ctx context.Context, input *svcsdk.TagResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.TagResourceOutput, error) {
+					tagCalls++
+					if untagCalls > 0 {
+						t.Error("TagResource called after UntagResource")
+					}
+					if tagCalls <= len(tt.tagErrs) && tt.tagErrs[tagCalls-1] != nil {
+						return nil, tt.tagErrs[tagCalls-1]
+					}
+					return &svcsdk.TagResourceOutput{}, nil
+				},
+				untagResourceFunc: func(ctx context.Context, input *svcsdk.UntagResourceInput, opts ...func(*svcsdk.Options)) (*svcsdk.UntagResourceOutput, error) {
+					untagCalls++
+					if untagCalls <= len(tt.untagErrs) && tt.untagErrs[untagCalls-1] != nil {
+						return nil, tt.untagErrs[untagCalls-1]
+					}
+					return &svcsdk.UntagResourceOutput{}, nil
+				},
+			}
+			mr := &mockMetricsRecorder{
+				recordAPICallFunc: func(opType string, opID string, err error) {},
+			}
+
+			err := SyncResourceTags(context.Background(), client, mr, resourceARN, tt.desiredTags, tt.latestTags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SyncResourceTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tagCalls != tt.wantTagCalls {
+				t.Errorf("TagResource called %d times, want %d", tagCalls, tt.wantTagCalls)
+			}
+			if untagCalls != tt.wantUntagCalls {
+				t.Errorf("UntagResource called %d times, want %d", untagCalls, tt.wantUntagCalls)
+			}
+			if err == nil {
+				return
+			}
+			var requeueErr *ackrequeue.RequeueNeededAfter
+			if errors.As(err, &requeueErr) != tt.wantRequeue {
+				t.Errorf("SyncResourceTags() error = %v, wantRequeue %v", err, tt.wantRequeue)
+			}
+			var syncErr *SyncError
+			if !errors.As(err, &syncErr) {
+				t.Fatalf("SyncResourceTags() error = %v, want a *SyncError", err)
+			}
+			if !reflect.DeepEqual(syncErr.FailedToTag, tt.wantFailedToTag) {
+				t.Errorf("FailedToTag = %v, want %v", syncErr.FailedToTag, tt.wantFailedToTag)
+			}
+			if !reflect.DeepEqual(syncErr.FailedToUntag, tt.wantFailedToUntag) {
+				t.Errorf("FailedToUntag = %v, want %v", syncErr.FailedToUntag, tt.wantFailedToUntag)
+			}
+			if !reflect.DeepEqual(syncErr.Postponed, tt.wantPostponed) {
+				t.Errorf("Postponed = %v, want %v", syncErr.Postponed, tt.wantPostponed)
+			}
+		})
+	}
+}
+
+func TestSyncError_Error(t *testing.T) {
+	err := &SyncError{
+		FailedToTag:   []string{"key1"},
+		FailedToUntag: []string{"key2"},
+		Postponed:     []string{"key3"},
+		Err:           errors.New("API error"),
+	}
+	want := "failed to add or update tags [key1]; failed to remove tags [key2]; postponed removal of tags [key3]: API error"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+// sortedKeys returns the keys of tags in order.
+func sortedKeys(tags map[string]*string) []string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // Helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
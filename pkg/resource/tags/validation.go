@@ -0,0 +1,87 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	ackerr "github.com/aws-controllers-k8s/runtime/pkg/errors"
+)
+
+const (
+	// MaxKeyLength is the maximum length, in characters, of a tag key.
+	MaxKeyLength = 128
+	// MaxValueLength is the maximum length, in characters, of a tag value.
+	MaxValueLength = 256
+	// reservedKeyPrefix is reserved for tags managed by AWS. Tag keys
+	// starting with it, in any case, are rejected by TagResource.
+	reservedKeyPrefix = "aws:"
+)
+
+// tagRegex matches the characters Bedrock accepts in tag keys and values.
+var tagRegex = regexp.MustCompile(`^[a-zA-Z0-9\s._:/=+@-]*$`)
+
+// ValidateTag returns an error if the tag would be rejected by TagResource.
+func ValidateTag(key string, value string) error {
+	switch {
+	case key == "":
+		return errors.New("tag key must not be empty")
+	case utf8.RuneCountInString(key) > MaxKeyLength:
+		return fmt.Errorf("tag key %q is longer than %d characters", key, MaxKeyLength)
+	case strings.HasPrefix(strings.ToLower(key), reservedKeyPrefix):
+		return fmt.Errorf("tag key %q uses the reserved prefix %q", key, reservedKeyPrefix)
+	case !tagRegex.MatchString(key):
+		return fmt.Errorf("tag key %q must match %s", key, tagRegex)
+	case utf8.RuneCountInString(value) > MaxValueLength:
+		return fmt.Errorf("value of tag %q is longer than %d characters", key, MaxValueLength)
+	case !tagRegex.MatchString(value):
+		return fmt.Errorf("value of tag %q must match %s", key, tagRegex)
+	}
+	return nil
+}
+
+// ValidateTags validates every tag and returns all problems found. Nil values
+// are treated as empty strings.
+func ValidateTags(tags map[string]*string) error {
+	values := make(map[string]string, len(tags))
+	for key, value := range tags {
+		values[key] = stringValue(value)
+	}
+	return validateTags(values)
+}
+
+// validateTags validates the tags in key order and wraps the problems found
+// in a terminal error, since retrying the request can't fix them.
+func validateTags(tags map[string]string) error {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var errs []error
+	for _, key := range keys {
+		if err := ValidateTag(key, tags[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return ackerr.NewTerminalError(errors.Join(errs...))
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tags
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		value   string
+		wantErr bool
+	}{
+		{name: "valid tag", key: "team", value: "ml-platform"},
+		{name: "empty value", key: "team", value: ""},
+		{name: "maximum lengths", key: strings.Repeat("k", MaxKeyLength), value: strings.Repeat("v", MaxValueLength)},
+		{name: "empty key", key: "", value: "value", wantErr: true},
+		{name: "key too long", key: strings.Repeat("k", MaxKeyLength+1), value: "value", wantErr: true},
+		{name: "value too long", key: "team", value: strings.Repeat("v", MaxValueLength+1), wantErr: true},
+		{name: "reserved prefix", key: "aws:team", value: "value", wantErr: true},
+		{name: "reserved prefix in upper case", key: "AWS:team", value: "value", wantErr: true},
+		{name: "invalid key characters", key: "team#1", value: "value", wantErr: true},
+		{name: "invalid value characters", key: "team", value: "ml*", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTag(tt.key, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	err := ValidateTags(map[string]*string{
+		"team":    stringPtr("ml-platform"),
+		"aws:env": stringPtr("prod"),
+		"owner":   nil,
+		"":        stringPtr("value"),
+	})
+	if err == nil {
+		t.Fatal("ValidateTags() error = nil, want an error")
+	}
+	for _, want := range []string{"must not be empty", "reserved prefix"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ValidateTags() error = %q, want it to contain %q", err, want)
+		}
+	}
+
+	if err := ValidateTags(map[string]*string{"team": stringPtr("ml-platform")}); err != nil {
+		t.Errorf("ValidateTags() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,593 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package fakebedrockagent provides an in-memory fake of the Bedrock Agent
+// API for tests. It serves the REST-JSON protocol used by the
+// aws-sdk-go-v2 bedrockagent client, so the controller can be pointed at it
+// with a custom endpoint and exercised without network access or AWS
+// credentials.
+//
+// Only the operations used by the Agent resource manager are implemented:
+// CreateAgent, GetAgent, UpdateAgent, PrepareAgent, DeleteAgent, the tagging
+// operations and empty listings of action groups and knowledge bases.
+//
+// Agent statuses follow the asynchronous transitions of the real service.
+// An in-progress status is returned by one GetAgent call, and the operation
+// completes before the next one:
+//
+//	CREATING  -> NOT_PREPARED
+//	PREPARING -> PREPARED
+//	DELETING  -> (deleted)
+//
+// Like the real service, the server fills in the default prompt override
+// configuration once the agent is created, so it is only returned by GetAgent
+// and must be late initialized.
+//
+// Replay makes the server answer an operation with a recorded response
+// instead, and Request returns the last request of an operation, so that the
+// controller's requests and its handling of responses can be compared to
+// golden files.
+package fakebedrockagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+const (
+	// Region is the region of the agent ARNs returned by the server.
+	Region = "us-west-2"
+	// AccountID is the account of the agent ARNs returned by the server.
+	AccountID = "123456789012"
+
+	draftVersion = "DRAFT"
+)
+
+// Agent statuses, as returned in the agentStatus field.
+const (
+	StatusCreating    = "CREATING"
+	StatusNotPrepared = "NOT_PREPARED"
+	StatusPreparing   = "PREPARING"
+	StatusPrepared    = "PREPARED"
+	StatusDeleting    = "DELETING"
+	StatusFailed      = "FAILED"
+)
+
+// systemFields are the agent fields set by the service rather than by the
+// CreateAgent and UpdateAgent requests.
+var systemFields = []string{
+	"agentArn",
+	"agentId",
+	"agentStatus",
+	"agentVersion",
+	"createdAt",
+	"preparedAt",
+	"updatedAt",
+}
+
+// RequestID returns the request ID of the nth request served by a Server,
+// counting from 1.
+func RequestID(n int) string {
+	return fmt.Sprintf("fake-request-%04d", n)
+}
+
+// Server is an in-memory Bedrock Agent API served over HTTP.
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	nextID        int
+	nextRequestID int
+	agents        map[string]map[string]any
+	tags          map[string]map[string]string
+	calls         []string
+	replays       map[string][]byte
+	requests      map[string][]byte
+
+	// observed records the agents whose current status has been returned
+	// by GetAgent.
+	observed map[string]bool
+}
+
+// NewServer starts a Server. It is closed when the test ends.
+func NewServer(t interface{ Cleanup(func()) }) *Server {
+	s := &Server{
+		agents:   map[string]map[string]any{},
+		tags:     map[string]map[string]string{},
+		replays:  map[string][]byte{},
+		requests: map[string][]byte{},
+		observed: map[string]bool{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Config returns an aws.Config whose clients send their requests to the
+// server.
+func (s *Server) Config() aws.Config {
+	return aws.Config{
+		Region:       Region,
+		BaseEndpoint: aws.String(s.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		HTTPClient:   s.Client(),
+	}
+}
+
+// Calls returns the names of the operations served so far, in order.
+func (s *Server) Calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.calls...)
+}
+
+// ResetCalls clears the recorded operation names.
+func (s *Server) ResetCalls() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+// Agent returns a copy of the stored agent, as it would be returned by
+// GetAgent but without advancing its status.
+func (s *Server) Agent(agentID string) (map[string]any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	agent, ok := s.agents[agentID]
+	if !ok {
+		return nil, false
+	}
+	return copyFields(agent), true
+}
+
+// SetAgentStatus forces the status of an agent, e.g. to FAILED.
+func (s *Server) SetAgentStatus(agentID string, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if agent, ok := s.agents[agentID]; ok {
+		s.setStatus(agentID, agent, status)
+	}
+}
+
+// Tags returns a copy of the tags of a resource.
+func (s *Server) Tags(resourceARN string) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tags := map[string]string{}
+	for key, value := range s.tags[resourceARN] {
+		tags[key] = value
+	}
+	return tags
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextRequestID++
+	w.Header().Set("X-Amzn-RequestId", RequestID(s.nextRequestID))
+
+	operation, resource := operationOf(r)
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "ValidationException", err.Error())
+		return
+	}
+	s.requests[operation] = raw
+	if response, ok := s.replays[operation]; ok {
+		s.replay(w, operation, response)
+		return
+	}
+
+	var body map[string]any
+	if len(raw) > 0 && (r.Method == http.MethodPut || r.Method == http.MethodPost) {
+		if err := json.Unmarshal(raw, &body); err != nil {
+			writeError(w, http.StatusBadRequest, "ValidationException", err.Error())
+			return
+		}
+	}
+
+	switch operation {
+	case "CreateAgent":
+		s.createAgent(w, body)
+	case "ListAgents":
+		s.listAgents(w)
+	case "GetAgent":
+		s.getAgent(w, resource)
+	case "UpdateAgent":
+		s.updateAgent(w, resource, body)
+	case "PrepareAgent":
+		s.prepareAgent(w, resource)
+	case "DeleteAgent":
+		s.deleteAgent(w, resource)
+	case "ListAgentActionGroups":
+		s.listAgentComponents(w, resource, "actiongroups")
+	case "ListAgentKnowledgeBases":
+		s.listAgentComponents(w, resource, "knowledgebases")
+	case "ListTagsForResource", "TagResource", "UntagResource":
+		s.serveTags(w, r, resource, body)
+	default:
+		writeError(w, http.StatusNotFound, "UnknownOperationException", r.Method+" "+r.URL.Path)
+	}
+}
+
+// operationOf returns the name of the operation of a request, and the agent
+// ID or resource ARN it is about. The name is empty for unknown operations.
+func operationOf(r *http.Request) (string, string) {
+	if resourceARN, ok := strings.CutPrefix(r.URL.Path, "/tags/"); ok {
+		return map[string]string{
+			http.MethodGet:    "ListTagsForResource",
+			http.MethodPost:   "TagResource",
+			http.MethodDelete: "UntagResource",
+		}[r.Method], resourceARN
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "agents":
+		return map[string]string{
+			http.MethodPut:  "CreateAgent",
+			http.MethodPost: "ListAgents",
+		}[r.Method], ""
+	case len(parts) == 2 && parts[0] == "agents":
+		return map[string]string{
+			http.MethodGet:    "GetAgent",
+			http.MethodPut:    "UpdateAgent",
+			http.MethodPost:   "PrepareAgent",
+			http.MethodDelete: "DeleteAgent",
+		}[r.Method], parts[1]
+	case len(parts) == 5 && parts[0] == "agents" && parts[2] == "agentversions" && r.Method == http.MethodPost:
+		return map[string]string{
+			"actiongroups":   "ListAgentActionGroups",
+			"knowledgebases": "ListAgentKnowledgeBases",
+		}[parts[4]], parts[1]
+	}
+	return "", ""
+}
+
+// Replay makes the server answer every call of the operation with the
+// given response body instead of its own. The agent of the response, if
+// any, is stored, so that the following operations find it. It is meant for
+// tests comparing the controller's output to recorded responses.
+func (s *Server) Replay(operation string, response []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replays[operation] = response
+}
+
+// Request returns the body of the last request of the operation.
+func (s *Server) Request(operation string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[operation]
+}
+
+func (s *Server) replay(w http.ResponseWriter, operation string, response []byte) {
+	s.calls = append(s.calls, operation)
+	var body struct {
+		Agent map[string]any `json:"agent"`
+	}
+	if err := json.Unmarshal(response, &body); err == nil && body.Agent != nil {
+		if agentID, ok := body.Agent["agentId"].(string); ok {
+			s.agents[agentID] = copyFields(body.Agent)
+			s.observed[agentID] = true
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(response)
+}
+
+func (s *Server) createAgent(w http.ResponseWriter, body map[string]any) {
+	s.calls = append(s.calls, "CreateAgent")
+	name, _ := body["agentName"].(string)
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "ValidationException", "agentName is required")
+		return
+	}
+	for _, agent := range s.agents {
+		if agent["agentName"] == name {
+			writeError(w, http.StatusConflict, "ConflictException", fmt.Sprintf("agent %s already exists", name))
+			return
+		}
+	}
+
+	s.nextID++
+	agentID := fmt.Sprintf("AGENT%05d", s.nextID)
+	agentARN := fmt.Sprintf("arn:aws:bedrock:%s:%s:agent/%s", Region, AccountID, agentID)
+	now := timestamp()
+	agent := map[string]any{
+		"agentArn":     agentARN,
+		"agentId":      agentID,
+		"agentStatus":  StatusCreating,
+		"agentVersion": draftVersion,
+		"createdAt":    now,
+		"updatedAt":    now,
+	}
+	setAgentFields(agent, body)
+	s.agents[agentID] = agent
+
+	if tags, ok := body["tags"].(map[string]any); ok {
+		s.tags[agentARN] = map[string]string{}
+		for key, value := range tags {
+			s.tags[agentARN][key] = fmt.Sprint(value)
+		}
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"agent": copyFields(agent)})
+}
+
+func (s *Server) getAgent(w http.ResponseWriter, agentID string) {
+	s.calls = append(s.calls, "GetAgent")
+	agent, ok := s.agents[agentID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("agent %s not found", agentID))
+		return
+	}
+
+	// Complete the asynchronous operation whose status was already
+	// returned, so that this read observes its outcome.
+	if s.observed[agentID] {
+		switch agent["agentStatus"] {
+		case StatusCreating:
+			s.setStatus(agentID, agent, StatusNotPrepared)
+			if _, ok := agent["promptOverrideConfiguration"]; !ok {
+				agent["promptOverrideConfiguration"] = defaultPromptOverrideConfiguration()
+			}
+		case StatusPreparing:
+			s.setStatus(agentID, agent, StatusPrepared)
+			agent["preparedAt"] = timestamp()
+		case StatusDeleting:
+			delete(s.agents, agentID)
+			delete(s.tags, agent["agentArn"].(string))
+			delete(s.observed, agentID)
+			writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("agent %s not found", agentID))
+			return
+		}
+	}
+	s.observed[agentID] = true
+	writeJSON(w, http.StatusOK, map[string]any{"agent": copyFields(agent)})
+}
+
+// listAgents returns the summaries of every agent in a single page.
+func (s *Server) listAgents(w http.ResponseWriter) {
+	s.calls = append(s.calls, "ListAgents")
+	summaries := []map[string]any{}
+	for agentID, agent := range s.agents {
+		summaries = append(summaries, map[string]any{
+			"agentId":     agentID,
+			"agentName":   agent["agentName"],
+			"agentStatus": agent["agentStatus"],
+			"updatedAt":   agent["updatedAt"],
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"agentSummaries": summaries})
+}
+
+func (s *Server) updateAgent(w http.ResponseWriter, agentID string, body map[string]any) {
+	s.calls = append(s.calls, "UpdateAgent")
+	agent, ok := s.agents[agentID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("agent %s not found", agentID))
+		return
+	}
+	if status := agent["agentStatus"]; status == StatusCreating || status == StatusPreparing || status == StatusDeleting {
+		writeError(w, http.StatusConflict, "ConflictException", fmt.Sprintf("agent %s is %s", agentID, status))
+		return
+	}
+	// UpdateAgent replaces every field, so omitted fields are cleared.
+	for key := range agent {
+		if !isSystemField(key) {
+			delete(agent, key)
+		}
+	}
+	setAgentFields(agent, body)
+	s.setStatus(agentID, agent, StatusNotPrepared)
+	agent["updatedAt"] = timestamp()
+	writeJSON(w, http.StatusAccepted, map[string]any{"agent": copyFields(agent)})
+}
+
+func (s *Server) prepareAgent(w http.ResponseWriter, agentID string) {
+	s.calls = append(s.calls, "PrepareAgent")
+	agent, ok := s.agents[agentID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("agent %s not found", agentID))
+		return
+	}
+	if status := agent["agentStatus"]; status != StatusNotPrepared && status != StatusPrepared && status != StatusFailed {
+		writeError(w, http.StatusConflict, "ConflictException", fmt.Sprintf("agent %s is %s", agentID, status))
+		return
+	}
+	s.setStatus(agentID, agent, StatusPreparing)
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"agentId":      agentID,
+		"agentStatus":  StatusPreparing,
+		"agentVersion": draftVersion,
+		"preparedAt":   timestamp(),
+	})
+}
+
+func (s *Server) deleteAgent(w http.ResponseWriter, agentID string) {
+	s.calls = append(s.calls, "DeleteAgent")
+	agent, ok := s.agents[agentID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("agent %s not found", agentID))
+		return
+	}
+	s.setStatus(agentID, agent, StatusDeleting)
+	writeJSON(w, http.StatusAccepted, map[string]any{
+		"agentId":     agentID,
+		"agentStatus": StatusDeleting,
+	})
+}
+
+// setStatus sets the status of an agent, which GetAgent hasn't returned yet.
+func (s *Server) setStatus(agentID string, agent map[string]any, status string) {
+	agent["agentStatus"] = status
+	delete(s.observed, agentID)
+}
+
+func (s *Server) listAgentComponents(w http.ResponseWriter, agentID string, component string) {
+	if _, ok := s.agents[agentID]; !ok {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("agent %s not found", agentID))
+		return
+	}
+	switch component {
+	case "actiongroups":
+		s.calls = append(s.calls, "ListAgentActionGroups")
+		writeJSON(w, http.StatusOK, map[string]any{"actionGroupSummaries": []any{}})
+	case "knowledgebases":
+		s.calls = append(s.calls, "ListAgentKnowledgeBases")
+		writeJSON(w, http.StatusOK, map[string]any{"agentKnowledgeBaseSummaries": []any{}})
+	default:
+		writeError(w, http.StatusNotFound, "UnknownOperationException", component)
+	}
+}
+
+func (s *Server) serveTags(w http.ResponseWriter, r *http.Request, resourceARN string, body map[string]any) {
+	if !s.resourceExists(resourceARN) {
+		writeError(w, http.StatusNotFound, "ResourceNotFoundException", fmt.Sprintf("resource %s not found", resourceARN))
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		s.calls = append(s.calls, "ListTagsForResource")
+		tags := map[string]string{}
+		for key, value := range s.tags[resourceARN] {
+			tags[key] = value
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"tags": tags})
+	case http.MethodPost:
+		s.calls = append(s.calls, "TagResource")
+		tags, _ := body["tags"].(map[string]any)
+		if s.tags[resourceARN] == nil {
+			s.tags[resourceARN] = map[string]string{}
+		}
+		for key, value := range tags {
+			s.tags[resourceARN][key] = fmt.Sprint(value)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+	case http.MethodDelete:
+		s.calls = append(s.calls, "UntagResource")
+		for _, key := range r.URL.Query()["tagKeys"] {
+			delete(s.tags[resourceARN], key)
+		}
+		writeJSON(w, http.StatusOK, map[string]any{})
+	default:
+		writeError(w, http.StatusNotFound, "UnknownOperationException", r.Method+" "+r.URL.Path)
+	}
+}
+
+func (s *Server) resourceExists(resourceARN string) bool {
+	for _, agent := range s.agents {
+		if agent["agentArn"] == resourceARN {
+			return true
+		}
+	}
+	return false
+}
+
+// setAgentFields copies the agent fields of a CreateAgent or UpdateAgent
+// request body into the agent, applying the service-side defaults.
+func setAgentFields(agent map[string]any, body map[string]any) {
+	for key, value := range body {
+		switch key {
+		case "tags", "clientToken", "agentId":
+			continue
+		}
+		agent[key] = value
+	}
+	if _, ok := agent["idleSessionTTLInSeconds"]; !ok {
+		agent["idleSessionTTLInSeconds"] = 600
+	}
+	if _, ok := agent["orchestrationType"]; !ok {
+		agent["orchestrationType"] = "DEFAULT"
+	}
+	if _, ok := agent["agentCollaboration"]; !ok {
+		agent["agentCollaboration"] = "DISABLED"
+	}
+}
+
+// defaultPromptTypes are the prompt types whose default templates are
+// returned in the prompt override configuration of new agents.
+var defaultPromptTypes = []string{
+	"PRE_PROCESSING",
+	"ORCHESTRATION",
+	"POST_PROCESSING",
+	"KNOWLEDGE_BASE_RESPONSE_GENERATION",
+}
+
+// defaultPromptOverrideConfiguration returns the prompt override
+// configuration of an agent that doesn't override any prompt.
+func defaultPromptOverrideConfiguration() map[string]any {
+	var configurations []any
+	for _, promptType := range defaultPromptTypes {
+		configurations = append(configurations, map[string]any{
+			"promptType":         promptType,
+			"promptCreationMode": "DEFAULT",
+			"promptState":        "ENABLED",
+			"parserMode":         "DEFAULT",
+			"basePromptTemplate": fmt.Sprintf("Default %s prompt template.", strings.ToLower(promptType)),
+			"inferenceConfiguration": map[string]any{
+				"maximumLength": 2048,
+				"stopSequences": []any{"</answer>"},
+				"temperature":   0,
+				"topK":          250,
+				"topP":          1,
+			},
+		})
+	}
+	return map[string]any{"promptConfigurations": configurations}
+}
+
+func isSystemField(key string) bool {
+	for _, field := range systemFields {
+		if field == key {
+			return true
+		}
+	}
+	return false
+}
+
+// copyFields returns a deep copy of the agent so that responses aren't
+// affected by later changes.
+func copyFields(agent map[string]any) map[string]any {
+	data, _ := json.Marshal(agent)
+	out := map[string]any{}
+	_ = json.Unmarshal(data, &out)
+	return out
+}
+
+func timestamp() string {
+	return time.Now().UTC().Format(time.RFC3339Nano)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Amzn-ErrorType", code)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
@@ -0,0 +1,198 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tracing provides the OpenTelemetry spans of the controller: spans
+// for the operations of the resource managers, and a span for each AWS SDK
+// call. The controller installs the exporter configured by its --tracing-*
+// flags at startup, see Setup; spans are dropped when none is.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the tracer creating the spans of the controller.
+const TracerName = "github.com/aws-controllers-k8s/bedrockagent-controller"
+
+// Options configures the export of the spans.
+type Options struct {
+	// Endpoint is the host:port of the OTLP gRPC collector. Tracing is
+	// disabled when it is empty.
+	Endpoint string
+	// Insecure disables TLS on the connection to the collector.
+	Insecure bool
+	// SampleRatio is the fraction of the traces sampled, between 0 and 1.
+	SampleRatio float64
+	// ServiceName and ServiceVersion identify the controller in the traces.
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Setup installs a global TracerProvider exporting the spans over OTLP, and
+// returns the function flushing and stopping it. It does nothing when the
+// endpoint is empty.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	if opts.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if opts.SampleRatio < 0 || opts.SampleRatio > 1 {
+		return nil, fmt.Errorf("invalid trace sample ratio %v, must be between 0 and 1", opts.SampleRatio)
+	}
+	exporterOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating the OTLP trace exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+		semconv.ServiceVersion(opts.ServiceVersion),
+	))
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the controller, from the global
+// TracerProvider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// Start starts a span, child of the span in ctx.
+func Start(
+	ctx context.Context,
+	name string,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, unless it is one of the ignored errors, and
+// ends the span. Ignored errors are expected outcomes, like a resource that
+// isn't found.
+func End(span trace.Span, err error, ignored ...error) {
+	if err != nil {
+		for _, target := range ignored {
+			if errors.Is(err, target) {
+				span.SetAttributes(attribute.String("error.outcome", err.Error()))
+				span.End()
+				return
+			}
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type attributesKey struct{}
+
+// ContextWithAttributes returns a copy of ctx whose SDK call spans carry the
+// given attributes, in addition to those already in ctx.
+func ContextWithAttributes(ctx context.Context, attrs ...attribute.KeyValue) context.Context {
+	existing := AttributesFromContext(ctx)
+	merged := make([]attribute.KeyValue, 0, len(existing)+len(attrs))
+	merged = append(append(merged, existing...), attrs...)
+	return context.WithValue(ctx, attributesKey{}, merged)
+}
+
+// AttributesFromContext returns the attributes set with ContextWithAttributes.
+func AttributesFromContext(ctx context.Context) []attribute.KeyValue {
+	attrs, _ := ctx.Value(attributesKey{}).([]attribute.KeyValue)
+	return attrs
+}
+
+// StartSDKCall starts the span of a call of the operation of an AWS service,
+// with the attributes of the context. It is meant for the calls made with a
+// client without the AddSDKSpans middleware, which ends the span with
+// EndSDKCall.
+func StartSDKCall(ctx context.Context, service, operation string) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, service+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "aws-api"),
+			semconv.RPCService(service),
+			semconv.RPCMethod(operation),
+		),
+		trace.WithAttributes(AttributesFromContext(ctx)...),
+	)
+}
+
+// EndSDKCall ends the span of a call started with StartSDKCall, adding the
+// request ID found in the metadata of the response or in the error.
+func EndSDKCall(span trace.Span, metadata middleware.Metadata, err error) {
+	requestID, ok := awsmiddleware.GetRequestIDMetadata(metadata)
+	var responseErr interface{ ServiceRequestID() string }
+	if !ok && errors.As(err, &responseErr) {
+		requestID, ok = responseErr.ServiceRequestID(), true
+	}
+	if ok && requestID != "" {
+		span.SetAttributes(semconv.AWSRequestID(requestID))
+	}
+	End(span, err)
+}
+
+// sdkSpanMiddlewareID identifies the middleware of the SDK call spans.
+const sdkSpanMiddlewareID = "ACKTracingSpan"
+
+// AddSDKSpans adds the middleware creating a span for each call of an AWS SDK
+// client, with the request ID and the attributes of the context. It is meant
+// for the APIOptions of the client:
+//
+//	svcsdk.NewFromConfig(cfg, func(o *svcsdk.Options) {
+//		o.APIOptions = append(o.APIOptions, tracing.AddSDKSpans)
+//	})
+func AddSDKSpans(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(
+		sdkSpanMiddlewareID,
+		func(
+			ctx context.Context,
+			in middleware.InitializeInput,
+			next middleware.InitializeHandler,
+		) (middleware.InitializeOutput, middleware.Metadata, error) {
+			ctx, span := StartSDKCall(ctx, awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx))
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			EndSDKCall(span, metadata, err)
+			return out, metadata, err
+		},
+	), middleware.After)
+}
@@ -0,0 +1,96 @@
+// Copyright Amazon.com Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may
+// not use this file except in compliance with the License. A copy of the
+// License is located at
+//
+//     http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either
+// express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSetup(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{name: "disabled", opts: Options{}},
+		{name: "disabled ignores the ratio", opts: Options{SampleRatio: 2}},
+		{name: "negative ratio", opts: Options{Endpoint: "localhost:4317", SampleRatio: -0.5}, wantErr: true},
+		{name: "ratio above 1", opts: Options{Endpoint: "localhost:4317", SampleRatio: 1.5}, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			shutdown, err := Setup(context.Background(), tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Setup() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err == nil {
+				if err := shutdown(context.Background()); err != nil {
+					t.Errorf("shutdown() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	errNotFound := errors.New("not found")
+	for _, tc := range []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{name: "success", want: codes.Unset},
+		{name: "error", err: errors.New("throttled"), want: codes.Error},
+		{name: "ignored error", err: errNotFound, want: codes.Unset},
+		{name: "wrapped ignored error", err: errors.Join(errNotFound), want: codes.Unset},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			_, span := provider.Tracer(TracerName).Start(context.Background(), "op")
+			End(span, tc.err, errNotFound)
+			spans := exporter.GetSpans()
+			if len(spans) != 1 {
+				t.Fatalf("%d spans, want 1 ended span", len(spans))
+			}
+			if got := spans[0].Status.Code; got != tc.want {
+				t.Errorf("status = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestContextWithAttributes(t *testing.T) {
+	ctx := ContextWithAttributes(context.Background(), attribute.String("a", "1"))
+	ctx = ContextWithAttributes(ctx, attribute.String("b", "2"))
+	got := AttributesFromContext(ctx)
+	want := []attribute.KeyValue{attribute.String("a", "1"), attribute.String("b", "2")}
+	if len(got) != len(want) {
+		t.Fatalf("attributes = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("attribute %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if attrs := AttributesFromContext(context.Background()); attrs != nil {
+		t.Errorf("attributes of an empty context = %v, want none", attrs)
+	}
+}
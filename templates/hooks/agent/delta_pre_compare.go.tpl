@@ -1,5 +1,13 @@
-    // Hack to ensure that reconcile loop triggers update for PrepareAgent call
+	// A rolled back agent is compared with the spec of the revision it
+	// names, see revisions.go.
+	a = compareRollback(delta, a)
+	// A key set on one side only isn't compared, see agentclass.go.
+	a, b = keepEncryptionKey(a, b)
+
+	// Hack to ensure that reconcile loop triggers update for PrepareAgent call
 	// if AgentStatus is not in PREPARED state.
 	compareAgentStatus(delta, b.ko.Status.AgentStatus)
 
-	comparePropertyOverrideConfiguration(delta, a, b)
\ No newline at end of file
+	comparePropertyOverrideConfiguration(delta, a, b)
+	compareActionGroups(delta, a, b)
+	compareKnowledgeBases(delta, a, b)
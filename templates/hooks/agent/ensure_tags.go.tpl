@@ -0,0 +1,20 @@
+	r := rm.concreteResource(res)
+	if r.ko == nil {
+		// Should never happen... if it does, it's buggy code.
+		panic("resource manager's EnsureTags method received resource with nil CR object")
+	}
+	// The tags of an observed agent are mirrored, not ensured, see
+	// observe.go.
+	if isObserveOnly(r) {
+		return nil
+	}
+	defaultTags := ackrt.GetDefaultTags(&rm.cfg, r.ko, md)
+	// The inherited, owner cluster and retained tags, see hooks.go.
+	existingTags, err := rm.controllerTags(ctx, r, md)
+	if err != nil {
+		return err
+	}
+	resourceTags, keyOrder := convertToOrderedACKTags(existingTags)
+	tags := acktags.Merge(resourceTags, defaultTags)
+	r.ko.Spec.Tags = fromACKTags(tags, keyOrder)
+	return nil
@@ -0,0 +1,11 @@
+	r := rm.concreteResource(res)
+	if r == nil || r.ko == nil {
+		return
+	}
+	var existingTags map[string]*string
+	existingTags = r.ko.Spec.Tags
+	resourceTags, tagKeyOrder := convertToOrderedACKTags(existingTags)
+	ignoreSystemTags(resourceTags, systemTags)
+	// The tags added by EnsureTags besides the --resource-tags, see hooks.go.
+	ignoreControllerTags(resourceTags, r)
+	r.ko.Spec.Tags = fromACKTags(resourceTags, tagKeyOrder)
@@ -0,0 +1,5 @@
+	// The AgentClass, span and Events of the resolution wrap the generated
+	// code below, see resolve.go.
+	if !isResolvingReferences(ctx) {
+		return rm.resolveAgentReferences(ctx, apiReader, res)
+	}
@@ -0,0 +1,3 @@
+	// The generated client has no span middleware, see tracing.go.
+	agentCtx := ctx
+	ctx, sdkSpan := startSDKSpan(ctx, "CreateAgent")
@@ -0,0 +1,2 @@
+	endSDKSpan(sdkSpan, resp, err)
+	ctx = agentCtx
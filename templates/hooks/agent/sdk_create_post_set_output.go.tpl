@@ -0,0 +1,3 @@
+	recordSpecChange(ko)
+	recordAgentStatus(ko)
+	recordAgentStatusEvent(desired.ko.Status.AgentStatus, ko)
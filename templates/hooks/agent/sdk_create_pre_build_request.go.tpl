@@ -0,0 +1,29 @@
+	// An observed agent is adopted, never created, see observe.go.
+	if isObserveOnly(desired) {
+		return observedCreate(desired)
+	}
+	// A paused agent isn't created, see pause.go.
+	if isPaused(desired) {
+		return pausedWrite(desired, "created")
+	}
+
+	// An agent with a rollback annotation is created with the spec of the
+	// revision it names, see revisions.go, but keeps its own spec.
+	rolledBack, err := rm.rollBack(desired, desired)
+	if err != nil {
+		return rolledBack, err
+	}
+	if rolledBack != desired {
+		defer keepDeclaredSpec(&created, desired.ko.Spec)
+		desired = rolledBack
+	}
+
+	// An agent in dry-run mode gets the plan of its creation, see plan.go.
+	if isDryRun(desired) {
+		return planCreate(desired)
+	}
+
+	ctx, span := startAgentSpan(ctx, "Agent.Create", desired.ko)
+	defer func() {
+		endAgentSpan(span, created, err)
+	}()
@@ -0,0 +1,2 @@
+	// The generated client has no span middleware, see tracing.go.
+	ctx, sdkSpan := startSDKSpan(ctx, "DeleteAgent")
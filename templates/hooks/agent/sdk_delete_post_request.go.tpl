@@ -0,0 +1,4 @@
+	endSDKSpan(sdkSpan, resp, err)
+	if err == nil {
+		forgetAgentMetrics(r.ko)
+	}
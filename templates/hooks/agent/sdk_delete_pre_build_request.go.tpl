@@ -0,0 +1,21 @@
+	// An observed agent is left to its owner, see observe.go, a paused agent
+	// isn't deleted, see pause.go, an agent in dry-run mode only gets the plan
+	// of its deletion, see plan.go, and an agent owned by another cluster is
+	// left to it, see ownership.go.
+	if isObserveOnly(r) {
+		return observedDelete(r), nil
+	}
+	if isPaused(r) {
+		return pausedWrite(r, "deleted")
+	}
+	if isDryRun(r) {
+		return planDelete(r)
+	}
+	if foreignDelete(r) {
+		return r, nil
+	}
+
+	ctx, span := startAgentSpan(ctx, "Agent.Delete", r.ko)
+	defer func() {
+		endAgentSpan(span, latest, err)
+	}()
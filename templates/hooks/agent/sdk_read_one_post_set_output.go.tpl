@@ -1,4 +1,29 @@
-    ko.Spec.Tags, err = rm.getTags(ctx, string(*ko.Status.ACKResourceMetadata.ARN))
-    if err != nil {
-        return nil, err
-    }
\ No newline at end of file
+	ko.Spec.Tags, err = rm.getTags(ctx, &resource{ko})
+	if err != nil {
+		return nil, err
+	}
+	if ko.Spec.ActionGroups != nil {
+		ko.Spec.ActionGroups, err = getActionGroups(ctx, rm.tracedSDKAPI(), rm.metrics, *ko.Status.AgentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if ko.Spec.KnowledgeBases != nil {
+		ko.Spec.KnowledgeBases, err = getKnowledgeBases(ctx, rm.tracedSDKAPI(), rm.metrics, *ko.Status.AgentID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	recordAgentStatus(ko)
+	recordAgentStatusEvent(r.ko.Status.AgentStatus, ko)
+	observed := &resource{ko}
+	mirrorOwnerTag(r, observed)
+	setOwnerStatus(observed)
+	// An observed agent is only mirrored, see observe.go.
+	if isObserveOnly(r) {
+		setObserveOnlyStatus(observed)
+		return observed, nil
+	}
+	setDryRunPlan(r, observed)
+	recordRevision(r, observed)
+	setPauseStatus(r, observed)
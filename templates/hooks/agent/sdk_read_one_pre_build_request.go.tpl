@@ -0,0 +1,11 @@
+	ctx, span := startAgentSpan(ctx, "Agent.ReadOne", r.ko)
+	defer func() {
+		endAgentSpan(span, latest, err)
+	}()
+	// An observed agent is read by the ID of the agent it adopts, see
+	// observe.go.
+	if isObserveOnly(r) {
+		if r, err = rm.adoptObservedAgent(ctx, r); err != nil {
+			return nil, err
+		}
+	}
@@ -0,0 +1,6 @@
+	// UpdateAgent moves the agent back to NOT_PREPARED, so the components are
+	// synced and the agent prepared once all changes have been made.
+	err = rm.syncAgentComponents(ctx, desired, latest, delta, true, ko.Status.AgentStatus)
+	if err != nil {
+		return &resource{ko}, err
+	}
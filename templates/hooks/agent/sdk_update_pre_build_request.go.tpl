@@ -1,12 +1,52 @@
-    // If AgentStatus is not in PREPARED state we need to call PrepareAgent to finalize setup
-    // of Agent. Uses hack (see delta.go) to trigger update from non-existent Spec.AgentStatus
-    if delta.DifferentAt("Spec.AgentStatus") {
-		err = prepareAgent(ctx, rm.sdkapi, rm.metrics, *desired.ko.Status.AgentID)
-		if err != nil {
-			return nil, err
-		}
+	// The spec of an observed agent is mirrored from AWS instead of applied,
+	// see observe.go.
+	if isObserveOnly(desired) {
+		return observedUpdate(latest), nil
+	}
+	// A paused agent isn't updated, see pause.go.
+	if isPaused(desired) {
+		return pausedUpdate(desired, latest), nil
+	}
+	// Changes to the immutable fields fail the update, see validation.go.
+	if err := checkImmutableFieldChanges(delta); err != nil {
+		return nil, err
 	}
 
+	// An agent with a rollback annotation is updated to the spec of the
+	// revision it names, which the delta already compares, see
+	// revisions.go, but keeps its own spec.
+	rolledBack, err := rm.rollBack(desired, latest)
+	if err != nil {
+		return rolledBack, err
+	}
+	if rolledBack != desired {
+		defer keepDeclaredSpec(&updated, desired.ko.Spec)
+		desired = rolledBack
+	}
+
+	// An agent in dry-run mode only gets the plan written by ReadOne, see
+	// plan.go.
+	if isDryRun(desired) {
+		return planUpdate(desired, latest)
+	}
+	// An agent owned by another cluster isn't updated, see ownership.go.
+	if conflict, ok := ownershipConflict(desired, latest); ok {
+		return conflict, nil
+	}
+	// Changes waiting for an approval aren't applied, see approval.go.
+	if awaiting, ok := awaitApproval(desired, latest, delta); ok {
+		return awaiting, nil
+	}
+
+	ctx, span := startAgentSpan(ctx, "Agent.Update", latest.ko)
+	defer func() {
+		endAgentSpan(span, updated, err)
+	}()
+
+	// UpdateAgent is sent the key of the agent, which its desired spec may
+	// leave unset, see agentclass.go.
+	desired, _ = keepEncryptionKey(desired, latest)
+
 	if delta.DifferentAt("Spec.Tags") {
 		err := rm.syncTags(
 			ctx,
@@ -18,7 +58,18 @@
 		}
 	}
 
-	if !delta.DifferentExcept("Spec.AgentStatus", "Spec.Tags") {
+	// Changes that prepare the agent again wait for its maintenance window,
+	// see maintenance.go.
+	if deferred, ok, err := deferChanges(desired, latest, delta); err != nil || ok {
+		return deferred, err
+	}
+
+	// Action groups, knowledge bases and the PREPARED state (see delta.go)
+	// are reconciled without calling UpdateAgent.
+	if !delta.DifferentExcept("Spec.AgentStatus", "Spec.Tags", "Spec.ActionGroups", "Spec.KnowledgeBases") {
+		err = rm.syncAgentComponents(ctx, desired, latest, delta, false, latest.ko.Status.AgentStatus)
+		if err != nil {
+			return nil, err
+		}
 		return desired, nil
 	}
-	
\ No newline at end of file